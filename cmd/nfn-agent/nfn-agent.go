@@ -32,37 +32,76 @@ import (
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/auth"
 	cs "github.com/akraino-edge-stack/icn-nodus/internal/pkg/cniserver"
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/criclient"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/kube"
 	pb "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify/proto"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/nodestate"
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
 	chaining "github.com/akraino-edge-stack/icn-nodus/internal/pkg/utils"
+	"github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	kexec "k8s.io/utils/exec"
 
 	log "k8s.io/klog"
 
 	"github.com/akraino-edge-stack/icn-nodus/cmd/ovn4nfvk8s-cni/app"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// subscribeInitialBackoff is the delay before the first reconnection to the nfn-operator
+	subscribeInitialBackoff = time.Second
+	// subscribeMaxBackoff caps the delay between reconnections
+	subscribeMaxBackoff = 30 * time.Second
+	// nodeCertRefreshInterval is the interval the certificate of the agent is fetched again at
+	nodeCertRefreshInterval = time.Hour
+)
+
 var errorChannel chan string
 var inSync bool
 var pnCreateStore []*pb.Notification_ProviderNwCreate
 
+// stateStore keeps the desired state of the node across restarts of the agent
+var stateStore *nodestate.Store
+
+// lastSeq is the sequence number of the last notification handled, notifications sent
+// again by the server after a reconnect are only acknowledged
+var lastSeq uint64
+
 // subscribe Notifications
 func subscribeNotif(client pb.NfnNotifyClient, criclient criclient.CRIClient) error {
 	log.Info("Subscribe Notification from server")
 	ctx := context.Background()
 	var n pb.SubscribeContext
 	n.NodeName = os.Getenv("NFN_NODE_NAME")
+	n.ProtocolVersion = pb.ProtocolVersion
+	n.Capabilities = pb.Capabilities
+	// Older operators don't implement the Ack call
+	ackSupported := true
+	backoff := subscribeInitialBackoff
 	for {
+		// The server replays the complete state of the node on every subscription and
+		// ends the replay with an InSync message
+		agentLock.Lock()
+		inSync = false
+		pnCreateStore = nil
+		desired.replayRoutes = make(map[string]*containerRoutes)
+		agentLock.Unlock()
+
 		stream, err := client.Subscribe(ctx, &n, grpc.WaitForReady(true))
 		if err != nil {
 			log.Error(err, "Subscribe", "client", client, "status", status.Code(err))
+			backoff = waitBackoff(backoff)
 			continue
 		}
 		log.Info("Subscribe Notification success")
@@ -71,34 +110,64 @@ func subscribeNotif(client pb.NfnNotifyClient, criclient criclient.CRIClient) er
 			in, err := stream.Recv()
 			if err == io.EOF {
 				// read done.
-				shutDownAgent("Stream closed")
-				return err
+				log.Info("Stream closed")
+				break
 			}
 			if err != nil {
-				log.Error(err, "Stream closed from server")
-				shutDownAgent("Stream closed from server")
-				return err
+				log.Error(err, "Stream closed from server", "status", status.Code(err))
+				break
+			}
+			backoff = subscribeInitialBackoff
+
+			if in.GetSeq() == 0 || in.GetSeq() > lastSeq {
+				agentLock.Lock()
+				handleNotif(in, criclient)
+				desired.save()
+				agentLock.Unlock()
+				lastSeq = in.GetSeq()
+			}
+			if in.GetSeq() != 0 && ackSupported {
+				if _, err := client.Ack(ctx, &pb.AckContext{NodeName: n.NodeName, Seq: in.GetSeq()}); err != nil {
+					if status.Code(err) == codes.Unimplemented {
+						log.Info("The nfn-operator doesn't support acknowledgements")
+						ackSupported = false
+					} else {
+						log.Error(err, "Failed to acknowledge the notification", "seq", in.GetSeq())
+					}
+				}
 			}
-
-			handleNotif(in, criclient)
 		}
+		backoff = waitBackoff(backoff)
 	}
 }
 
+// waitBackoff sleeps for the given delay and returns the next one
+func waitBackoff(backoff time.Duration) time.Duration {
+	log.Infof("Reconnecting to the nfn-operator in %v", backoff)
+	time.Sleep(backoff)
+	backoff *= 2
+	if backoff > subscribeMaxBackoff {
+		backoff = subscribeMaxBackoff
+	}
+	return backoff
+}
+
 func createVlanProvidernetwork(payload *pb.Notification_ProviderNwCreate) error {
 	var err error
 	vlanID := payload.ProviderNwCreate.GetVlan().GetVlanId()
 	ln := payload.ProviderNwCreate.GetVlan().GetLogicalIntf()
 	pn := payload.ProviderNwCreate.GetVlan().GetProviderIntf()
 	name := payload.ProviderNwCreate.GetProviderNwName()
-	if ln == "" {
-		ln = name + "." + vlanID
-	}
+	ln = ovn.GetVlanLogicalInterfaceName(name, vlanID, ln)
 	err = ovn.CreateVlan(vlanID, pn, ln)
 	if err != nil {
 		log.Error(err, "Unable to create VLAN", "vlan", ln)
 		return err
 	}
+	if !usesOvsBridge(payload) {
+		// The pods are attached with sub-interfaces of the VLAN interface
+		return nil
+	}
 	err = ovn.CreatePnBridge("nw_"+name, "br-"+name, ln)
 	if err != nil {
 		log.Error(err, "Unable to create vlan direct bridge", "vlan", pn)
@@ -111,6 +180,10 @@ func createDirectProvidernetwork(payload *pb.Notification_ProviderNwCreate) erro
 	var err error
 	pn := payload.ProviderNwCreate.GetDirect().GetProviderIntf()
 	name := payload.ProviderNwCreate.GetProviderNwName()
+	if !usesOvsBridge(payload) {
+		// The pods are attached with sub-interfaces of the provider interface
+		return nil
+	}
 	err = ovn.CreatePnBridge("nw_"+name, "br-"+name, pn)
 	if err != nil {
 		log.Error(err, "Unable to create direct bridge", "direct", pn)
@@ -119,6 +192,15 @@ func createDirectProvidernetwork(payload *pb.Notification_ProviderNwCreate) erro
 	return nil
 }
 
+// usesOvsBridge returns false when the pods are attached with macvlan or ipvlan sub-interfaces
+func usesOvsBridge(payload *pb.Notification_ProviderNwCreate) bool {
+	switch payload.ProviderNwCreate.GetAttachType() {
+	case v1alpha1.MacvlanAttachment, v1alpha1.IpvlanAttachment:
+		return false
+	}
+	return true
+}
+
 func deleteVlanProvidernetwork(payload *pb.Notification_ProviderNwRemove) {
 	ln := payload.ProviderNwRemove.GetVlanLogicalIntf()
 	name := payload.ProviderNwRemove.GetProviderNwName()
@@ -133,24 +215,20 @@ func deleteDirectProvidernetwork(payload *pb.Notification_ProviderNwRemove) {
 	ovn.DeletePnBridge("nw_"+name, "br-"+name)
 }
 
-func inSyncVlanProvidernetwork() {
+func inSyncVlanProvidernetwork(diffPnBridge map[string]bool) {
 	var err error
 	// Read config from node
 	vlanList := ovn.GetVlan()
 	pnBridgeList := ovn.GetPnBridge("nfn")
 	diffVlan := make(map[string]bool)
-	diffPnBridge := make(map[string]bool)
 VLAN:
 	for _, pn := range pnCreateStore {
-		if pn.ProviderNwCreate.GetVlan() != nil {
+		if pn.ProviderNwCreate.GetVlan() == nil {
 			continue
 		}
 		id := pn.ProviderNwCreate.GetVlan().GetVlanId()
-		ln := pn.ProviderNwCreate.GetVlan().GetLogicalIntf()
+		ln := ovn.GetVlanLogicalInterfaceName(pn.ProviderNwCreate.GetProviderNwName(), id, pn.ProviderNwCreate.GetVlan().GetLogicalIntf())
 		pn := pn.ProviderNwCreate.GetVlan().GetProviderIntf()
-		if ln == "" {
-			ln = pn + "." + id
-		}
 		for _, vlan := range vlanList {
 			if vlan == ln {
 				// VLAN already present
@@ -162,16 +240,16 @@ VLAN:
 		err = ovn.CreateVlan(id, pn, ln)
 		if err != nil {
 			log.Error(err, "Unable to create VLAN", "vlan", ln)
-			return
+			continue
 		}
 	}
 PRNETWORK:
 	for _, pn := range pnCreateStore {
-		if pn.ProviderNwCreate.GetVlan() != nil {
+		if pn.ProviderNwCreate.GetVlan() == nil {
 			continue
 		}
-		ln := pn.ProviderNwCreate.GetVlan().GetLogicalIntf()
 		name := pn.ProviderNwCreate.GetProviderNwName()
+		ln := ovn.GetVlanLogicalInterfaceName(name, pn.ProviderNwCreate.GetVlan().GetVlanId(), pn.ProviderNwCreate.GetVlan().GetLogicalIntf())
 		for _, br := range pnBridgeList {
 			pnName := strings.Replace(br, "br-", "", -1)
 			if name == pnName {
@@ -180,6 +258,9 @@ PRNETWORK:
 			}
 		}
 		// Provider Network not found
+		if !usesOvsBridge(pn) {
+			continue
+		}
 		ovn.CreatePnBridge("nw_"+name, "br-"+name, ln)
 	}
 	// Delete VLAN not in the list
@@ -188,22 +269,14 @@ PRNETWORK:
 			ovn.DeleteVlan(vlan)
 		}
 	}
-	// Delete Provider Bridge not in the list
-	for _, br := range pnBridgeList {
-		if diffPnBridge[br] == false {
-			name := strings.Replace(br, "br-", "", -1)
-			ovn.DeletePnBridge("nw_"+name, "br-"+name)
-		}
-	}
 }
 
-func inSyncDirectProvidernetwork() {
+func inSyncDirectProvidernetwork(diffPnBridge map[string]bool) {
 	// Read config from node
 	pnBridgeList := ovn.GetPnBridge("nfn")
-	diffPnBridge := make(map[string]bool)
 DIRECTPRNETWORK:
 	for _, pn := range pnCreateStore {
-		if pn.ProviderNwCreate.GetDirect() != nil {
+		if pn.ProviderNwCreate.GetDirect() == nil {
 			continue
 		}
 		pr := pn.ProviderNwCreate.GetDirect().GetProviderIntf()
@@ -216,10 +289,16 @@ DIRECTPRNETWORK:
 			}
 		}
 		// Provider Network not found
+		if !usesOvsBridge(pn) {
+			continue
+		}
 		ovn.CreatePnBridge("nw_"+name, "br-"+name, pr)
 	}
-	// Delete Provider Bridge not in the list
-	for _, br := range pnBridgeList {
+}
+
+// inSyncPnBridge deletes the provider network bridges of both VLAN and direct networks not in the list
+func inSyncPnBridge(diffPnBridge map[string]bool) {
+	for _, br := range ovn.GetPnBridge("nfn") {
 		if diffPnBridge[br] == false {
 			name := strings.Replace(br, "br-", "", -1)
 			ovn.DeletePnBridge("nw_"+name, "br-"+name)
@@ -241,16 +320,29 @@ func createNodeOVSInternalPort(payload *pb.Notification_InSync) error {
 	return nil
 }
 
+// removePnCreate removes the stored create messages of the provider network
+func removePnCreate(store []*pb.Notification_ProviderNwCreate, name string) []*pb.Notification_ProviderNwCreate {
+	kept := store[:0]
+	for _, pn := range store {
+		if pn.ProviderNwCreate.GetProviderNwName() != name {
+			kept = append(kept, pn)
+		}
+	}
+	return kept
+}
+
 func handleNotif(msg *pb.Notification, criclient criclient.CRIClient) {
 	switch msg.GetCniType() {
 	case "ovn4nfv":
 		switch payload := msg.Payload.(type) {
 		case *pb.Notification_ProviderNwCreate:
 			if !inSync {
-				// Store Msgs
+				// Store Msgs, a later message for the same network replaces the stored one
+				pnCreateStore = removePnCreate(pnCreateStore, payload.ProviderNwCreate.GetProviderNwName())
 				pnCreateStore = append(pnCreateStore, payload)
 				return
 			}
+			desired.setProviderNetwork(payload)
 			if payload.ProviderNwCreate.GetVlan() != nil {
 				err := createVlanProvidernetwork(payload)
 				if err != nil {
@@ -266,9 +358,11 @@ func handleNotif(msg *pb.Notification, criclient criclient.CRIClient) {
 			}
 		case *pb.Notification_ProviderNwRemove:
 			if !inSync {
-				// Unexpected Remove message
+				// The network was removed while the state was replayed
+				pnCreateStore = removePnCreate(pnCreateStore, payload.ProviderNwRemove.GetProviderNwName())
 				return
 			}
+			desired.removeProviderNetwork(payload.ProviderNwRemove.GetProviderNwName())
 
 			if payload.ProviderNwRemove.GetVlanLogicalIntf() != "" {
 				deleteVlanProvidernetwork(payload)
@@ -280,25 +374,27 @@ func handleNotif(msg *pb.Notification, criclient criclient.CRIClient) {
 
 		case *pb.Notification_ContainterRtInsert:
 			id := payload.ContainterRtInsert.GetContainerId()
-			pid, err := criclient.GetPidForContainer(id)
+			desired.addRoutes(id, payload.ContainterRtInsert.GetRoute())
+			netns, err := criclient.GetNetnsForContainer(id)
 			if err != nil {
-				log.Error(err, "Failed to get pid", "containerID", id)
+				log.Error(err, "Failed to get network namespace", "containerID", id)
 				return
 			}
-			err = chaining.ContainerAddRoute(pid, payload.ContainterRtInsert.GetRoute())
+			err = chaining.ContainerAddRoute(netns, payload.ContainterRtInsert.GetRoute())
 			if err != nil {
 				return
 			}
 
 		case *pb.Notification_PodAddNetwork:
 			id := payload.PodAddNetwork.GetContainerId()
-			pid, err := criclient.GetPidForContainer(id)
+			desired.addRoutes(id, payload.PodAddNetwork.GetRoute())
+			netns, err := criclient.GetNetnsForContainer(id)
 			if err != nil {
-				log.Error(err, "Failed to get pid", "containerID", id)
+				log.Error(err, "Failed to get network namespace", "containerID", id)
 				return
 			}
 
-			err = chaining.ContainerAddInteface(pid, payload.PodAddNetwork)
+			err = chaining.ContainerAddInteface(netns, payload.PodAddNetwork)
 			if err != nil {
 				log.Errorf("Failed to add interface for containerID-%v & podaddnetwork-%v | err-%v", id, payload.PodAddNetwork, err)
 				return
@@ -306,7 +402,7 @@ func handleNotif(msg *pb.Notification, criclient criclient.CRIClient) {
 
 			var route []*pb.RouteData
 			route = append(route, payload.PodAddNetwork.GetRoute()...)
-			err = chaining.ContainerAddRoute(pid, route)
+			err = chaining.ContainerAddRoute(netns, route)
 			if err != nil {
 				log.Errorf("Failed to add route for containerID-%v & route-%v | err-%v", id, route, err)
 				return
@@ -314,40 +410,48 @@ func handleNotif(msg *pb.Notification, criclient criclient.CRIClient) {
 
 		case *pb.Notification_ContainterRtRemove:
 			id := payload.ContainterRtRemove.GetContainerId()
-			pid, err := criclient.GetPidForContainer(id)
+			desired.delRoutes(id, payload.ContainterRtRemove.GetRoute())
+			netns, err := criclient.GetNetnsForContainer(id)
 			if err != nil {
-				log.Error(err, "Failed to get pid", "containerID", id)
+				log.Error(err, "Failed to get network namespace", "containerID", id)
 				return
 			}
-			err = chaining.ContainerDelRoute(pid, payload.ContainterRtRemove.GetRoute())
+			err = chaining.ContainerDelRoute(netns, payload.ContainterRtRemove.GetRoute())
 			if err != nil {
 				return
 			}
 
 		case *pb.Notification_PodDelNetwork:
 			id := payload.PodDelNetwork.GetContainerId()
-			pid, err := criclient.GetPidForContainer(id)
+			desired.delRoutes(id, payload.PodDelNetwork.GetRoute())
+			netns, err := criclient.GetNetnsForContainer(id)
 			if err != nil {
-				log.Error(err, "Failed to get pid", "containerID", id)
+				log.Error(err, "Failed to get network namespace", "containerID", id)
 				return
 			}
 
 			var route []*pb.RouteData
 			route = append(route, payload.PodDelNetwork.GetRoute()...)
 			log.Info("route information from msg", "route", route)
-			err = chaining.ContainerDelRoute(pid, route)
+			err = chaining.ContainerDelRoute(netns, route)
 			if err != nil {
 				return
 			}
 
-			err = chaining.ContainerDelInteface(pid, payload.PodDelNetwork)
+			err = chaining.ContainerDelInteface(netns, payload.PodDelNetwork)
 			if err != nil {
 				return
 			}
 
 		case *pb.Notification_InSync:
-			inSyncVlanProvidernetwork()
-			inSyncDirectProvidernetwork()
+			if v := payload.InSync.GetProtocolVersion(); v != pb.ProtocolVersion {
+				log.Infof("nfn-operator protocol version %d, nfn-agent protocol version %d", v, pb.ProtocolVersion)
+			}
+			diffPnBridge := make(map[string]bool)
+			inSyncVlanProvidernetwork(diffPnBridge)
+			inSyncDirectProvidernetwork(diffPnBridge)
+			inSyncPnBridge(diffPnBridge)
+			desired.sync(pnCreateStore, payload)
 			pnCreateStore = nil
 			inSync = true
 			if (payload.InSync.GetNodeIntfIpAddress() != "" || payload.InSync.GetNodeIntfIpv6Address() != "") && payload.InSync.GetNodeIntfMacAddress() != "" {
@@ -385,15 +489,6 @@ func shutdownHandler(errorChannel <-chan string) {
 	log.Info("nfn-agent is shutting down", "reason", reason)
 }
 
-func shutDownAgent(reason string) {
-	// Send a failure message and give few seconds complete shutdown.
-	log.Info("shutDownAgent recieved")
-	errorChannel <- reason
-	time.Sleep(10 * time.Second)
-	// The graceful shutdown failed, terminate the process.
-	panic("Shutdown failed. Panicking.")
-}
-
 func configureFirewall() error {
 	logrus.Info("Configuring firewall")
 
@@ -431,6 +526,7 @@ func main() {
 	}
 
 	serverAddr := serverIP + ":" + os.Getenv("NFN_OPERATOR_SERVICE_PORT")
+	certAddr := serverIP + ":" + os.Getenv(auth.NodeCertPortEnv)
 
 	// Setup ovn utilities
 	exec := kexec.New()
@@ -441,42 +537,23 @@ func main() {
 		return
 	}
 
-	namespace := os.Getenv(auth.NamespaceEnv)
-	nfnSvcIP := os.Getenv(auth.NfnOperatorHostEnv)
-
-	// obtain certifcates
-	kubecli, err := auth.GetKubeClient()
-	crt, err := auth.GetCert(namespace, auth.DefaultCert)
+	// restore the desired state of the node saved before the restart, the node is served
+	// from it until the nfn-operator is reachable
+	stateStore, err = nodestate.Open(nodestate.StateDir)
 	if err != nil {
-		log.Error(err, "Error while obtaining certificate")
-	}
-
-	// get secret associated with the certificate
-	var sec *kapi.Secret
-	if !auth.IsCertIPUpToDate(crt, nfnSvcIP) {
-		// update the IP address of the certificate if required
-		crt, sec, err = auth.UpdateCertIP(crt, nfnSvcIP)
+		log.Error(err, "Unable to open the node state store, the state is not persisted")
 	} else {
-		// wait for secret to be updated by cert-manager
-		sec, err = auth.WaitForSecretIP(kubecli, crt)
-	}
-	if err != nil {
-		log.Error(err, "Error while obtaining secret")
+		desired.restore(stateStore.Get())
 	}
 
-	// create TLS config using the obtained secret
-	clientTLS, err := auth.CreateClientTLSFromSecret(sec)
-	if err != nil {
-		log.Error(err, "Error while creating TLS configuration")
-	}
+	namespace := os.Getenv(auth.NamespaceEnv)
+	nodeName := os.Getenv("NFN_NODE_NAME")
 
-	conn, err := grpc.Dial(serverAddr, grpc.WithTransportCredentials(*clientTLS))
+	kubecli, err := auth.GetKubeClient()
 	if err != nil {
-		log.Error(err, "fail to dial")
+		log.Error(err, "Error while creating the kube client")
 		return
 	}
-	defer conn.Close()
-	client := pb.NewNfnNotifyClient(conn)
 	errorChannel = make(chan string)
 
 	// creates the in-cluster config
@@ -493,7 +570,7 @@ func main() {
 		return
 	}
 
-	node, err := kubecli.GetNode(os.Getenv("HOSTNAME"))
+	node, err := getNode(kubecli)
 	if err != nil {
 		log.Error(err, "failed to get node's data")
 		return
@@ -505,14 +582,102 @@ func main() {
 		return
 	}
 
-	cniserver := cs.NewCNIServer("", clientset)
+	cniserver := cs.NewCNIServer("", clientset, stateStore)
+	if cniserver == nil {
+		log.Error(fmt.Errorf("no CNI server"), "Unable to create cni server")
+		return
+	}
 	err = cniserver.Start(cs.HandleCNIcommandRequest)
 	if err != nil {
 		log.Error(err, "Unable to start cni server")
 		return
 	}
-	// Run client in background
-	go subscribeNotif(client, criclient)
+
+	// report the drift repaired by the agent as events of the node
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+	recorder := broadcaster.NewRecorder(scheme.Scheme, kapi.EventSource{Component: "nfn-agent", Host: node.Name})
+	runReconcile(criclient, recorder, node, clientset)
+
+	// Run client in background, the nfn-operator may not be reachable yet
+	go func() {
+		conn := dialOperator(kubecli, namespace, certAddr, nodeName, serverAddr)
+		defer conn.Close()
+		subscribeNotif(pb.NewNfnNotifyClient(conn), criclient)
+	}()
 	shutdownHandler(errorChannel)
 
 }
+
+// getNode returns the node of the agent, the node is built from the stored container
+// runtime if the Kubernetes API is unreachable
+func getNode(kubecli *kube.Kube) (*kapi.Node, error) {
+	node, err := kubecli.GetNode(os.Getenv("HOSTNAME"))
+	if err == nil {
+		if stateStore != nil {
+			version := node.Status.NodeInfo.ContainerRuntimeVersion
+			if err := stateStore.Update(func(state *nodestate.State) { state.ContainerRuntimeVersion = version }); err != nil {
+				log.Error(err, "Unable to save the container runtime")
+			}
+		}
+		return node, nil
+	}
+	if stateStore == nil || stateStore.Get().ContainerRuntimeVersion == "" {
+		return nil, err
+	}
+	log.Infof("Unable to get the node, using the stored container runtime: %v", err)
+	node = &kapi.Node{}
+	node.Name = os.Getenv("HOSTNAME")
+	node.Status.NodeInfo.ContainerRuntimeVersion = stateStore.Get().ContainerRuntimeVersion
+	return node, nil
+}
+
+// dialOperator obtains the certificate of the agent from the nfn-operator and connects to it,
+// it retries until the certificate is available
+func dialOperator(kubecli *kube.Kube, namespace, certAddr, nodeName, serverAddr string) *grpc.ClientConn {
+	backoff := subscribeInitialBackoff
+	for {
+		// the client certificate of the node identifies the agent to the nfn-operator
+		nodeSec, err := fetchNodeSecret(kubecli, namespace, certAddr, nodeName)
+		if err != nil {
+			log.Error(err, "Error while obtaining the node certificate")
+			backoff = waitBackoff(backoff)
+			continue
+		}
+
+		// create TLS config using the obtained secret, the certificate is fetched again before it expires
+		reloader, err := auth.NewCertReloader(nodeSec)
+		if err != nil {
+			log.Error(err, "Error while creating TLS configuration")
+			backoff = waitBackoff(backoff)
+			continue
+		}
+
+		conn, err := grpc.Dial(serverAddr, grpc.WithTransportCredentials(credentials.NewTLS(reloader.ClientTLSConfig())))
+		if err != nil {
+			log.Error(err, "fail to dial")
+			backoff = waitBackoff(backoff)
+			continue
+		}
+		go wait.Until(func() {
+			sec, err := fetchNodeSecret(kubecli, namespace, certAddr, nodeName)
+			if err == nil {
+				err = reloader.Update(sec)
+			}
+			if err != nil {
+				log.Error(err, "Error while renewing the node certificate")
+			}
+		}, nodeCertRefreshInterval, wait.NeverStop)
+		return conn
+	}
+}
+
+// fetchNodeSecret requests the certificate of the node from the nfn-operator, the nfn-operator
+// is verified with the CA bundle it publishes
+func fetchNodeSecret(kubecli *kube.Kube, namespace, certAddr, nodeName string) (*kapi.Secret, error) {
+	caBundle, err := auth.GetCABundle(kubecli, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return auth.FetchNodeSecret(certAddr, nodeName, caBundle)
+}
@@ -0,0 +1,441 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/config"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/criclient"
+	pb "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify/proto"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/nodestate"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
+	chaining "github.com/akraino-edge-stack/icn-nodus/internal/pkg/utils"
+
+	"github.com/vishvananda/netlink"
+	kapi "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	log "k8s.io/klog"
+)
+
+const (
+	// reconcileIntervalEnv is a name of env variable with the interval of the drift reconcile,
+	// 0 disables it
+	reconcileIntervalEnv = "NFN_RECONCILE_INTERVAL"
+	// defaultReconcileInterval is the interval of the drift reconcile if not configured
+	defaultReconcileInterval = time.Minute
+	// maxContainerMisses is the number of reconciles a container can't be found in before its
+	// routes are forgotten
+	maxContainerMisses = 3
+	// driftRepairedReason is the reason of the node events reporting the repaired drift
+	driftRepairedReason = "DriftRepaired"
+)
+
+// agentLock serializes the handling of the notifications and the drift reconcile
+var agentLock sync.Mutex
+
+// containerRoutes are the routes pushed to a container, by destination
+type containerRoutes struct {
+	routes map[string]*pb.RouteData
+	misses int
+}
+
+// desiredState is the state of the node requested by the nfn-operator, the drift reconcile
+// compares the node with it. It is guarded by agentLock.
+type desiredState struct {
+	providerNetworks map[string]*pb.Notification_ProviderNwCreate
+	nodeIntf         *pb.Notification_InSync
+	podRoutes        map[string]*containerRoutes
+	// replayRoutes are the routes received during the replay of a subscription
+	replayRoutes map[string]*containerRoutes
+	// valid is set once the state is synced with the nfn-operator or restored from the store
+	valid bool
+	// dirty is set when the state changed since it was saved in the store
+	dirty bool
+}
+
+var desired = desiredState{
+	providerNetworks: make(map[string]*pb.Notification_ProviderNwCreate),
+	podRoutes:        make(map[string]*containerRoutes),
+	replayRoutes:     make(map[string]*containerRoutes),
+}
+
+// routeStore returns the routes map updated by the notifications
+func (d *desiredState) routeStore() map[string]*containerRoutes {
+	if !inSync {
+		return d.replayRoutes
+	}
+	return d.podRoutes
+}
+
+// addRoutes records the routes pushed to the container
+func (d *desiredState) addRoutes(id string, routes []*pb.RouteData) {
+	store := d.routeStore()
+	cr, ok := store[id]
+	if !ok {
+		cr = &containerRoutes{routes: make(map[string]*pb.RouteData)}
+		store[id] = cr
+	}
+	for _, r := range routes {
+		cr.routes[r.GetDst()] = r
+	}
+	d.dirty = true
+}
+
+// delRoutes forgets the routes removed from the container
+func (d *desiredState) delRoutes(id string, routes []*pb.RouteData) {
+	for _, store := range []map[string]*containerRoutes{d.podRoutes, d.replayRoutes} {
+		cr, ok := store[id]
+		if !ok {
+			continue
+		}
+		for _, r := range routes {
+			delete(cr.routes, r.GetDst())
+		}
+		if len(cr.routes) == 0 {
+			delete(store, id)
+		}
+	}
+	d.dirty = true
+}
+
+// setProviderNetwork records the provider network created on the node
+func (d *desiredState) setProviderNetwork(payload *pb.Notification_ProviderNwCreate) {
+	d.providerNetworks[payload.ProviderNwCreate.GetProviderNwName()] = payload
+	d.dirty = true
+}
+
+// removeProviderNetwork forgets the provider network removed from the node
+func (d *desiredState) removeProviderNetwork(name string) {
+	delete(d.providerNetworks, name)
+	d.dirty = true
+}
+
+// sync takes the state replayed by the nfn-operator once the node is in sync. Operators
+// without a protocol version don't replay the pod routes, the known ones are kept then.
+func (d *desiredState) sync(store []*pb.Notification_ProviderNwCreate, payload *pb.Notification_InSync) {
+	d.providerNetworks = make(map[string]*pb.Notification_ProviderNwCreate)
+	for _, pn := range store {
+		d.providerNetworks[pn.ProviderNwCreate.GetProviderNwName()] = pn
+	}
+	if payload.InSync.GetProtocolVersion() > 0 {
+		d.podRoutes = d.replayRoutes
+	} else {
+		for id, cr := range d.replayRoutes {
+			d.podRoutes[id] = cr
+		}
+	}
+	d.replayRoutes = make(map[string]*containerRoutes)
+	if (payload.InSync.GetNodeIntfIpAddress() != "" || payload.InSync.GetNodeIntfIpv6Address() != "") && payload.InSync.GetNodeIntfMacAddress() != "" {
+		d.nodeIntf = payload
+	}
+	d.valid = true
+	d.dirty = true
+}
+
+// restore takes the state saved in the store by a previous run of the agent
+func (d *desiredState) restore(state nodestate.State) {
+	for _, pn := range state.ProviderNetworks {
+		d.providerNetworks[pn.GetProviderNwName()] = &pb.Notification_ProviderNwCreate{ProviderNwCreate: pn}
+	}
+	if state.NodeIntf != nil {
+		d.nodeIntf = &pb.Notification_InSync{InSync: state.NodeIntf}
+	}
+	for id, routes := range state.PodRoutes {
+		cr := &containerRoutes{routes: make(map[string]*pb.RouteData)}
+		for _, r := range routes {
+			cr.routes[r.GetDst()] = r
+		}
+		d.podRoutes[id] = cr
+	}
+	d.valid = state.NodeIntf != nil || len(state.ProviderNetworks) != 0 || len(state.PodRoutes) != 0
+}
+
+// save writes the state to the store if it changed
+func (d *desiredState) save() {
+	if stateStore == nil || !d.dirty || !d.valid {
+		return
+	}
+	err := stateStore.Update(func(state *nodestate.State) {
+		state.ProviderNetworks = nil
+		for _, pn := range d.providerNetworks {
+			state.ProviderNetworks = append(state.ProviderNetworks, pn.ProviderNwCreate)
+		}
+		state.NodeIntf = nil
+		if d.nodeIntf != nil {
+			state.NodeIntf = d.nodeIntf.InSync
+		}
+		state.PodRoutes = make(map[string][]*pb.RouteData)
+		for id, cr := range d.podRoutes {
+			for _, r := range cr.routes {
+				state.PodRoutes[id] = append(state.PodRoutes[id], r)
+			}
+		}
+	})
+	if err != nil {
+		log.Errorf("Unable to save the node state: %v", err)
+		return
+	}
+	d.dirty = false
+}
+
+// reconcileInterval returns the interval of the drift reconcile
+func reconcileInterval() time.Duration {
+	v := os.Getenv(reconcileIntervalEnv)
+	if v == "" {
+		return defaultReconcileInterval
+	}
+	interval, err := time.ParseDuration(v)
+	if err != nil {
+		log.Errorf("Invalid %s %q, using %v: %v", reconcileIntervalEnv, v, defaultReconcileInterval, err)
+		return defaultReconcileInterval
+	}
+	return interval
+}
+
+// runReconcile repairs the drift of the node from the desired state periodically, the repairs
+// are reported as events of the node
+func runReconcile(criclient criclient.CRIClient, recorder record.EventRecorder, node *kapi.Node, clientset kubernetes.Interface) {
+	interval := reconcileInterval()
+	if interval <= 0 {
+		log.Info("Drift reconcile disabled")
+		return
+	}
+	go wait.Until(func() {
+		prunePodAnnotations(clientset, node.Name)
+		repaired := reconcileNode(criclient)
+		if len(repaired) == 0 {
+			return
+		}
+		log.Infof("Repaired node drift: %s", strings.Join(repaired, ", "))
+		recorder.Eventf(node, kapi.EventTypeWarning, driftRepairedReason, "nfn-agent repaired %s", strings.Join(repaired, ", "))
+	}, interval, wait.NeverStop)
+}
+
+// prunePodAnnotations forgets the stored annotations of the pods not running on the node
+// anymore, nothing is pruned while the Kubernetes API is unreachable
+func prunePodAnnotations(clientset kubernetes.Interface, nodeName string) {
+	if stateStore == nil {
+		return
+	}
+	pods, err := clientset.CoreV1().Pods(v1.NamespaceAll).List(context.TODO(), v1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return
+	}
+	keys := make(map[string]bool)
+	for i := range pods.Items {
+		keys[nodestate.PodKey(&pods.Items[i])] = true
+	}
+	if err := stateStore.PrunePods(keys); err != nil {
+		log.Errorf("Unable to prune the stored pod annotations: %v", err)
+	}
+}
+
+// reconcileNode compares the node with the desired state and repairs the drift, it returns
+// what was repaired
+func reconcileNode(criclient criclient.CRIClient) []string {
+	agentLock.Lock()
+	defer agentLock.Unlock()
+	if !desired.valid {
+		// Nothing is known about the node before the first sync
+		return nil
+	}
+	var repaired []string
+	repaired = append(repaired, reconcileProviderNetworks()...)
+	repaired = append(repaired, reconcileNodeIntf()...)
+	repaired = append(repaired, reconcilePodRoutes(criclient)...)
+	desired.save()
+	return repaired
+}
+
+// reconcileProviderNetworks repairs the VLAN interfaces, the provider network bridges and
+// the ovn-bridge-mappings, and removes the ones of deleted provider networks
+func reconcileProviderNetworks() []string {
+	var repaired []string
+	mappings, err := ovn.GetOvnBridgeMappings()
+	if err != nil {
+		log.Errorf("Unable to read the ovn-bridge-mappings: %v", err)
+	}
+	vlans := make(map[string]bool)
+	bridges := make(map[string]bool)
+
+	names := make([]string, 0, len(desired.providerNetworks))
+	for name := range desired.providerNetworks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pn := desired.providerNetworks[name]
+		intf := pn.ProviderNwCreate.GetDirect().GetProviderIntf()
+		if vlan := pn.ProviderNwCreate.GetVlan(); vlan != nil {
+			intf = ovn.GetVlanLogicalInterfaceName(name, vlan.GetVlanId(), vlan.GetLogicalIntf())
+			vlans[intf] = true
+			link, err := netlink.LinkByName(intf)
+			if err != nil {
+				if err := ovn.CreateVlan(vlan.GetVlanId(), vlan.GetProviderIntf(), intf); err != nil {
+					log.Errorf("Unable to repair VLAN %s: %v", intf, err)
+					continue
+				}
+				repaired = append(repaired, "VLAN "+intf)
+			} else if link.Attrs().Flags&net.FlagUp == 0 {
+				if err := netlink.LinkSetUp(link); err != nil {
+					log.Errorf("Unable to set VLAN %s up: %v", intf, err)
+					continue
+				}
+				repaired = append(repaired, "VLAN "+intf+" state")
+			}
+		}
+		if !usesOvsBridge(pn) {
+			continue
+		}
+		br := "br-" + name
+		bridges[br] = true
+		if ovn.BridgeExists(br) && ovn.BridgeHasPort(br, intf) && (mappings == nil || mappings["nw_"+name] == br) {
+			continue
+		}
+		if err := ovn.CreatePnBridge("nw_"+name, br, intf); err != nil {
+			log.Errorf("Unable to repair bridge %s: %v", br, err)
+			continue
+		}
+		repaired = append(repaired, "bridge "+br)
+	}
+
+	for _, vlan := range ovn.GetVlan() {
+		if !vlans[vlan] {
+			ovn.DeleteVlan(vlan)
+			repaired = append(repaired, "stale VLAN "+vlan)
+		}
+	}
+	for _, br := range ovn.GetPnBridge("nfn") {
+		if !bridges[br] {
+			name := strings.TrimPrefix(br, "br-")
+			ovn.DeletePnBridge("nw_"+name, br)
+			repaired = append(repaired, "stale bridge "+br)
+		}
+	}
+	return repaired
+}
+
+// reconcileNodeIntf repairs the node OVS internal port and its addresses
+func reconcileNodeIntf() []string {
+	if desired.nodeIntf == nil {
+		return nil
+	}
+	name := config.GetNodeIntfName(strings.ToLower(os.Getenv("NFN_NODE_NAME")))
+	if nodeIntfInSync(name, desired.nodeIntf) {
+		return nil
+	}
+	if err := createNodeOVSInternalPort(desired.nodeIntf); err != nil {
+		log.Errorf("Unable to repair node interface %s: %v", name, err)
+		return nil
+	}
+	return []string{"node interface " + name}
+}
+
+// nodeIntfInSync checks that the node OVS internal port is up with its addresses
+func nodeIntfInSync(name string, payload *pb.Notification_InSync) bool {
+	if !ovn.BridgeHasPort("br-int", name) {
+		return false
+	}
+	link, err := netlink.LinkByName(name)
+	if err != nil || link.Attrs().Flags&net.FlagUp == 0 {
+		return false
+	}
+	addrs, err := netlink.AddrList(link, netlink.FAMILY_ALL)
+	if err != nil {
+		return false
+	}
+	for _, a := range []string{payload.InSync.GetNodeIntfIpAddress(), payload.InSync.GetNodeIntfIpv6Address()} {
+		a = strings.Trim(strings.TrimSpace(a), "\"")
+		if a == "" {
+			continue
+		}
+		addr, err := netlink.ParseAddr(a)
+		if err != nil {
+			continue
+		}
+		found := false
+		for _, existing := range addrs {
+			if existing.IPNet.String() == addr.IPNet.String() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// reconcilePodRoutes adds the routes missing in the containers, the routes of containers
+// not found anymore are forgotten
+func reconcilePodRoutes(criclient criclient.CRIClient) []string {
+	var repaired []string
+	for id, cr := range desired.podRoutes {
+		netns, err := criclient.GetNetnsForContainer(id)
+		if err != nil {
+			cr.misses++
+			if cr.misses >= maxContainerMisses {
+				log.Infof("Container %s not found, forgetting its routes: %v", id, err)
+				delete(desired.podRoutes, id)
+				desired.dirty = true
+			}
+			continue
+		}
+		cr.misses = 0
+		var routes []*pb.RouteData
+		for _, r := range cr.routes {
+			routes = append(routes, r)
+		}
+		missing, err := chaining.ContainerMissingRoutes(netns, routes)
+		if err != nil {
+			log.Errorf("Unable to read the routes of container %s: %v", id, err)
+			continue
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		if err := chaining.ContainerAddRoute(netns, missing); err != nil {
+			log.Errorf("Unable to repair the routes of container %s: %v", id, err)
+			continue
+		}
+		repaired = append(repaired, fmt.Sprintf("%d routes of container %s", len(missing), shortID(id)))
+	}
+	return repaired
+}
+
+// shortID returns the short form of a container ID
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"runtime"
 
@@ -52,7 +53,7 @@ func main() {
 	printVersion()
 
 	// Create an OVN Controller
-	_, err := ovn.NewOvnController(nil)
+	ovnCtl, err := ovn.NewOvnController(nil)
 	if err != nil {
 		log.Error(err, "")
 		os.Exit(1)
@@ -66,9 +67,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Start GRPC Notification Server
-	go notif.SetupNotifServer(cfg)
-
 	// Create a new Cmd to provide shared dependencies and start components
 	mgr, err := manager.New(cfg, manager.Options{})
 	if err != nil {
@@ -76,6 +74,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The admission webhook rejects the overlapping subnets before the objects are created
+	validator, err := ovn.NewAddressValidator(mgr.GetClient())
+	if err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
+	// Start GRPC Notification Server
+	go notif.SetupNotifServer(cfg, map[string]http.Handler{ovn.AddressValidationPath: validator})
+
 	log.Info("Registering Components.")
 
 	// Setup Scheme for all resources
@@ -84,6 +92,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The OVN Controller reads the IPReservations and the pods from the cache
+	if err := ovn.IndexReservations(mgr.GetFieldIndexer()); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+	ovnCtl.SetClient(mgr.GetClient())
+
 	// Setup all Controllers
 	if err := controller.AddToManager(mgr); err != nil {
 		log.Error(err, "")
@@ -19,12 +19,97 @@ import (
 	"github.com/containernetworking/cni/pkg/types/current"
 	"github.com/containernetworking/plugins/pkg/ip"
 	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/safchain/ethtool"
 	"github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
 )
 
 const primaryiface = "eth0"
 
+// InterfaceTuning holds the optional per network settings of the pod interface
+type InterfaceTuning struct {
+	TxQueueLen        int
+	Offload           map[string]bool
+	ProviderInterface string
+	// AttachType is macvlan or ipvlan when the pod is attached directly to AttachMaster
+	AttachType   string
+	AttachMode   string
+	AttachMaster string
+}
+
+// offloadFeatures maps the offload toggles to the kernel feature names
+var offloadFeatures = map[string][]string{
+	"tx":  {"tx-checksum-ip-generic", "tx-checksum-ipv4", "tx-checksum-ipv6"},
+	"rx":  {"rx-checksum"},
+	"tso": {"tx-tcp-segmentation", "tx-tcp6-segmentation"},
+	"gso": {"tx-generic-segmentation"},
+	"gro": {"rx-gro"},
+}
+
+// validateProviderMtu checks the interface MTU doesn't exceed the provider interface MTU
+func validateProviderMtu(providerInterface string, mtu int) error {
+	link, err := netlink.LinkByName(providerInterface)
+	if err != nil {
+		logrus.Warningf("failed to lookup provider interface %s, skipping MTU validation: %v", providerInterface, err)
+		return nil
+	}
+	if mtu > link.Attrs().MTU {
+		return fmt.Errorf("MTU %d exceeds the MTU %d of the provider interface %s", mtu, link.Attrs().MTU, providerInterface)
+	}
+	return nil
+}
+
+// setOffload changes the offload features of the interface in the current network namespace
+func setOffload(ifName string, offload map[string]bool) error {
+	e, err := ethtool.NewEthtool()
+	if err != nil {
+		return err
+	}
+	defer e.Close()
+
+	names, err := e.FeatureNames(ifName)
+	if err != nil {
+		return err
+	}
+
+	features := map[string]bool{}
+	for toggle, value := range offload {
+		kernelNames, ok := offloadFeatures[toggle]
+		if !ok {
+			return fmt.Errorf("unknown offload feature %q", toggle)
+		}
+		for _, name := range kernelNames {
+			if _, ok := names[name]; ok {
+				features[name] = value
+			}
+		}
+	}
+	if len(features) == 0 {
+		return nil
+	}
+
+	return e.Change(ifName, features)
+}
+
+// applyTuning sets the transmit queue length and the offload features of the link in the current network namespace
+func applyTuning(link netlink.Link, tuning *InterfaceTuning) error {
+	if tuning == nil {
+		return nil
+	}
+	ifName := link.Attrs().Name
+	if tuning.TxQueueLen > 0 {
+		if err := netlink.LinkSetTxQLen(link, tuning.TxQueueLen); err != nil {
+			return fmt.Errorf("failed to set txqueuelen %d on %s: %v", tuning.TxQueueLen, ifName, err)
+		}
+	}
+	if len(tuning.Offload) > 0 {
+		if err := setOffload(ifName, tuning.Offload); err != nil {
+			return fmt.Errorf("failed to set offload features on %s: %v", ifName, err)
+		}
+	}
+	return nil
+}
+
 func renameLink(curName, newName string) error {
 	link, err := netlink.LinkByName(curName)
 	if err != nil {
@@ -92,7 +177,7 @@ func CreateNodeOVSInternalPort(nodeintfipaddr, nodeintfipv6addr, nodeintfmacaddr
 			return fmt.Errorf("failed to parse IP addr %s: %v", nodeintfipaddr, err)
 		}
 
-		err = netlink.AddrAdd(link, addr)
+		err = netlink.AddrReplace(link, addr)
 		if err != nil {
 			logrus.Errorf("failed to parse IP addr %s: %v", nodeintfipaddr, err)
 			return fmt.Errorf("failed to add IP addr %s to %s: %v", nodeintfipaddr, nodeOVSInternalIntfName, err)
@@ -114,7 +199,7 @@ func CreateNodeOVSInternalPort(nodeintfipaddr, nodeintfipv6addr, nodeintfmacaddr
 			return fmt.Errorf("failed to parse IP addr %s: %v", nodeintfipv6addr, err)
 		}
 
-		err = netlink.AddrAdd(link, addr)
+		err = netlink.AddrReplace(link, addr)
 		if err != nil {
 			logrus.Errorf("failed to parse IP addr %s: %v", nodeintfipv6addr, err)
 			return fmt.Errorf("failed to add IP addr %s to %s: %v", nodeintfipv6addr, nodeOVSInternalIntfName, err)
@@ -214,32 +299,89 @@ func setExtraRoutes(hostNet, serviceSubnet, podSubnet, gatewayIP string) error {
 	return nil
 }
 
-func setupInterface(netns ns.NetNS, containerID, ifName, macAddress string, ipAddress, gatewayIP []string, defaultGateway string, idx, mtu int, isDefaultGW bool) (*current.Interface, *current.Interface, error) {
-	hostIface := &current.Interface{}
-	contIface := &current.Interface{}
-	var hostNet string
-	var serviceSubnet string
-	var podSubnet string
-	var err error
-
+// getClusterSubnets returns the host network, the service and the pod subnets of the cluster
+func getClusterSubnets() (hostNet, serviceSubnet, podSubnet string, err error) {
 	hostNet, err = network.GetHostNetwork()
 	if err != nil {
 		logrus.Error(err, "Failed to get host network")
-		return nil, nil, fmt.Errorf("failed to get host network: %v", err)
+		return "", "", "", fmt.Errorf("failed to get host network: %v", err)
 	}
 
 	k, err := kube.GetKubeConfig()
 	if err != nil {
-		return nil, nil, fmt.Errorf("Error in kubeclientset:%v", err)
+		return "", "", "", fmt.Errorf("Error in kubeclientset:%v", err)
 	}
 
 	kubecli := &kube.Kube{KClient: k}
 	kn, err := kubecli.GetControlPlaneServiceIPRange()
 	if err != nil {
-		return nil, nil, fmt.Errorf("Error in getting svc cidr range")
+		return "", "", "", fmt.Errorf("Error in getting svc cidr range")
+	}
+	return hostNet, kn.ServiceSubnet, kn.PodSubnet, nil
+}
+
+// setupGatewayRoutes adds the default gateway or the cluster routes of the pod interface
+func setupGatewayRoutes(link netlink.Link, ifName, defaultGateway string, gatewayIP []string, isDefaultGW bool, hostNet, serviceSubnet, podSubnet string) error {
+	logrus.Infof("Value of defaultGateway- %v and ifname- %v", defaultGateway, ifName)
+	if defaultGateway == "true" && ifName == "eth0" {
+		for _, address := range gatewayIP {
+			err := setGateway(link, address)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if defaultGateway == "true" && ifName != "eth0" {
+		_, err := GetPrimaryInterface()
+		if err != nil {
+			if strings.Contains(err.Error(), "Link not found") {
+				for _, address := range gatewayIP {
+					err := setGateway(link, address)
+					if err != nil {
+						return err
+					}
+				}
+			} else {
+				logrus.Error(err, "Error in getting the eth0 link in container ns")
+				return err
+			}
+		} else {
+			for _, address := range gatewayIP {
+				err := setpodGWRoutes(hostNet, serviceSubnet, podSubnet, address)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if defaultGateway == "false" && isDefaultGW == true && ifName == "eth0" {
+		for _, address := range gatewayIP {
+			err := setExtraRoutes(hostNet, serviceSubnet, podSubnet, address)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func setupInterface(netns ns.NetNS, containerID, ifName, macAddress string, ipAddress, gatewayIP []string, defaultGateway string, idx, mtu int, isDefaultGW bool, tuning *InterfaceTuning) (*current.Interface, *current.Interface, error) {
+	hostIface := &current.Interface{}
+	contIface := &current.Interface{}
+
+	hostNet, serviceSubnet, podSubnet, err := getClusterSubnets()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if tuning != nil && tuning.ProviderInterface != "" {
+		if err := validateProviderMtu(tuning.ProviderInterface, mtu); err != nil {
+			return nil, nil, err
+		}
 	}
-	serviceSubnet = kn.ServiceSubnet
-	podSubnet = kn.PodSubnet
 
 	var oldHostVethName string
 	err = netns.Do(func(hostNS ns.NetNS) error {
@@ -268,6 +410,10 @@ func setupInterface(netns ns.NetNS, containerID, ifName, macAddress string, ipAd
 		contIface.Mac = macAddress
 		contIface.Sandbox = netns.Path()
 
+		if err := applyTuning(link, tuning); err != nil {
+			return err
+		}
+
 		for _, address := range ipAddress {
 			err = addIpAddressToLinkDevice(address, &link, contIface.Name)
 			if err != nil {
@@ -275,47 +421,8 @@ func setupInterface(netns ns.NetNS, containerID, ifName, macAddress string, ipAd
 			}
 		}
 
-		logrus.Infof("Value of defaultGateway- %v and ifname- %v", defaultGateway, ifName)
-		if defaultGateway == "true" && ifName == "eth0" {
-			for _, address := range gatewayIP {
-				err := setGateway(link, address)
-				if err != nil {
-					return err
-				}
-			}
-		}
-
-		if defaultGateway == "true" && ifName != "eth0" {
-			_, err = GetPrimaryInterface()
-			if err != nil {
-				if strings.Contains(err.Error(), "Link not found") {
-					for _, address := range gatewayIP {
-						err := setGateway(link, address)
-						if err != nil {
-							return err
-						}
-					}
-				} else {
-					logrus.Error(err, "Error in getting the eth0 link in container ns")
-					return err
-				}
-			} else {
-				for _, address := range gatewayIP {
-					err := setpodGWRoutes(hostNet, serviceSubnet, podSubnet, address)
-					if err != nil {
-						return err
-					}
-				}
-			}
-		}
-
-		if defaultGateway == "false" && isDefaultGW == true && ifName == "eth0" {
-			for _, address := range gatewayIP {
-				err = setExtraRoutes(hostNet, serviceSubnet, podSubnet, address)
-				if err != nil {
-					return err
-				}
-			}
+		if err := setupGatewayRoutes(link, ifName, defaultGateway, gatewayIP, isDefaultGW, hostNet, serviceSubnet, podSubnet); err != nil {
+			return err
 		}
 
 		oldHostVethName = hostVeth.Name
@@ -332,9 +439,150 @@ func setupInterface(netns ns.NetNS, containerID, ifName, macAddress string, ipAd
 		return nil, nil, fmt.Errorf("failed to rename %s to %s: %v", oldHostVethName, hostIface.Name, err)
 	}
 
+	hostLink, err := netlink.LinkByName(hostIface.Name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lookup %s: %v", hostIface.Name, err)
+	}
+	if err := applyTuning(hostLink, tuning); err != nil {
+		return nil, nil, err
+	}
+
 	return hostIface, contIface, nil
 }
 
+// newSubInterface returns the macvlan or ipvlan link to be created on the master interface
+func newSubInterface(name string, mtu int, master netlink.Link, netns ns.NetNS, tuning *InterfaceTuning) (netlink.Link, error) {
+	attrs := netlink.NewLinkAttrs()
+	attrs.Name = name
+	attrs.MTU = mtu
+	attrs.ParentIndex = master.Attrs().Index
+	attrs.Namespace = netlink.NsFd(int(netns.Fd()))
+
+	switch tuning.AttachType {
+	case "macvlan":
+		modes := map[string]netlink.MacvlanMode{
+			"":         netlink.MACVLAN_MODE_BRIDGE,
+			"bridge":   netlink.MACVLAN_MODE_BRIDGE,
+			"private":  netlink.MACVLAN_MODE_PRIVATE,
+			"vepa":     netlink.MACVLAN_MODE_VEPA,
+			"passthru": netlink.MACVLAN_MODE_PASSTHRU,
+		}
+		mode, ok := modes[tuning.AttachMode]
+		if !ok {
+			return nil, fmt.Errorf("unsupported macvlan mode %s", tuning.AttachMode)
+		}
+		return &netlink.Macvlan{LinkAttrs: attrs, Mode: mode}, nil
+	case "ipvlan":
+		modes := map[string]netlink.IPVlanMode{
+			"":    netlink.IPVLAN_MODE_L2,
+			"l2":  netlink.IPVLAN_MODE_L2,
+			"l3":  netlink.IPVLAN_MODE_L3,
+			"l3s": netlink.IPVLAN_MODE_L3S,
+		}
+		mode, ok := modes[tuning.AttachMode]
+		if !ok {
+			return nil, fmt.Errorf("unsupported ipvlan mode %s", tuning.AttachMode)
+		}
+		return &netlink.IPVlan{LinkAttrs: attrs, Mode: mode}, nil
+	}
+	return nil, fmt.Errorf("unsupported attachment type %s", tuning.AttachType)
+}
+
+// setupSubInterface creates a macvlan or ipvlan interface of the provider network in the container
+func setupSubInterface(netns ns.NetNS, ifName, macAddress string, ipAddress, gatewayIP []string, defaultGateway string, mtu int, isDefaultGW bool, tuning *InterfaceTuning) (*current.Interface, error) {
+	contIface := &current.Interface{}
+
+	hostNet, serviceSubnet, podSubnet, err := getClusterSubnets()
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := netlink.LinkByName(tuning.AttachMaster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup master interface %s: %v", tuning.AttachMaster, err)
+	}
+	if mtu > master.Attrs().MTU {
+		return nil, fmt.Errorf("MTU %d exceeds the MTU %d of the master interface %s", mtu, master.Attrs().MTU, tuning.AttachMaster)
+	}
+
+	tmpName, err := ip.RandomVethName()
+	if err != nil {
+		return nil, err
+	}
+	subIface, err := newSubInterface(tmpName, mtu, master, netns, tuning)
+	if err != nil {
+		return nil, err
+	}
+	if err := netlink.LinkAdd(subIface); err != nil {
+		return nil, fmt.Errorf("failed to create %s interface on %s: %v", tuning.AttachType, tuning.AttachMaster, err)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		// the interface has the temporary name until it is renamed, another interface may
+		// already have the name of the container interface
+		_ = netns.Do(func(_ ns.NetNS) error {
+			for _, name := range []string{tmpName, ifName} {
+				if link, lerr := netlink.LinkByName(name); lerr == nil {
+					return netlink.LinkDel(link)
+				}
+			}
+			return nil
+		})
+	}()
+
+	err = netns.Do(func(_ ns.NetNS) error {
+		if err := ip.RenameLink(tmpName, ifName); err != nil {
+			return fmt.Errorf("failed to rename %s to %s: %v", tmpName, ifName, err)
+		}
+
+		link, err := netlink.LinkByName(ifName)
+		if err != nil {
+			return fmt.Errorf("failed to lookup %s: %v", ifName, err)
+		}
+
+		// ipvlan interfaces share the MAC address of the master
+		if tuning.AttachType == "macvlan" {
+			hwAddr, err := net.ParseMAC(macAddress)
+			if err != nil {
+				return fmt.Errorf("failed to parse mac address for %s: %v", ifName, err)
+			}
+			if err := netlink.LinkSetHardwareAddr(link, hwAddr); err != nil {
+				return fmt.Errorf("failed to add mac address %s to %s: %v", macAddress, ifName, err)
+			}
+			link, err = netlink.LinkByName(ifName)
+			if err != nil {
+				return fmt.Errorf("failed to lookup %s: %v", ifName, err)
+			}
+		}
+		contIface.Name = ifName
+		contIface.Mac = link.Attrs().HardwareAddr.String()
+		contIface.Sandbox = netns.Path()
+
+		if err := applyTuning(link, tuning); err != nil {
+			return err
+		}
+
+		for _, address := range ipAddress {
+			if err := addIpAddressToLinkDevice(address, &link, ifName); err != nil {
+				return err
+			}
+		}
+
+		if err := netlink.LinkSetUp(link); err != nil {
+			return fmt.Errorf("failed to set %s up: %v", ifName, err)
+		}
+
+		return setupGatewayRoutes(link, ifName, defaultGateway, gatewayIP, isDefaultGW, hostNet, serviceSubnet, podSubnet)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return contIface, nil
+}
+
 func addIpAddressToLinkDevice(ipAddress string, link *netlink.Link, contIfaceName string) error {
 	addr, err := netlink.ParseAddr(ipAddress)
 	if err != nil {
@@ -398,7 +646,7 @@ var ConfigureDeleteInterface = func(containerNetns, ifName string) error {
 }
 
 // ConfigureInterface sets up the container interface
-var ConfigureInterface = func(containerNetns, containerID, ifName, namespace, podName, macAddress string, ipAddress, gatewayIP []string, interfaceName, defaultGateway string, idx, mtu int, isDefaultGW bool) ([]*current.Interface, error) {
+var ConfigureInterface = func(containerNetns, containerID, ifName, namespace, podName, macAddress string, ipAddress, gatewayIP []string, interfaceName, defaultGateway string, idx, mtu int, isDefaultGW bool, tuning *InterfaceTuning) ([]*current.Interface, error) {
 	netns, err := ns.GetNS(containerNetns)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open netns %q: %v", containerNetns, err)
@@ -412,7 +660,16 @@ var ConfigureInterface = func(containerNetns, containerID, ifName, namespace, po
 		ifaceID = fmt.Sprintf("%s_%s", namespace, podName)
 		interfaceName = ifName
 	}
-	hostIface, contIface, err := setupInterface(netns, containerID, interfaceName, macAddress, ipAddress, gatewayIP, defaultGateway, idx, mtu, isDefaultGW)
+
+	if tuning != nil && tuning.AttachType != "" {
+		contIface, err := setupSubInterface(netns, interfaceName, macAddress, ipAddress, gatewayIP, defaultGateway, mtu, isDefaultGW, tuning)
+		if err != nil {
+			return nil, err
+		}
+		return []*current.Interface{contIface}, nil
+	}
+
+	hostIface, contIface, err := setupInterface(netns, containerID, interfaceName, macAddress, ipAddress, gatewayIP, defaultGateway, idx, mtu, isDefaultGW, tuning)
 	if err != nil {
 		return nil, err
 	}
@@ -461,6 +718,22 @@ var ConfigureRoute = func(containerNetns, dst, gw, dev string) error {
 	return err
 }
 
+// HostInterfaceName returns the br-int port of the OVN logical port. The logical port is recorded in
+// the iface-id of the OVS interface when the pod interface is plugged, whatever the sandbox ID used
+// to name the port was. It returns "" if no port is plugged for the logical port.
+func HostInterfaceName(ifaceID string) (string, error) {
+	out, err := exec.Command("ovs-vsctl", "--bare", "--columns=name", "find", "interface",
+		fmt.Sprintf("external_ids:iface-id=%s", ifaceID)).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the OVS port of %s: %v\n  %q", ifaceID, err, string(out))
+	}
+	names := strings.Fields(string(out))
+	if len(names) == 0 {
+		return "", nil
+	}
+	return names[0], nil
+}
+
 // PlatformSpecificCleanup deletes the OVS port
 func PlatformSpecificCleanup(ifaceName string) (bool, error) {
 	done := false
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	authv1 "k8s.io/api/authentication/v1"
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/kube"
+)
+
+const (
+	// AgentServiceAccount is the service account of the nfn-agents, only its tokens can request
+	// a node certificate
+	AgentServiceAccount = "nfn-agent"
+	// CABundleConfigMap is a name of the config map the nfn-operator publishes its CA bundle in
+	CABundleConfigMap = "nodus-ca-bundle"
+	// NodeCertPortEnv is a name of env variable that holds the port serving the node certificates
+	NodeCertPortEnv = "NFN_OPERATOR_SERVICE_PORT_CERTS"
+	// HTTPSAddr is the address the nfn-operator serves the node certificates and the admission
+	// webhooks on
+	HTTPSAddr = ":50001"
+	// NodeCertPath is the path of the node certificates
+	NodeCertPath = "/nodecert"
+
+	nodeCertTimeout = time.Minute
+	podNameExtra    = "authentication.kubernetes.io/pod-name"
+	podUIDExtra     = "authentication.kubernetes.io/pod-uid"
+	tokenFile       = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// nodeCertServer serves the nfn-agents the certificate of their node. The nfn-agents share a
+// service account so they can't be allowed to read the node secrets, the nfn-operator reads
+// them instead and hands out a certificate only to the agent running on its node.
+type nodeCertServer struct {
+	client    kubernetes.Interface
+	namespace string
+	provider  CertProvider
+}
+
+// NewNodeCertHandler returns the handler serving the node certificates on NodeCertPath
+func NewNodeCertHandler(client kubernetes.Interface, namespace string, provider CertProvider) http.Handler {
+	return &nodeCertServer{client: client, namespace: namespace, provider: provider}
+}
+
+func (s *nodeCertServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	nodeName := r.URL.Query().Get("node")
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if nodeName == "" || token == "" {
+		http.Error(w, "node and token are required", http.StatusBadRequest)
+		return
+	}
+	if err := s.verifyAgent(r.Context(), token, nodeName); err != nil {
+		klog.Errorf("Rejected the certificate request for node %s: %v", nodeName, err)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	secret, err := s.provider.NodeSecret(nodeName)
+	if err != nil {
+		klog.Errorf("Unable to get the certificate of node %s: %v", nodeName, err)
+		http.Error(w, "certificate not available", http.StatusServiceUnavailable)
+		return
+	}
+	data := map[string][]byte{
+		CAFile:   secret.Data[CAFile],
+		CertFile: secret.Data[CertFile],
+		KeyFile:  secret.Data[KeyFile],
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		klog.Errorf("Unable to send the certificate of node %s: %v", nodeName, err)
+	}
+}
+
+// verifyAgent checks that the token belongs to a nfn-agent pod running on the node
+func (s *nodeCertServer) verifyAgent(ctx context.Context, token, nodeName string) error {
+	review, err := s.client.AuthenticationV1().TokenReviews().Create(ctx, &authv1.TokenReview{
+		Spec: authv1.TokenReviewSpec{Token: token},
+	}, v1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	if !review.Status.Authenticated {
+		return fmt.Errorf("token is not authenticated: %s", review.Status.Error)
+	}
+	user := review.Status.User
+	if user.Username != fmt.Sprintf("system:serviceaccount:%s:%s", s.namespace, AgentServiceAccount) {
+		return fmt.Errorf("token of %s is not a nfn-agent token", user.Username)
+	}
+	podNames, podUIDs := user.Extra[podNameExtra], user.Extra[podUIDExtra]
+	if len(podNames) != 1 || len(podUIDs) != 1 {
+		return fmt.Errorf("token is not bound to a pod")
+	}
+	pod, err := s.client.CoreV1().Pods(s.namespace).Get(ctx, podNames[0], v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if string(pod.UID) != podUIDs[0] {
+		return fmt.Errorf("pod %s of the token no longer exists", podNames[0])
+	}
+	if pod.Spec.NodeName != nodeName {
+		return fmt.Errorf("pod %s runs on node %s", pod.Name, pod.Spec.NodeName)
+	}
+	return nil
+}
+
+// FetchNodeSecret requests the certificate of the node from the nfn-operator at addr with the
+// token of the nfn-agent, the nfn-operator certificate is verified against the CA bundle
+func FetchNodeSecret(addr, nodeName string, caBundle []byte) (*kapi.Secret, error) {
+	token, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caBundle) {
+		return nil, fmt.Errorf("no CA certificate found in the CA bundle")
+	}
+	client := http.Client{
+		Timeout: nodeCertTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://%s%s?node=%s", addr, NodeCertPath, url.QueryEscape(nodeName)), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nfn-operator refused the certificate of node %s: %s", nodeName, resp.Status)
+	}
+
+	data := map[string][]byte{}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	return &kapi.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: NodeCertName(nodeName)},
+		Data:       data,
+	}, nil
+}
+
+// PublishCABundle publishes the CA bundle of the secret in the CABundleConfigMap config map
+func PublishCABundle(client kubernetes.Interface, namespace string, secret *kapi.Secret) error {
+	configMaps := client.CoreV1().ConfigMaps(namespace)
+	cm, err := configMaps.Get(context.TODO(), CABundleConfigMap, v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		cm = &kapi.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{Name: CABundleConfigMap, Namespace: namespace},
+			Data:       map[string]string{CAFile: string(secret.Data[CAFile])},
+		}
+		_, err = configMaps.Create(context.TODO(), cm, v1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data[CAFile] == string(secret.Data[CAFile]) {
+		return nil
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[CAFile] = string(secret.Data[CAFile])
+	_, err = configMaps.Update(context.TODO(), cm, v1.UpdateOptions{})
+	return err
+}
+
+// GetCABundle returns the CA bundle published by the nfn-operator
+func GetCABundle(kubecli *kube.Kube, namespace string) ([]byte, error) {
+	cm, err := kubecli.KClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), CABundleConfigMap, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if cm.Data[CAFile] == "" {
+		return nil, fmt.Errorf("config map %s/%s has no CA bundle", namespace, CABundleConfigMap)
+	}
+	return []byte(cm.Data[CAFile]), nil
+}
@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+)
+
+const (
+	// DefaultCASecret is a name of the secret holding the built-in CA of the nfn-operator
+	DefaultCASecret = "nodus-ca"
+
+	caSyncInterval      = time.Minute
+	caDuration          = 10 * 365 * 24 * time.Hour
+	leafDuration        = 365 * 24 * time.Hour
+	cniCertDNSName      = "dummy"
+	caCommonName        = "nodus-ca"
+	serverCertName      = "nfn-operator"
+	certBlockType       = "CERTIFICATE"
+	ecKeyBlockType      = "EC PRIVATE KEY"
+	issuerAnnotationKey = "k8s.plugin.opnfv.org/issuer"
+)
+
+// builtinCA issues the certificates of the nfn-operator, the nfn-agents and the CNI server
+// into the same secrets cert-manager would use, and rotates them before they expire
+type builtinCA struct {
+	client    kubernetes.Interface
+	namespace string
+	serverIP  string
+}
+
+// certRequest describes a certificate to issue
+type certRequest struct {
+	commonName  string
+	dnsNames    []string
+	ipAddresses []net.IP
+	usages      []x509.ExtKeyUsage
+}
+
+// needsRenewal returns true if less than a third of the validity of the certificate is left
+func needsRenewal(cert *x509.Certificate) bool {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return time.Until(cert.NotAfter) < lifetime/3
+}
+
+func generateKey() (*ecdsa.PrivateKey, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: ecKeyBlockType, Bytes: der}), nil
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// sync issues the missing certificates and renews the expiring ones
+func (ca *builtinCA) sync() {
+	caSecret, caCert, caKey, err := ca.ensureCA()
+	if err != nil {
+		klog.Errorf("Unable to set up the built-in CA: %v", err)
+		return
+	}
+	caBundle := caSecret.Data[CAFile]
+
+	requests := map[string]certRequest{
+		DefaultCert: {
+			commonName: serverCertName,
+			// the API server calls the admission webhooks through the service name
+			dnsNames:    []string{fmt.Sprintf("%s.%s.svc", serverCertName, ca.namespace)},
+			ipAddresses: []net.IP{net.ParseIP(ca.serverIP)},
+			usages:      []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		},
+		DefaultCniCert: {
+			commonName: cniCertDNSName,
+			dnsNames:   []string{cniCertDNSName},
+			usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		},
+	}
+	nodes, err := ca.client.CoreV1().Nodes().List(context.TODO(), v1.ListOptions{})
+	if err != nil {
+		klog.Errorf("Unable to list the nodes: %v", err)
+	} else {
+		for _, node := range nodes.Items {
+			requests[NodeCertName(node.Name)] = certRequest{
+				commonName: node.Name,
+				dnsNames:   []string{node.Name},
+				usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			}
+		}
+	}
+
+	for name, req := range requests {
+		if err := ca.ensureCert(name, req, caCert, caKey, caBundle); err != nil {
+			klog.Errorf("Unable to issue the certificate %s: %v", name, err)
+		}
+	}
+}
+
+// ensureCA returns the CA secret, the CA is created or renewed if required. The previous CA
+// stays in the bundle while it is valid so that the certificates it issued are still trusted.
+func (ca *builtinCA) ensureCA() (*kapi.Secret, *x509.Certificate, *ecdsa.PrivateKey, error) {
+	secrets := ca.client.CoreV1().Secrets(ca.namespace)
+	secret, err := secrets.Get(context.TODO(), DefaultCASecret, v1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, nil, nil, err
+	}
+
+	var oldCA []byte
+	if err == nil {
+		cert, certErr := parseCertificate(secret.Data[CertFile])
+		block, _ := pem.Decode(secret.Data[KeyFile])
+		if certErr == nil && block != nil {
+			key, keyErr := x509.ParseECPrivateKey(block.Bytes)
+			if keyErr == nil && !needsRenewal(cert) {
+				return secret, cert, key, nil
+			}
+			if time.Now().Before(cert.NotAfter) {
+				oldCA = secret.Data[CertFile]
+			}
+		}
+	}
+
+	key, keyPEM, err := generateKey()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: caCommonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caDuration),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: certBlockType, Bytes: der})
+
+	data := map[string][]byte{
+		CAFile:   append(append([]byte{}, certPEM...), oldCA...),
+		CertFile: certPEM,
+		KeyFile:  keyPEM,
+	}
+	secret, err = ca.applySecret(DefaultCASecret, data)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	klog.Infof("Issued the built-in CA %s/%s", ca.namespace, DefaultCASecret)
+	return secret, cert, key, nil
+}
+
+// ensureCert issues the certificate into the secret if it is missing, expiring, issued by
+// another CA or no longer matching the request
+func (ca *builtinCA) ensureCert(name string, req certRequest, caCert *x509.Certificate, caKey *ecdsa.PrivateKey, caBundle []byte) error {
+	secret, err := ca.client.CoreV1().Secrets(ca.namespace).Get(context.TODO(), name, v1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && bytes.Equal(secret.Data[CAFile], caBundle) {
+		cert, err := parseCertificate(secret.Data[CertFile])
+		if err == nil && cert.CheckSignatureFrom(caCert) == nil && !needsRenewal(cert) && matchesRequest(cert, req) {
+			return nil
+		}
+	}
+
+	key, keyPEM, err := generateKey()
+	if err != nil {
+		return err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: req.commonName},
+		DNSNames:     req.dnsNames,
+		IPAddresses:  req.ipAddresses,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(leafDuration),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  req.usages,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+
+	data := map[string][]byte{
+		CAFile:   caBundle,
+		CertFile: pem.EncodeToMemory(&pem.Block{Type: certBlockType, Bytes: der}),
+		KeyFile:  keyPEM,
+	}
+	if _, err := ca.applySecret(name, data); err != nil {
+		return err
+	}
+	klog.Infof("Issued the certificate %s/%s", ca.namespace, name)
+	return nil
+}
+
+// matchesRequest checks that the SANs of the certificate are the requested ones
+func matchesRequest(cert *x509.Certificate, req certRequest) bool {
+	if fmt.Sprint(cert.DNSNames) != fmt.Sprint(req.dnsNames) || len(cert.IPAddresses) != len(req.ipAddresses) {
+		return false
+	}
+	for i, ip := range req.ipAddresses {
+		if !cert.IPAddresses[i].Equal(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// applySecret creates or updates the TLS secret with the data
+func (ca *builtinCA) applySecret(name string, data map[string][]byte) (*kapi.Secret, error) {
+	secrets := ca.client.CoreV1().Secrets(ca.namespace)
+	secret, err := secrets.Get(context.TODO(), name, v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		secret = &kapi.Secret{
+			ObjectMeta: v1.ObjectMeta{
+				Name:        name,
+				Namespace:   ca.namespace,
+				Annotations: map[string]string{issuerAnnotationKey: BuiltinProvider},
+			},
+			Type: kapi.SecretTypeTLS,
+			Data: data,
+		}
+		return secrets.Create(context.TODO(), secret, v1.CreateOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	secret.Data = data
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[issuerAnnotationKey] = BuiltinProvider
+	return secrets.Update(context.TODO(), secret, v1.UpdateOptions{})
+}
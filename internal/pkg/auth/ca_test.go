@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	kapi "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAuth(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Auth Test Suite")
+}
+
+// validity returns a certificate valid from the start to the end, relative to now
+func validity(start, end time.Duration) *x509.Certificate {
+	now := time.Now()
+	return &x509.Certificate{NotBefore: now.Add(start), NotAfter: now.Add(end)}
+}
+
+// caSecret returns the secret of a self-signed CA valid from the start to the end, relative to now
+func caSecret(namespace string, start, end time.Duration) *kapi.Secret {
+	key, keyPEM, err := generateKey()
+	Expect(err).NotTo(HaveOccurred())
+	serial, err := serialNumber()
+	Expect(err).NotTo(HaveOccurred())
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: caCommonName},
+		NotBefore:             now.Add(start),
+		NotAfter:              now.Add(end),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: certBlockType, Bytes: der})
+	return &kapi.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: DefaultCASecret, Namespace: namespace},
+		Data:       map[string][]byte{CAFile: certPEM, CertFile: certPEM, KeyFile: keyPEM},
+	}
+}
+
+var _ = Describe("Test built-in CA", func() {
+	table.DescribeTable("renews the certificates after two thirds of their validity",
+		func(cert *x509.Certificate, renew bool) {
+			Expect(needsRenewal(cert)).To(Equal(renew))
+		},
+		table.Entry("new certificate", validity(-time.Hour, 299*time.Hour), false),
+		table.Entry("certificate before two thirds of its validity", validity(-190*time.Hour, 110*time.Hour), false),
+		table.Entry("certificate after two thirds of its validity", validity(-210*time.Hour, 90*time.Hour), true),
+		table.Entry("expired certificate", validity(-300*time.Hour, -time.Hour), true),
+	)
+
+	table.DescribeTable("checks the SANs of the certificates",
+		func(dnsNames []string, ips []string, match bool) {
+			cert := &x509.Certificate{
+				DNSNames:    []string{"nfn-operator.nodus.svc"},
+				IPAddresses: []net.IP{net.ParseIP("10.0.0.5")},
+			}
+			req := certRequest{dnsNames: dnsNames}
+			for _, ip := range ips {
+				req.ipAddresses = append(req.ipAddresses, net.ParseIP(ip))
+			}
+			Expect(matchesRequest(cert, req)).To(Equal(match))
+		},
+		table.Entry("same SANs", []string{"nfn-operator.nodus.svc"}, []string{"10.0.0.5"}, true),
+		table.Entry("IP address changed", []string{"nfn-operator.nodus.svc"}, []string{"10.0.0.6"}, false),
+		table.Entry("IP address added", []string{"nfn-operator.nodus.svc"}, []string{"10.0.0.5", "10.0.0.6"}, false),
+		table.Entry("IP address removed", []string{"nfn-operator.nodus.svc"}, nil, false),
+		table.Entry("DNS name changed", []string{"nfn-operator.kube-system.svc"}, []string{"10.0.0.5"}, false),
+	)
+
+	table.DescribeTable("rolls over the CA",
+		func(start, end time.Duration, renewed, keepOld bool) {
+			old := caSecret("nodus", start, end)
+			ca := &builtinCA{client: fake.NewSimpleClientset(old), namespace: "nodus"}
+
+			secret, cert, _, err := ca.ensureCA()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(!bytes.Equal(secret.Data[CertFile], old.Data[CertFile])).To(Equal(renewed))
+			Expect(needsRenewal(cert)).To(BeFalse())
+			Expect(bytes.HasPrefix(secret.Data[CAFile], secret.Data[CertFile])).To(BeTrue())
+			Expect(bytes.Contains(secret.Data[CAFile], old.Data[CertFile])).To(Equal(keepOld))
+		},
+		table.Entry("valid CA is kept", -time.Hour, caDuration, false, true),
+		table.Entry("expiring CA stays in the bundle", -caDuration*9/10, caDuration/10, true, true),
+		table.Entry("expired CA is dropped from the bundle", -caDuration, -time.Hour, true, false),
+	)
+
+	It("issues the certificates again with the new CA", func() {
+		old := caSecret("nodus", -caDuration*9/10, caDuration/10)
+		client := fake.NewSimpleClientset(old)
+		ca := &builtinCA{client: client, namespace: "nodus"}
+		req := certRequest{commonName: "node-a", dnsNames: []string{"node-a"}, usages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+
+		// certificate issued by the expiring CA
+		block, _ := pem.Decode(old.Data[KeyFile])
+		oldKey, err := x509.ParseECPrivateKey(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+		oldCert, err := parseCertificate(old.Data[CertFile])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ca.ensureCert(NodeCertName("node-a"), req, oldCert, oldKey, old.Data[CAFile])).To(Succeed())
+		issued, err := client.CoreV1().Secrets("nodus").Get(context.TODO(), NodeCertName("node-a"), v1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		// the certificate is kept while the CA is not renewed
+		Expect(ca.ensureCert(NodeCertName("node-a"), req, oldCert, oldKey, old.Data[CAFile])).To(Succeed())
+		kept, err := client.CoreV1().Secrets("nodus").Get(context.TODO(), NodeCertName("node-a"), v1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kept.Data[CertFile]).To(Equal(issued.Data[CertFile]))
+
+		secret, caCert, caKey, err := ca.ensureCA()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ca.ensureCert(NodeCertName("node-a"), req, caCert, caKey, secret.Data[CAFile])).To(Succeed())
+		reissued, err := client.CoreV1().Secrets("nodus").Get(context.TODO(), NodeCertName("node-a"), v1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reissued.Data[CAFile]).To(Equal(secret.Data[CAFile]))
+		cert, err := parseCertificate(reissued.Data[CertFile])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.CheckSignatureFrom(caCert)).To(Succeed())
+		Expect(reissued.Annotations).To(HaveKeyWithValue(issuerAnnotationKey, BuiltinProvider))
+	})
+})
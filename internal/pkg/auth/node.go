@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/kube"
+
+	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+)
+
+const (
+	// nodeCertPrefix prefixes the name of the certificate and the secret of a nfn-agent
+	nodeCertPrefix = "nodus-agent-"
+	// nodeCertDuration is the duration of the nfn-agent certificates
+	nodeCertDuration = 17520 * time.Hour
+)
+
+// NodeCertName returns the name of the certificate and the secret of the nfn-agent of the node
+func NodeCertName(nodeName string) string {
+	return nodeCertPrefix + nodeName
+}
+
+// GetNodeCert returns the client certificate of the nfn-agent of the node and its secret.
+// The certificate is requested from the default issuer with the node name in the SAN if it
+// doesn't exist yet. It is called by the nfn-operator, the nfn-agents can't read the secrets.
+func GetNodeCert(namespace, nodeName string) (*cmv1.Certificate, *kapi.Secret, error) {
+	name := NodeCertName(nodeName)
+	crt, err := GetCert(namespace, name)
+	if err == nil {
+		kubecli, err := GetKubeClient()
+		if err != nil {
+			return crt, nil, err
+		}
+		s, err := WaitForSecret(kubecli, namespace, crt.Spec.SecretName)
+		return crt, s, err
+	}
+	if !errors.IsNotFound(err) {
+		return nil, nil, err
+	}
+
+	return applyCertAndWait(nodeCertificate(namespace, nodeName))
+}
+
+// nodeCertificate returns the certificate of the nfn-agent of the node issued by the default issuer
+func nodeCertificate(namespace, nodeName string) *cmv1.Certificate {
+	name := NodeCertName(nodeName)
+	return &cmv1.Certificate{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: cmv1.CertificateSpec{
+			CommonName: nodeName,
+			DNSNames:   []string{nodeName},
+			SecretName: name,
+			Duration:   &v1.Duration{Duration: nodeCertDuration},
+			IssuerRef: cmmeta.ObjectReference{
+				Name: DefaultIssuer,
+				Kind: cmv1.IssuerKind,
+			},
+			Usages: []cmv1.KeyUsage{
+				cmv1.UsageDigitalSignature,
+				cmv1.UsageKeyEncipherment,
+				cmv1.UsageClientAuth,
+			},
+		},
+	}
+}
+
+// ensureNodeCerts requests the certificates of the nfn-agents of all the nodes that don't have one
+func ensureNodeCerts(kubecli *kube.Kube, namespace string) {
+	client, err := getCertClient(namespace)
+	if err != nil {
+		klog.Errorf("Unable to create the cert-manager client: %v", err)
+		return
+	}
+	nodes, err := kubecli.KClient.CoreV1().Nodes().List(context.TODO(), v1.ListOptions{})
+	if err != nil {
+		klog.Errorf("Unable to list the nodes: %v", err)
+		return
+	}
+	for _, node := range nodes.Items {
+		_, err := (*client).Create(context.TODO(), nodeCertificate(namespace, node.Name), v1.CreateOptions{})
+		if err != nil && !errors.IsAlreadyExists(err) {
+			klog.Errorf("Unable to request the certificate of node %s: %v", node.Name, err)
+		}
+	}
+}
+
+// VerifyPeerNode checks that the client certificate of the GRPC peer is issued for the node
+func VerifyPeerNode(ctx context.Context, nodeName string) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("no peer information found")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return fmt.Errorf("no client certificate found")
+	}
+	for _, name := range tlsInfo.State.PeerCertificates[0].DNSNames {
+		if name == nodeName {
+			return nil
+		}
+	}
+	return fmt.Errorf("client certificate is not issued for the node %s", nodeName)
+}
@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/kube"
+)
+
+const (
+	// CertProviderEnv is a name of env variable that selects the certificate provider
+	CertProviderEnv = "NODUS_CERT_PROVIDER"
+	// StaticCertSecretEnv is a name of env variable that holds the secret of the static provider
+	StaticCertSecretEnv = "NODUS_CERT_SECRET"
+
+	// CertManagerProvider requests the certificates from cert-manager, it is the default
+	CertManagerProvider = "cert-manager"
+	// BuiltinProvider issues the certificates with a CA built in the nfn-operator
+	BuiltinProvider = "builtin"
+	// StaticProvider uses the certificates of a secret supplied by the user
+	StaticProvider = "static"
+
+	// secretPollInterval and secretPollTimeout bound the wait for the secrets issued by
+	// the nfn-operator
+	secretPollInterval = 2 * time.Second
+	secretPollTimeout  = 10 * time.Minute
+)
+
+// CertProvider provides the secrets holding the certificates of the nfn-operator, the
+// nfn-agents and the CNI server. Every secret has the ca.crt, tls.crt and tls.key keys.
+type CertProvider interface {
+	// ServerSecret returns the secret of the nfn-operator certificate issued for the IP address
+	ServerSecret(ipAddr string) (*kapi.Secret, error)
+	// NodeSecret returns the secret of the nfn-agent certificate of the node, it is called by the
+	// nfn-operator which serves the certificate to the nfn-agent of the node
+	NodeSecret(nodeName string) (*kapi.Secret, error)
+	// CNISecret returns the secret of the CNI server certificate
+	CNISecret() (*kapi.Secret, error)
+	// Run issues and rotates the certificates in the nfn-operator until stopCh is closed
+	Run(ipAddr string, stopCh <-chan struct{})
+}
+
+// NewCertProvider returns the certificate provider selected by the NODUS_CERT_PROVIDER env variable
+func NewCertProvider(kubecli *kube.Kube, namespace string) (CertProvider, error) {
+	switch provider := os.Getenv(CertProviderEnv); provider {
+	case "", CertManagerProvider:
+		return &certManagerProvider{kubecli: kubecli, namespace: namespace}, nil
+	case BuiltinProvider:
+		return &builtinProvider{kubecli: kubecli, namespace: namespace}, nil
+	case StaticProvider:
+		name := os.Getenv(StaticCertSecretEnv)
+		if name == "" {
+			name = DefaultCert
+		}
+		return &staticProvider{kubecli: kubecli, namespace: namespace, name: name}, nil
+	default:
+		return nil, fmt.Errorf("unknown certificate provider %s", provider)
+	}
+}
+
+// IsSecretIPUpToDate checks if the certificate of the secret is issued for the IP address
+func IsSecretIPUpToDate(secret *kapi.Secret, ipAddr string) bool {
+	cert, err := parseCertificate(secret.Data[CertFile])
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(ipAddr)
+	for _, certIP := range cert.IPAddresses {
+		if certIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// waitForSecretCondition waits for the secret to exist and to satisfy the condition
+func waitForSecretCondition(kubecli *kube.Kube, namespace, name string, condition func(*kapi.Secret) bool) (*kapi.Secret, error) {
+	var s *kapi.Secret
+	err := wait.PollImmediate(secretPollInterval, secretPollTimeout, func() (bool, error) {
+		secret, err := kubecli.GetSecret(namespace, name)
+		if err != nil || secret == nil {
+			return false, nil
+		}
+		s = secret
+		return condition == nil || condition(secret), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("secret %s/%s not available: %v", namespace, name, err)
+	}
+	return s, nil
+}
+
+// certManagerProvider requests the certificates through the cert-manager Certificate CRs
+type certManagerProvider struct {
+	kubecli   *kube.Kube
+	namespace string
+}
+
+func (p *certManagerProvider) ServerSecret(ipAddr string) (*kapi.Secret, error) {
+	crt, err := GetCert(p.namespace, DefaultCert)
+	if err != nil {
+		return nil, err
+	}
+	if !IsCertIPUpToDate(crt, ipAddr) {
+		// update the IP address of the certificate if required
+		_, sec, err := UpdateCertIP(crt, ipAddr)
+		return sec, err
+	}
+	// wait for secret to be updated by cert-manager
+	return WaitForSecretIP(p.kubecli, crt)
+}
+
+func (p *certManagerProvider) NodeSecret(nodeName string) (*kapi.Secret, error) {
+	_, sec, err := GetNodeCert(p.namespace, nodeName)
+	return sec, err
+}
+
+func (p *certManagerProvider) CNISecret() (*kapi.Secret, error) {
+	return WaitForSecret(p.kubecli, p.namespace, DefaultCniCert)
+}
+
+// Run requests the certificates of the nfn-agents of new nodes, cert-manager renews the certificates
+func (p *certManagerProvider) Run(ipAddr string, stopCh <-chan struct{}) {
+	go wait.Until(func() { ensureNodeCerts(p.kubecli, p.namespace) }, caSyncInterval, stopCh)
+}
+
+// builtinProvider uses the secrets issued by the CA of the nfn-operator
+type builtinProvider struct {
+	kubecli   *kube.Kube
+	namespace string
+}
+
+func (p *builtinProvider) ServerSecret(ipAddr string) (*kapi.Secret, error) {
+	return waitForSecretCondition(p.kubecli, p.namespace, DefaultCert, func(s *kapi.Secret) bool {
+		return IsSecretIPUpToDate(s, ipAddr)
+	})
+}
+
+func (p *builtinProvider) NodeSecret(nodeName string) (*kapi.Secret, error) {
+	return waitForSecretCondition(p.kubecli, p.namespace, NodeCertName(nodeName), nil)
+}
+
+func (p *builtinProvider) CNISecret() (*kapi.Secret, error) {
+	return waitForSecretCondition(p.kubecli, p.namespace, DefaultCniCert, nil)
+}
+
+// Run issues and rotates the certificates with the built-in CA
+func (p *builtinProvider) Run(ipAddr string, stopCh <-chan struct{}) {
+	ca := &builtinCA{client: p.kubecli.KClient, namespace: p.namespace, serverIP: ipAddr}
+	go wait.Until(ca.sync, caSyncInterval, stopCh)
+}
+
+// staticProvider uses the certificate of a secret supplied by the user for the nfn-operator, the
+// nfn-agents use the nodus-agent-<node name> secret of their node supplied by the user
+type staticProvider struct {
+	kubecli   *kube.Kube
+	namespace string
+	name      string
+}
+
+func (p *staticProvider) ServerSecret(ipAddr string) (*kapi.Secret, error) {
+	sec, err := WaitForSecret(p.kubecli, p.namespace, p.name)
+	if err != nil {
+		return nil, err
+	}
+	if !IsSecretIPUpToDate(sec, ipAddr) {
+		return nil, fmt.Errorf("the certificate of secret %s/%s is not issued for %s", p.namespace, p.name, ipAddr)
+	}
+	return sec, nil
+}
+
+// NodeSecret returns the secret of the node, the shared certificate is not issued for a node and
+// is never served to the nfn-agents
+func (p *staticProvider) NodeSecret(nodeName string) (*kapi.Secret, error) {
+	name := NodeCertName(nodeName)
+	sec, err := p.kubecli.GetSecret(p.namespace, name)
+	if err != nil || sec == nil {
+		return nil, fmt.Errorf("secret %s/%s of node %s not supplied: %v", p.namespace, name, nodeName, err)
+	}
+	return sec, nil
+}
+
+// CNISecret returns the nodus-cni-cert secret supplied by the user, cnishim reads it as well
+func (p *staticProvider) CNISecret() (*kapi.Secret, error) {
+	return WaitForSecret(p.kubecli, p.namespace, DefaultCniCert)
+}
+
+// Run does nothing, the user renews the certificate
+func (p *staticProvider) Run(ipAddr string, stopCh <-chan struct{}) {}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync"
+
+	kapi "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+)
+
+// CertReloader holds the TLS material of a secret and reloads it when the secret changes, when
+// cert-manager or the built-in CA renews the certificate or the user replaces a static secret.
+// The TLS configs it creates always use the current material.
+type CertReloader struct {
+	sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+	// OnUpdate is called with the secret every time it is reloaded by Watch
+	OnUpdate func(secret *kapi.Secret)
+}
+
+// NewCertReloader creates a CertReloader loaded from the secret
+func NewCertReloader(secret *kapi.Secret) (*CertReloader, error) {
+	r := &CertReloader{}
+	if err := r.Update(secret); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Update loads the TLS material of the secret
+func (r *CertReloader) Update(secret *kapi.Secret) error {
+	cert, pool, err := LoadCertsFromSecret(secret)
+	if err != nil {
+		return err
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.cert = cert
+	r.pool = pool
+	return nil
+}
+
+func (r *CertReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.RLock()
+	defer r.RUnlock()
+	return r.cert, r.pool
+}
+
+// Watch reloads the TLS material every time the secret changes until stopCh is closed
+func (r *CertReloader) Watch(client kubernetes.Interface, namespace, name string, stopCh <-chan struct{}) {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *v1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}))
+
+	reload := func(obj interface{}) {
+		secret, ok := obj.(*kapi.Secret)
+		if !ok {
+			return
+		}
+		if err := r.Update(secret); err != nil {
+			klog.Errorf("Unable to reload the certificate from secret %s/%s: %v", namespace, name, err)
+			return
+		}
+		klog.Infof("Reloaded the certificate from secret %s/%s", namespace, name)
+		if r.OnUpdate != nil {
+			r.OnUpdate(secret)
+		}
+	}
+	factory.Core().V1().Secrets().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: reload,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			reload(newObj)
+		},
+	})
+	factory.Start(stopCh)
+}
+
+// ServerTLSConfig creates a server TLS config using the current certificate and CA
+func (r *CertReloader) ServerTLSConfig() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			return CreateTLSConfig(cert, pool, true), nil
+		},
+	}
+}
+
+// ServingTLSConfig creates a server TLS config using the current certificate that doesn't
+// require a client certificate, the clients are authenticated by the server itself
+func (r *CertReloader) ServingTLSConfig() *tls.Config {
+	cert, _ := r.current()
+	config := CreateTLSConfig(cert, nil, false)
+	config.Certificates = nil
+	config.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		cert, _ := r.current()
+		return cert, nil
+	}
+	return config
+}
+
+// ClientTLSConfig creates a client TLS config using the current certificate and CA. The
+// server certificate is verified against the current CA pool in VerifyConnection since
+// RootCAs can't be changed once the config is in use.
+func (r *CertReloader) ClientTLSConfig() *tls.Config {
+	cert, _ := r.current()
+	config := CreateTLSConfig(cert, nil, false)
+	config.Certificates = nil
+	config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		cert, _ := r.current()
+		return cert, nil
+	}
+	config.InsecureSkipVerify = true
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("no server certificate found")
+		}
+		_, pool := r.current()
+		opts := x509.VerifyOptions{
+			Roots:         pool,
+			DNSName:       cs.ServerName,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := cs.PeerCertificates[0].Verify(opts)
+		return err
+	}
+	return config
+}
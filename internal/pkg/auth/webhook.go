@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+
+	kapi "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// WebhookConfigName is a name of the validating webhook configuration of the nfn-operator
+const WebhookConfigName = "nodus-address-validation"
+
+// PublishWebhookCABundle sets the CA bundle of the secret in the webhooks of the validating
+// webhook configuration, the API server verifies the nfn-operator with it
+func PublishWebhookCABundle(client kubernetes.Interface, name string, secret *kapi.Secret) error {
+	configs := client.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+	config, err := configs.Get(context.TODO(), name, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	changed := false
+	for i := range config.Webhooks {
+		if !bytes.Equal(config.Webhooks[i].ClientConfig.CABundle, secret.Data[CAFile]) {
+			config.Webhooks[i].ClientConfig.CABundle = secret.Data[CAFile]
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	_, err = configs.Update(context.TODO(), config, v1.UpdateOptions{})
+	return err
+}
@@ -24,6 +24,8 @@ import (
 const (
 	nfnNetworkAnnotationTag = "k8s.plugin.opnfv.org/nfn-network"
 	ovn4nfvAnnotationTag    = "k8s.plugin.opnfv.org/ovnInterfaces"
+	// ovnNetworkRoutesAnnotationTag holds the routes added to the pod
+	ovnNetworkRoutesAnnotationTag = "ovnNetworkRoutes"
 )
 
 type nfnNetwork struct {
@@ -37,11 +39,18 @@ type nfnNetwork struct {
 }
 
 type OvnNetwork struct {
-	IpAddress      []string `json:"ip_address"`
-	MacAddress     string   `json:"mac_address"`
-	GatewayIP      []string `json:"gateway_ip"`
-	DefaultGateway string   `json:"defaultGateway,omitempty"`
-	Interface      string   `json:"interface"`
+	IpAddress         []string        `json:"ip_address"`
+	MacAddress        string          `json:"mac_address"`
+	GatewayIP         []string        `json:"gateway_ip"`
+	DefaultGateway    string          `json:"defaultGateway,omitempty"`
+	Interface         string          `json:"interface"`
+	Mtu               int             `json:"mtu,omitempty"`
+	TxQueueLen        int             `json:"txqueuelen,omitempty"`
+	Offload           map[string]bool `json:"offload,omitempty"`
+	ProviderInterface string          `json:"provider_interface,omitempty"`
+	AttachType        string          `json:"attach_type,omitempty"`
+	AttachMode        string          `json:"attach_mode,omitempty"`
+	AttachMaster      string          `json:"attach_master,omitempty"`
 }
 
 func parseNfnNetworkObject(nfnnetwork string) (*nfnNetwork, error) {
@@ -231,8 +240,21 @@ func (cr *CNIServerRequest) AddMultipleInterfaces(nfnAnnotation, ovnAnnotation,
 			}
 		}
 
-		klog.Infof("addMultipleInterfaces: ipAddress-%v ovn4nfv-interface-%v cni-ifname-%v", ipAddress, interfaceName, cr.IfName)
-		interfacesArray, err = app.ConfigureInterface(cr.Netns, cr.SandboxID, cr.IfName, namespace, podName, macAddress, ipAddress, gatewayIP, interfaceName, defaultGateway, index, config.Default.MTU, isDefaultGW)
+		mtu := config.Default.MTU
+		if ovnNet.Mtu > 0 {
+			mtu = ovnNet.Mtu
+		}
+		tuning := &app.InterfaceTuning{
+			TxQueueLen:        ovnNet.TxQueueLen,
+			Offload:           ovnNet.Offload,
+			ProviderInterface: ovnNet.ProviderInterface,
+			AttachType:        ovnNet.AttachType,
+			AttachMode:        ovnNet.AttachMode,
+			AttachMaster:      ovnNet.AttachMaster,
+		}
+
+		klog.Infof("addMultipleInterfaces: ipAddress-%v ovn4nfv-interface-%v cni-ifname-%v mtu-%v", ipAddress, interfaceName, cr.IfName, mtu)
+		interfacesArray, err = app.ConfigureInterface(cr.Netns, cr.SandboxID, cr.IfName, namespace, podName, macAddress, ipAddress, gatewayIP, interfaceName, defaultGateway, index, mtu, isDefaultGW, tuning)
 		if err != nil {
 			klog.Errorf("Failed to configure interface in pod: %v", err)
 			return nil
@@ -245,7 +267,7 @@ func (cr *CNIServerRequest) AddMultipleInterfaces(nfnAnnotation, ovnAnnotation,
 				routes = append(routes, &types.Route{Dst: net.IPNet{IP: defaultAddr, Mask: defaultAddrNet.Mask}, GW: net.ParseIP(gateway)})
 			}
 
-			ipConfigs, err := generateIpConfigs(ipAddress, gatewayIP)
+			ipConfigs, err := generateIpConfigs(ipAddress, gatewayIP, len(interfacesArray)-1)
 			if err != nil {
 				return nil
 			}
@@ -256,7 +278,7 @@ func (cr *CNIServerRequest) AddMultipleInterfaces(nfnAnnotation, ovnAnnotation,
 				Routes:     routes,
 			}
 		} else {
-			ipConfigs, err := generateIpConfigs(ipAddress, gatewayIP)
+			ipConfigs, err := generateIpConfigs(ipAddress, gatewayIP, len(interfacesArray)-1)
 			if err != nil {
 				return nil
 			}
@@ -278,7 +300,8 @@ func (cr *CNIServerRequest) AddMultipleInterfaces(nfnAnnotation, ovnAnnotation,
 	return dstResult
 }
 
-func generateIpConfigs(ipAddresses, gatewayIP []string) ([]*current.IPConfig, error) {
+// generateIpConfigs returns the IP configs of the container interface at index ifIndex of the result
+func generateIpConfigs(ipAddresses, gatewayIP []string, ifIndex int) ([]*current.IPConfig, error) {
 	var ipConfigs []*current.IPConfig
 
 	for i, ipAddress := range ipAddresses {
@@ -295,7 +318,7 @@ func generateIpConfigs(ipAddresses, gatewayIP []string) ([]*current.IPConfig, er
 
 		ipConfigs = append(ipConfigs, &current.IPConfig{
 			Version:   ipVersion,
-			Interface: current.Int(1),
+			Interface: current.Int(ifIndex),
 			Address:   net.IPNet{IP: addr, Mask: addrNet.Mask},
 			Gateway:   net.ParseIP(gatewayIP[i]),
 		})
@@ -348,6 +371,22 @@ func (cr *CNIServerRequest) addRoutes(ovnAnnotation string, dstResult types.Resu
 	return dstResult
 }
 
+// storeAnnotations stores the network annotations of the pod of the request
+func (cr *CNIServerRequest) storeAnnotations(annotation map[string]string) {
+	if cr.store == nil {
+		return
+	}
+	stored := make(map[string]string)
+	for _, key := range []string{nfnNetworkAnnotationTag, ovn4nfvAnnotationTag, ovnNetworkRoutesAnnotationTag} {
+		if v, ok := annotation[key]; ok {
+			stored[key] = v
+		}
+	}
+	if err := cr.store.SetPodAnnotations(cr.PodNamespace, cr.PodName, stored); err != nil {
+		klog.Errorf("Unable to store the annotations of pod %s/%s: %v", cr.PodNamespace, cr.PodName, err)
+	}
+}
+
 func (cr *CNIServerRequest) cmdAdd(kclient kubernetes.Interface) ([]byte, error) {
 	klog.Infof("ovn4nfvk8s-cni: cmdAdd")
 	namespace := cr.PodNamespace
@@ -368,9 +407,18 @@ func (cr *CNIServerRequest) cmdAdd(kclient kubernetes.Interface) ([]byte, error)
 				return false, fmt.Errorf("Error - pod not found - %v", err)
 			}
 			klog.Infof("ovn4nfvk8s-cni: cmdAdd Warning - Error while obtaining pod annotations - %v", err)
+			// The pods already known are served while the Kubernetes API is unreachable
+			if cr.store != nil {
+				if stored, ok := cr.store.PodAnnotations(namespace, podname); ok {
+					klog.Infof("ovn4nfvk8s-cni: cmdAdd using the stored pod annotations")
+					annotation = stored
+					return true, nil
+				}
+			}
 			return false, nil
 		}
 		if _, ok := annotation[ovn4nfvAnnotationTag]; ok {
+			cr.storeAnnotations(annotation)
 			return true, nil
 		}
 		return false, nil
@@ -399,7 +447,7 @@ func (cr *CNIServerRequest) cmdAdd(kclient kubernetes.Interface) ([]byte, error)
 
 	result := cr.AddMultipleInterfaces(nfnAnnotation, ovnAnnotation, namespace, podname)
 	//Add Routes to the pod if annotation found for routes
-	ovnRouteAnnotation, ok := annotation["ovnNetworkRoutes"]
+	ovnRouteAnnotation, ok := annotation[ovnNetworkRoutesAnnotationTag]
 	if ok {
 		klog.Infof("ovn4nfvk8s-cni: ovnNetworkRoutes Annotation Found %+v", ovnRouteAnnotation)
 		result = cr.addRoutes(ovnRouteAnnotation, result)
@@ -474,7 +522,16 @@ func (cr *CNIServerRequest) DeleteMultipleInterfaces(ovnAnnotation, namespace, p
 			return nil
 		}
 
-		ifaceName := cr.SandboxID[:14] + strconv.Itoa(i)
+		// The port is looked up from the logical port it was plugged for, the interfaces attached at
+		// pod creation are named from the pod sandbox and the hot-plugged ones from hotplug IDs
+		ifaceName, err := app.HostInterfaceName(fmt.Sprintf("%s_%s_%s", namespace, podName, interfaceName))
+		if err != nil {
+			klog.Errorf("Failed to find the host interface: %v", err)
+		}
+		if ifaceName == "" {
+			// host interfaces are indexed from 1 by AddMultipleInterfaces
+			ifaceName = cr.SandboxID[:14] + strconv.Itoa(i+1)
+		}
 		done, err := app.PlatformSpecificCleanup(ifaceName)
 		if err != nil {
 			klog.Errorf("Teardown error: %v", err)
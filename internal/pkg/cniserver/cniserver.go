@@ -15,8 +15,10 @@ import (
 
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/auth"
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/config"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/nodestate"
 
 	"github.com/gorilla/mux"
+	kapi "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	utilwait "k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
@@ -40,6 +42,7 @@ type CNIServerRequest struct {
 	Netns        string
 	IfName       string
 	CNIConf      *config.NetConf
+	store        *nodestate.Store
 }
 
 type cniServerRequestFunc func(request *CNIServerRequest, k8sclient kubernetes.Interface) ([]byte, error)
@@ -53,9 +56,12 @@ type CNIServer struct {
 	requestFunc  cniServerRequestFunc
 	serverrundir string
 	k8sclient    kubernetes.Interface
+	store        *nodestate.Store
 }
 
-func NewCNIServer(serverRunDir string, k8sclient kubernetes.Interface) *CNIServer {
+// NewCNIServer creates the CNI server, the pod annotations and the certificate are stored in
+// the store to serve the pods while the Kubernetes API is unreachable
+func NewCNIServer(serverRunDir string, k8sclient kubernetes.Interface, store *nodestate.Store) *CNIServer {
 	klog.Infof("Setting up CNI server in nfn-agent")
 	if len(serverRunDir) == 0 {
 		serverRunDir = CNIServerRunDir
@@ -69,34 +75,55 @@ func NewCNIServer(serverRunDir string, k8sclient kubernetes.Interface) *CNIServe
 		return nil
 	}
 
-	// wait for secret to be created
-	sec, err := auth.WaitForSecret(kubecli, namespace, auth.DefaultCniCert)
-	if sec == nil || err != nil {
-		klog.Errorf("Unable to obtain the secret: %v", err)
+	certProvider, err := auth.NewCertProvider(kubecli, namespace)
+	if err != nil {
+		klog.Errorf("Error creating the certificate provider: %v", err)
 		return nil
 	}
 
-	// load certificates from obtained secret
-	cert, pool, err := auth.LoadCertsFromSecret(sec)
+	// use the stored copy of the secret if any, the watch below loads the current one
+	sec, err := nodestate.LoadSecret(nodestate.StateDir, auth.DefaultCniCert)
+	if err != nil {
+		// wait for secret to be created
+		sec, err = certProvider.CNISecret()
+		if sec == nil || err != nil {
+			klog.Errorf("Unable to obtain the secret: %v", err)
+			return nil
+		}
+		saveSecret(sec)
+	}
+
+	// load certificates from obtained secret, they are reloaded when the certificate provider renews them
+	reloader, err := auth.NewCertReloader(sec)
 	if err != nil {
 		klog.Errorf("Error while loading certificate data from secret: %v", err)
 		return nil
 	}
+	reloader.OnUpdate = saveSecret
+	reloader.Watch(kubecli.KClient, namespace, sec.Name, utilwait.NeverStop)
 
 	router := mux.NewRouter()
 	cs := &CNIServer{
 		Server: http.Server{
 			Handler: router,
-			TLSConfig: auth.CreateTLSConfig(cert, pool, true),
+			TLSConfig: reloader.ServerTLSConfig(),
 		},
 		serverrundir: serverRunDir,
 		k8sclient:    k8sclient,
+		store:        store,
 	}
 	router.NotFoundHandler = http.HandlerFunc(http.NotFound)
 	router.HandleFunc("/", cs.handleCNIShimRequest).Methods("POST")
 	return cs
 }
 
+// saveSecret stores a copy of the CNI secret for the CNI shim
+func saveSecret(secret *kapi.Secret) {
+	if err := nodestate.SaveSecret(nodestate.StateDir, secret); err != nil {
+		klog.Errorf("Unable to store the secret %s: %v", secret.Name, err)
+	}
+}
+
 func loadCNIShimArgs(env map[string]string) (map[string]string, error) {
 	cnishimArgs, ok := env["CNI_ARGS"]
 	if !ok {
@@ -177,6 +204,7 @@ func (cs *CNIServer) handleCNIShimRequest(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	req.store = cs.store
 	klog.Infof("Waiting for %s result for CNI server pod %s/%s", req.Command, req.PodNamespace, req.PodName)
 	result, err := cs.requestFunc(req, cs.k8sclient)
 	if err != nil {
@@ -14,11 +14,13 @@ import (
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/cniserver"
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/config"
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/kube"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/nodestate"
 
 	"github.com/containernetworking/cni/pkg/skel"
 	"github.com/containernetworking/cni/pkg/types"
 	"github.com/containernetworking/cni/pkg/types/current"
 	"github.com/sirupsen/logrus"
+	kapi "k8s.io/api/core/v1"
 )
 
 const CNIEndpointURLReq string = "https://dummy/"
@@ -49,12 +51,9 @@ func cniEndpointRequest(args *skel.CmdArgs) *cniserver.CNIEndpointRequest {
 	}
 }
 
-func (ep *Endpoint) sendCNIServerReq(req *cniserver.CNIEndpointRequest) ([]byte, error) {
-	cnireqdata, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("sendCNIServerReq: failed to Marshal CNIShim Req %v:%v", req, err)
-	}
-
+// getCNISecret returns the CNI secret, the copy stored by the nfn-agent is used when the
+// Kubernetes API is unreachable
+func getCNISecret() (*kapi.Secret, error) {
 	// load kubeconfig from file
 	client, err := kube.GetKubeConfigfromFile()
 	if err != nil {
@@ -65,7 +64,25 @@ func (ep *Endpoint) sendCNIServerReq(req *cniserver.CNIEndpointRequest) ([]byte,
 	kubecli := &kube.Kube{KClient: client}
 	sec, err := kubecli.GetSecret(config.Namespace, auth.DefaultCniCert)
 	if err != nil {
-		return nil, fmt.Errorf("sendCNIServerReq: unable to get CNI secret: %v", err)
+		stored, storeErr := nodestate.LoadSecret(nodestate.StateDir, auth.DefaultCniCert)
+		if storeErr != nil {
+			return nil, fmt.Errorf("sendCNIServerReq: unable to get CNI secret: %v", err)
+		}
+		logrus.Infof("sendCNIServerReq: using the stored CNI secret, unable to get it: %v", err)
+		return stored, nil
+	}
+	return sec, nil
+}
+
+func (ep *Endpoint) sendCNIServerReq(req *cniserver.CNIEndpointRequest) ([]byte, error) {
+	cnireqdata, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("sendCNIServerReq: failed to Marshal CNIShim Req %v:%v", req, err)
+	}
+
+	sec, err := getCNISecret()
+	if err != nil {
+		return nil, err
 	}
 
 	// create TLS config from secret
@@ -0,0 +1,143 @@
+package cri
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	runtimeapi "github.com/akraino-edge-stack/icn-nodus/internal/pkg/cri/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// EndpointEnv is the env variable setting the CRI endpoint of the node,
+	// e.g. unix:///run/containerd/containerd.sock
+	EndpointEnv = "NFN_CRI_ENDPOINT"
+
+	unixScheme     = "unix://"
+	requestTimeout = 10 * time.Second
+
+	separator = "://"
+)
+
+// Client is a client for the CRI RuntimeService that implements our common CRI interface,
+// it works with any runtime implementing the CRI API v1
+type Client struct {
+	conn *grpc.ClientConn
+	cli  runtimeapi.RuntimeServiceClient
+}
+
+// sandboxInfo is the part of the verbose sandbox status info used to find the network namespace,
+// containerd and CRI-O both return the pid and the OCI runtime spec of the sandbox
+type sandboxInfo struct {
+	Pid         int `json:"pid"`
+	RuntimeSpec struct {
+		Linux struct {
+			Namespaces []struct {
+				Type string `json:"type"`
+				Path string `json:"path"`
+			} `json:"namespaces"`
+		} `json:"linux"`
+	} `json:"runtimeSpec"`
+}
+
+// NewClient returns new CRI client for the endpoint, the endpoint is a unix socket path with or
+// without the unix:// scheme. The runtime must answer the Version request
+func NewClient(endpoint string) (*Client, error) {
+	if !strings.HasPrefix(endpoint, unixScheme) {
+		endpoint = unixScheme + endpoint
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to the CRI endpoint %s: %v", endpoint, err)
+	}
+	c := &Client{
+		conn: conn,
+		cli:  runtimeapi.NewRuntimeServiceClient(conn),
+	}
+	if _, err := c.cli.Version(ctx, &runtimeapi.VersionRequest{}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("CRI endpoint %s does not serve the CRI API v1: %v", endpoint, err)
+	}
+	return c, nil
+}
+
+// GetPidForContainer returns PID of the sandbox of provided container (ID), the sandbox keeps
+// the network namespace of the pod when the container restarts
+func (c *Client) GetPidForContainer(id string) (int, error) {
+	info, err := c.sandboxInfo(id)
+	if err != nil {
+		return -1, err
+	}
+	if info.Pid <= 0 {
+		return -1, fmt.Errorf("no pid for the sandbox of container %s", id)
+	}
+	return info.Pid, nil
+}
+
+// GetNetnsForContainer returns the path of the network namespace of the sandbox of provided
+// container (ID), as reported by the runtime
+func (c *Client) GetNetnsForContainer(id string) (string, error) {
+	info, err := c.sandboxInfo(id)
+	if err != nil {
+		return "", err
+	}
+	for _, ns := range info.RuntimeSpec.Linux.Namespaces {
+		if ns.Type == "network" && ns.Path != "" {
+			return ns.Path, nil
+		}
+	}
+	if info.Pid > 0 {
+		return fmt.Sprintf("/proc/%d/ns/net", info.Pid), nil
+	}
+	return "", fmt.Errorf("no network namespace for the sandbox of container %s", id)
+}
+
+// sandboxInfo returns the verbose info of the sandbox of the container, the ID of a sandbox
+// is accepted as well
+func (c *Client) sandboxInfo(id string) (*sandboxInfo, error) {
+	if strings.Contains(id, separator) {
+		id = strings.Split(id, separator)[1]
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	sandboxID := id
+	resp, err := c.cli.ListContainers(ctx, &runtimeapi.ListContainersRequest{
+		Filter: &runtimeapi.ContainerFilter{Id: id},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Containers) > 0 {
+		sandboxID = resp.Containers[0].PodSandboxId
+	}
+
+	status, err := c.cli.PodSandboxStatus(ctx, &runtimeapi.PodSandboxStatusRequest{
+		PodSandboxId: sandboxID,
+		Verbose:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	info := &sandboxInfo{}
+	data, ok := status.Info["info"]
+	if !ok {
+		return nil, fmt.Errorf("no verbose info for the sandbox %s of container %s", sandboxID, id)
+	}
+	if err := json.Unmarshal([]byte(data), info); err != nil {
+		return nil, fmt.Errorf("invalid verbose info for the sandbox %s: %v", sandboxID, err)
+	}
+	return info, nil
+}
+
+// Close closes the connection to the runtime
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -2,13 +2,16 @@ package criclient
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/containerd"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/cri"
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/crio"
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/docker"
 
 	kapi "k8s.io/api/core/v1"
+	log "k8s.io/klog"
 )
 
 const (
@@ -19,23 +22,69 @@ const (
 	separator = ":"
 )
 
-// CRIClient is common interface for various CRI clients (containerd, CRI-O, Docker)
+// defaultEndpoints are the CRI endpoints tried when no endpoint is configured
+var defaultEndpoints = map[string]string{
+	criDocker:     "unix:///var/run/cri-dockerd.sock",
+	criContainerd: "unix:///var/run/containerd/containerd.sock",
+	criCrio:       "unix:///var/run/crio/crio.sock",
+}
+
+// CRIClient is common interface for various CRI clients (CRI API, containerd, CRI-O, Docker)
 type CRIClient interface {
 	GetPidForContainer(string) (int, error)
+	// GetNetnsForContainer returns the path of the network namespace of the container
+	GetNetnsForContainer(string) (string, error)
+}
+
+type pidClient interface {
+	GetPidForContainer(string) (int, error)
+}
+
+// procClient returns the network namespace of the container process for the clients
+// without access to the sandbox of the container
+type procClient struct {
+	pidClient
 }
 
-// NewCRIClient returns new CRIClient for specified Node
+func (c *procClient) GetNetnsForContainer(id string) (string, error) {
+	pid, err := c.GetPidForContainer(id)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/proc/%d/ns/net", pid), nil
+}
+
+// NewCRIClient returns new CRIClient for specified Node. The CRI API client is used with the
+// endpoint of the NFN_CRI_ENDPOINT env variable or else with the default endpoint of the
+// runtime of the node, the runtime specific clients are used when it is not reachable
 func NewCRIClient(node *kapi.Node) (CRIClient, error) {
+	if endpoint := os.Getenv(cri.EndpointEnv); endpoint != "" {
+		return cri.NewClient(endpoint)
+	}
+
 	critype := strings.Split(node.Status.NodeInfo.ContainerRuntimeVersion, separator)[0]
+	if endpoint, ok := defaultEndpoints[critype]; ok {
+		c, err := cri.NewClient(endpoint)
+		if err == nil {
+			return c, nil
+		}
+		log.Infof("CRI API not available, using the %s client: %v", critype, err)
+	}
 
+	var c pidClient
+	var err error
 	switch critype {
 	case criDocker:
-		return docker.NewClient()
+		c, err = docker.NewClient()
 	case criContainerd:
-		return containerd.NewClient()
+		c, err = containerd.NewClient()
 	case criCrio:
-		return crio.NewClient()
+		c, err = crio.NewClient()
+	default:
+		return nil, fmt.Errorf("Unsupported CRI type: %s", critype)
 	}
-
-	return nil, fmt.Errorf("Unsupported CRI type: %s", critype)
+	if err != nil {
+		return nil, err
+	}
+	return &procClient{c}, nil
 }
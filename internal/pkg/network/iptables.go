@@ -61,7 +61,7 @@ func FilterRules() ([]IPTablesRule, []IPTablesRule) {
 	var ipv4Rules []IPTablesRule
 	var ipv6Rules []IPTablesRule
 
-	for _, port := range []string{"6081", "6641", "6642", "8080", "50000"} {
+	for _, port := range []string{"6081", "6641", "6642", "8080", "50000", "50001"} {
 		ipv4Rules = append(ipv4Rules, IPTablesRule{
 			"filter", "INPUT", []string{"-p", "tcp", "-m", "tcp", "--dport", port, "-j", "ACCEPT"}})
 		ipv4Rules = append(ipv4Rules, IPTablesRule{
@@ -4,274 +4,457 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/kube"
 	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
-	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/util/retry"
 )
 
 const (
+	// DefaultNetworkPool is the NetworkPool created from the virtual network config file
+	DefaultNetworkPool = "default"
+	// NetworkPoolLabel is the label of the networks created from a pool, its value is the pool name.
+	// On a namespace, it selects the pool of the networks created for the pods and chainings of the
+	// namespace
+	NetworkPoolLabel = "k8s.plugin.opnfv.org/network-pool"
+
+	// NetworkDrainingAnnotation marks a network created from a pool that is about to be deleted
+	// for being idle, the pods are not attached to it anymore
+	NetworkDrainingAnnotation = "k8s.plugin.opnfv.org/draining"
+
+	// the namespace of the networks created from a pool
+	poolNetworkNamespace = "default"
+
+	// legacyImportAnnotation marks the default pool until the subnets taken in the pool ConfigMap
+	// are imported in its status
+	legacyImportAnnotation = "k8s.plugin.opnfv.org/legacy-import"
+
+	// the ConfigMap holding the pool before the NetworkPool CRD
 	networkpoolconfig = "nodus-dynamic-network-pool"
 	networkpoolns     = "kube-system"
+
+	// the ConfigMap and key of the virtual network config of the default pool
+	networkconfig    = "ovn-controller-network"
+	networkconfigkey = "virtual-net-conf.json"
 )
 
-var m sync.Mutex
+// legacyNetworkPool is an entry of the pool ConfigMap
+type legacyNetworkPool struct {
+	PoolNr    int                  `json:"poolnumber"`
+	Network   k8sv1alpha1.IpSubnet `json:"network"`
+	Available bool                 `json:"available"`
+}
 
-//CheckandCreateNetworkPools returns
-func CheckandCreateNetworkPools() error {
+// virtualNetworkConf returns the virtual network config of the default pool. The config is read from
+// the network ConfigMap, the config file mounted by the previous releases is read when the ConfigMap
+// has no config
+func virtualNetworkConf() (spec k8sv1alpha1.NetworkPoolSpec, found bool, err error) {
+	k8sv1ClientSet, err := kube.GetKubeConfig()
+	if err != nil {
+		return spec, false, fmt.Errorf("Error in getting k8s clientset -%v", err)
+	}
+
+	cm, err := k8sv1ClientSet.CoreV1().ConfigMaps(networkpoolns).Get(context.TODO(), networkconfig, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return spec, false, fmt.Errorf("Error in getting the ConfigMap %s -%v", networkconfig, err)
+	}
+	if err == nil {
+		if data, ok := cm.Data[networkconfigkey]; ok {
+			spec, err = ParseVirtualNetworkConf([]byte(data))
+			return spec, err == nil, err
+		}
+	}
 
-	var networkPools []k8sv1alpha1.NetworkPool
 	isExist, err := CheckVirtualNetworkConf()
+	if err != nil || !isExist {
+		return spec, false, err
+	}
+	spec, err = ReadVirtualNetworkConf()
+	return spec, err == nil, err
+}
+
+//CheckandCreateNetworkPools creates the default NetworkPool from the virtual network config, the
+//subnets taken in the pool ConfigMap are kept. An existing NetworkPool keeps its spec, the pools
+//are then only read from the NetworkPool objects
+func CheckandCreateNetworkPools() error {
+	spec, isExist, err := virtualNetworkConf()
 	if err != nil {
 		return err
 	}
 
 	if isExist != true {
-		log.Info("No need to create virtual network", "FileExist", isExist)
+		log.Info("No need to create virtual network", "ConfigExist", isExist)
 		return nil
 	}
 
-	nets, err := CalculateVirtualNetworkSubnet()
+	subnets, err := newPoolSubnets(spec)
 	if err != nil {
 		return err
 	}
 
-	log.Info("Value of the virtual Networks", "network-controller value of len(networks)", len(nets))
-	log.Info("Value of the virtual Networks", "network-controller value of networks", nets)
+	log.Info("Value of the virtual Networks", "network-controller value of len(networks)", subnets.total)
 
-	for k, n := range nets {
-		var np k8sv1alpha1.NetworkPool
-		np.PoolNr = k
-		np.Network = n
-		np.Available = true
-		networkPools = append(networkPools, np)
+	k8sv1alpha1Clientset, err := kube.GetKubev1alpha1Config()
+	if err != nil {
+		log.Error(err, "Error in getting k8s v1alpha1 clientset")
+		return fmt.Errorf("Error in getting k8s v1alpha1 clientset -%v", err)
 	}
 
-	err = CreateNetworkPool(networkPools)
-	if err != nil {
-		return fmt.Errorf("Error in creating CreateNetworkPool - %v", err)
+	pool := &k8sv1alpha1.NetworkPool{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPool",
+			APIVersion: "k8s.plugin.opnfv.org/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        DefaultNetworkPool,
+			Annotations: map[string]string{legacyImportAnnotation: "pending"},
+		},
+		Spec: spec,
+	}
+	_, err = k8sv1alpha1Clientset.NetworkPools().Create(context.TODO(), pool, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		log.Info("NetworkPool already exists", "name", DefaultNetworkPool)
+	} else if err != nil {
+		return fmt.Errorf("Error in creating the NetworkPool - %v", err)
 	}
 
+	// the allocations of the pool ConfigMap are imported until the pool status is updated
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool, err := k8sv1alpha1Clientset.NetworkPools().Get(context.TODO(), DefaultNetworkPool, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if _, ok := pool.Annotations[legacyImportAnnotation]; !ok {
+			return nil
+		}
+		subnets, err := newPoolSubnets(pool.Spec)
+		if err != nil {
+			return err
+		}
+		pool.Status.Allocations = mergeAllocations(pool.Status.Allocations, legacyAllocations(subnets.ipv4))
+		setPoolUsage(pool, subnets.total)
+		if pool, err = k8sv1alpha1Clientset.NetworkPools().UpdateStatus(context.TODO(), pool, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		delete(pool.Annotations, legacyImportAnnotation)
+		if _, err = k8sv1alpha1Clientset.NetworkPools().Update(context.TODO(), pool, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		log.Info("NetworkPool created", "name", pool.Name, "network", pool.Spec.Network, "allocated", pool.Status.Allocated)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error in updating the NetworkPool status - %v", err)
+	}
 	return nil
 }
 
-//CreateNetworkPool returns
-func CreateNetworkPool(np []k8sv1alpha1.NetworkPool) error {
-	var err error
+// mergeAllocations adds the allocations of the subnets not allocated yet, a subnet imported again
+// is not allocated twice
+func mergeAllocations(allocations, imported []k8sv1alpha1.IpSubnet) []k8sv1alpha1.IpSubnet {
+	allocated := make(map[string]bool)
+	for _, a := range allocations {
+		allocated[a.Subnet] = true
+	}
+	for _, a := range imported {
+		if !allocated[a.Subnet] {
+			allocations = append(allocations, a)
+			allocated[a.Subnet] = true
+		}
+	}
+	return allocations
+}
 
-	for id, sn := range np {
-		log.Info("CreateNetworkPool", "id", id, "n.name", sn.PoolNr)
-		log.Info("CreateNetworkPool", "id", id, "n.Subnet", sn.Network)
-		log.Info("CreateNetworkPool", "id", id, "n.Gateway", sn.Available)
+// legacyAllocations returns the subnets of the pool taken in the pool ConfigMap
+func legacyAllocations(nets []k8sv1alpha1.IpSubnet) []k8sv1alpha1.IpSubnet {
+	k8sv1ClientSet, err := kube.GetKubeConfig()
+	if err != nil {
+		return nil
 	}
 
-	npsmap := make(map[string]string)
-	npsByte, err := json.Marshal(np)
+	cm, err := k8sv1ClientSet.CoreV1().ConfigMaps(networkpoolns).Get(context.TODO(), networkpoolconfig, metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("Error in Marshalling nps -%v", err)
+		return nil
 	}
 
-	npsmap["networkpools"] = string(npsByte)
-	log.Info("CreateNetworkPool", "npsmap", npsmap)
+	var nps []legacyNetworkPool
+	if err := json.Unmarshal([]byte(cm.Data["networkpools"]), &nps); err != nil {
+		log.Error(err, "Error in unmarshalling networkpool data")
+		return nil
+	}
 
-	k8sv1ClientSet, err := kube.GetKubeConfig()
-	if err != nil {
-		log.Error(err, "Error in getting k8s v1alpha1 clientset")
-		return fmt.Errorf("Error in getting k8s clientset -%v", err)
+	subnets := make(map[string]bool)
+	for _, n := range nets {
+		subnets[n.Subnet] = true
 	}
 
-	req := &corev1.ConfigMap{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "ConfigMap",
-			APIVersion: "v1",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name: networkpoolconfig,
-		},
-		Data: npsmap,
+	var allocations []k8sv1alpha1.IpSubnet
+	for _, np := range nps {
+		if !np.Available && np.Network.Name != "nil" && subnets[np.Network.Subnet] {
+			allocations = append(allocations, np.Network)
+		}
 	}
+	log.Info("Subnets taken in the network pool ConfigMap", "allocations", allocations)
+	return allocations
+}
 
-	log.Info("Value of the Virtual Network req", "req", req)
-	resp, err := k8sv1ClientSet.CoreV1().ConfigMaps(networkpoolns).Create(context.TODO(), req, metav1.CreateOptions{})
+// setPoolUsage updates the counters of the pool
+func setPoolUsage(pool *k8sv1alpha1.NetworkPool, total int) {
+	networks := make(map[string]bool)
+	for _, a := range pool.Status.Allocations {
+		networks[a.Name] = true
+	}
+	for _, a := range pool.Status.Ipv6Allocations {
+		networks[a.Name] = true
+	}
+	pool.Status.Total = total
+	pool.Status.Allocated = len(networks)
+	pool.Status.Free = total - pool.Status.Allocated
+}
+
+// RefreshPoolStatus updates the counters of the pool to its spec, an invalid spec returns an error
+func RefreshPoolStatus(pool *k8sv1alpha1.NetworkPool) error {
+	subnets, err := newPoolSubnets(pool.Spec)
 	if err != nil {
 		return err
 	}
+	setPoolUsage(pool, subnets.total)
+	return nil
+}
 
-	log.Info("Value of the Virtual Network created", "resp", resp)
+// subnetsOf returns the subnets allocated to the network
+func subnetsOf(allocations []k8sv1alpha1.IpSubnet, name string) []k8sv1alpha1.IpSubnet {
+	var subnets []k8sv1alpha1.IpSubnet
+	for _, a := range allocations {
+		if a.Name == name {
+			subnets = append(subnets, a)
+		}
+	}
+	return subnets
+}
 
-	return err
+// allocateSubnet takes the first free subnet of each IP family of the pool for the network, the subnets
+// already allocated to the network are returned when a concurrent request took them
+func allocateSubnet(pool *k8sv1alpha1.NetworkPool, name string) (ipv4, ipv6 []k8sv1alpha1.IpSubnet, err error) {
+	ipv4 = subnetsOf(pool.Status.Allocations, name)
+	ipv6 = subnetsOf(pool.Status.Ipv6Allocations, name)
+	if len(ipv4) > 0 || len(ipv6) > 0 {
+		return ipv4, ipv6, nil
+	}
 
+	subnets, err := newPoolSubnets(pool.Spec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	i := subnets.nextFree(pool.Status.Allocations, pool.Status.Ipv6Allocations)
+	if i < 0 {
+		setPoolUsage(pool, subnets.total)
+		return nil, nil, fmt.Errorf("all the network pools are taken")
+	}
+	sn4, sn6 := subnets.subnets(i)
+	if sn4 != nil {
+		sn4.Name = name
+		ipv4 = append(ipv4, *sn4)
+		pool.Status.Allocations = append(pool.Status.Allocations, *sn4)
+	}
+	if sn6 != nil {
+		sn6.Name = name
+		ipv6 = append(ipv6, *sn6)
+		pool.Status.Ipv6Allocations = append(pool.Status.Ipv6Allocations, *sn6)
+	}
+	setPoolUsage(pool, subnets.total)
+	return ipv4, ipv6, nil
 }
 
-//UpdateNetworkPool update the config maps
-func UpdateNetworkPool(np []k8sv1alpha1.NetworkPool) error {
-	var err error
+// NamespaceNetworkPool returns the pool selected by the NetworkPoolLabel of the namespace, the
+// default pool when the namespace has no label
+func NamespaceNetworkPool(namespace string) (string, error) {
+	if namespace == "" {
+		return DefaultNetworkPool, nil
+	}
 
-	for id, sn := range np {
-		log.Info("CreateNetworkPool", "id", id, "poolNr", sn.PoolNr)
-		log.Info("CreateNetworkPool", "id", id, "Network", sn.Network)
-		log.Info("CreateNetworkPool", "id", id, "Available", sn.Available)
+	k8sv1ClientSet, err := kube.GetKubeConfig()
+	if err != nil {
+		return "", fmt.Errorf("Error in getting k8s clientset -%v", err)
 	}
 
-	npsmap := make(map[string]string)
-	npsByte, err := json.Marshal(np)
+	ns, err := k8sv1ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("Error in Marshalling nps -%v", err)
+		return "", fmt.Errorf("Error in getting the namespace %s -%v", namespace, err)
 	}
 
-	npsmap["networkpools"] = string(npsByte)
-	log.Info("UpdateNetworkPool", "npsmap", npsmap)
+	if pool := ns.Labels[NetworkPoolLabel]; pool != "" {
+		return pool, nil
+	}
+	return DefaultNetworkPool, nil
+}
 
-	k8sv1ClientSet, err := kube.GetKubeConfig()
+// PoolNetworkKey returns the key of the network of the name created from a pool
+func PoolNetworkKey(name string) types.NamespacedName {
+	return types.NamespacedName{Namespace: poolNetworkNamespace, Name: name}
+}
+
+// NetworkDraining checks if the network created from a pool is marked for deletion
+func NetworkDraining(cr *k8sv1alpha1.Network) bool {
+	if _, ok := cr.Labels[NetworkPoolLabel]; !ok {
+		return false
+	}
+	_, draining := cr.Annotations[NetworkDrainingAnnotation]
+	return draining || !cr.DeletionTimestamp.IsZero()
+}
+
+// CreateNetworkFromPool create the network from the pool, the pool of the namespace is used when the
+// pool is not given. The subnet is allocated in the status of the NetworkPool, a concurrent update
+// makes the allocation conflict and retry. The subnet is released when the network can't be created
+func CreateNetworkFromPool(ns, poolName, namespace, thread string) error {
+	k8sv1alpha1Clientset, err := kube.GetKubev1alpha1Config()
 	if err != nil {
 		log.Error(err, "Error in getting k8s v1alpha1 clientset")
-		return fmt.Errorf("Error in getting k8s clientset -%v", err)
+		return fmt.Errorf("Error in getting k8s v1alpha1 clientset -%v", err)
 	}
 
-	kc := k8sv1ClientSet.CoreV1()
-	name := networkpoolconfig
-	namespace := networkpoolns
-	var cm *corev1.ConfigMap
+	if poolName == "" {
+		poolName, err = NamespaceNetworkPool(namespace)
+		if err != nil {
+			log.Error(err, "Error in getting the network pool of the namespace", "namespace", namespace)
+			return err
+		}
+	}
 
-	r := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		cm, err = kc.ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	log.Info("Allocating a subnet from the network pool", "calling thread", thread, "network name", ns, "NetworkPool", poolName)
+	var ipv4, ipv6 []k8sv1alpha1.IpSubnet
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool, err := k8sv1alpha1Clientset.NetworkPools().Get(context.TODO(), poolName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
 
-		cm.Data = npsmap
+		ipv4, ipv6, err = allocateSubnet(pool, ns)
+		if err != nil {
+			return err
+		}
 
-		_, err = kc.ConfigMaps(namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+		_, err = k8sv1alpha1Clientset.NetworkPools().UpdateStatus(context.TODO(), pool, metav1.UpdateOptions{})
 		return err
 	})
-
-	if r != nil {
-		return fmt.Errorf("status update failed for configmap %s/%s: %v", cm.Namespace, cm.Name, r)
+	if errors.IsNotFound(err) {
+		log.Info("Can't create virtual network as the network pool doesn't exist", "NetworkPool", poolName)
+		return fmt.Errorf("Can't create virtual network as the network pool %s doesn't exist", poolName)
+	}
+	if err != nil {
+		log.Error(err, "Error in allocating a subnet from the network pool")
+		return fmt.Errorf("Error in allocating a subnet from the network pool -%v", err)
 	}
 
-	cm, err = kc.ConfigMaps(cm.Namespace).Get(context.TODO(), cm.Name, metav1.GetOptions{})
+	log.Info("Available network pool to create network", "network name", ns, "ipv4Subnets", ipv4, "ipv6Subnets", ipv6)
+	err = CreateNetwork(ns, ipv4, ipv6, poolName)
 	if err != nil {
-		return err
+		log.Error(err, "Error in the creating the network")
+		// the subnet of a network that exists is released when the network is deleted
+		if _, getErr := k8sv1alpha1Clientset.Networks(poolNetworkNamespace).Get(context.TODO(), ns, metav1.GetOptions{}); errors.IsNotFound(getErr) {
+			cr := &k8sv1alpha1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: ns, Namespace: poolNetworkNamespace, Labels: map[string]string{NetworkPoolLabel: poolName}},
+				Spec:       k8sv1alpha1.NetworkSpec{Ipv4Subnets: ipv4, Ipv6Subnets: ipv6},
+			}
+			if releaseErr := ReleaseNetworkSubnet(cr); releaseErr != nil {
+				log.Error(releaseErr, "Error in releasing the network subnet to the pool", "network name", ns, "NetworkPool", poolName)
+			}
+		}
+		return fmt.Errorf("Error in the creating the network -%v", err)
 	}
 
-	log.Info("Checking the updated config map data", "config map data", cm.Data)
 	return nil
 }
 
-// CreateNetworkFromPool create the network from the pool
-func CreateNetworkFromPool(ns, thread string) error {
-	m.Lock()
-	defer m.Unlock()
-	isExist, err := CheckVirtualNetworkConf()
-	if err != nil {
-		return err
+// NetworkPoolOf returns the NetworkPool the subnets of the network may be taken from, empty for
+// the networks out of the namespace of the pool networks
+func NetworkPoolOf(cr *k8sv1alpha1.Network) string {
+	if cr.Namespace != poolNetworkNamespace {
+		return ""
 	}
-
-	if isExist != true {
-		log.Info("Can't create virtual network as the network pool config doesn't exit", "FileExist", isExist)
-		return fmt.Errorf("Can't create virtual network as the network pool config doesn't exit")
+	if pool := cr.Labels[NetworkPoolLabel]; pool != "" {
+		return pool
 	}
+	// the networks created before the label are in the default pool
+	return DefaultNetworkPool
+}
 
-	k8sv1ClientSet, err := kube.GetKubeConfig()
-	if err != nil {
-		log.Error(err, "Error in getting k8s v1alpha1 clientset")
-		return fmt.Errorf("Error in getting k8s clientset -%v", err)
+// ReleaseNetworkSubnet returns the subnet of a network created from a pool to the pool
+func ReleaseNetworkSubnet(cr *k8sv1alpha1.Network) error {
+	poolName := NetworkPoolOf(cr)
+	if poolName == "" {
+		return nil
 	}
 
-	log.Info("Before entering the shared resources: ", "calling thread", thread)
-	configmaps, err := k8sv1ClientSet.CoreV1().ConfigMaps(networkpoolns).Get(context.TODO(), networkpoolconfig, metav1.GetOptions{})
+	k8sv1alpha1Clientset, err := kube.GetKubev1alpha1Config()
 	if err != nil {
-		log.Error(err, "Error in getting k8s configmaps")
-		return fmt.Errorf("Error in getting k8s configmaps -%v", err)
-	}
-
-	networkPoolData := configmaps.Data
-
-	val, ok := networkPoolData["networkpools"]
-	if !ok {
-		err := fmt.Errorf("Error in getting k8s configmaps")
-		log.Error(err, "Error in getting the data from the configmaps")
-		return err
+		log.Error(err, "Error in getting k8s v1alpha1 clientset")
+		return fmt.Errorf("Error in getting k8s v1alpha1 clientset -%v", err)
 	}
 
-	var nps []k8sv1alpha1.NetworkPool
-	err = json.Unmarshal([]byte(val), &nps)
-	if err != nil {
-		log.Error(err, "Error in unmarshalling networkpool data")
-		return fmt.Errorf("Error in unmarshalling networkpool data -%v", err)
-	}
-
-	poollength := len(nps)
-	var networkConf k8sv1alpha1.IpSubnet
-	for i := 0; i < poollength; i++ {
-		n := &nps[i].Network
-		log.Info("nps pair: ", "field", i, "PoolNr", nps[i].PoolNr)
-		log.Info("nps pair: ", "field", i, "network Name", n.Name)
-		log.Info("nps pair: ", "field", i, "network Subnet", n.Subnet)
-		log.Info("nps pair: ", "field", i, "network Gateway", n.Gateway)
-		log.Info("nps pair: ", "field", i, "network ExcludeIps", n.ExcludeIps)
-		log.Info("nps pair: ", "field", i, "Pool Available", nps[i].Available)
-		if n.Name != "nil" && nps[i].Available == false {
-			if i == poollength-1 {
-				err := fmt.Errorf("all the network pools are taken")
-				log.Error(err, "All the network pools are taken")
-				return err
-			}
-			continue
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool, err := k8sv1alpha1Clientset.NetworkPools().Get(context.TODO(), poolName, metav1.GetOptions{})
+		if err != nil {
+			return err
 		}
 
-		if n.Name == "nil" && nps[i].Available == true {
-			n.Name = ns
-			nps[i].Available = false
-			networkConf = nps[i].Network
-			break
+		if !releaseSubnets(pool, cr) {
+			return nil
 		}
+		_, err = k8sv1alpha1Clientset.NetworkPools().UpdateStatus(context.TODO(), pool, metav1.UpdateOptions{})
+		return err
+	})
+	if errors.IsNotFound(err) {
+		return nil
 	}
+	return err
+}
 
-	//remove this block after debug
-	log.Info("Available network pool to create network", "network name", ns, "network pool", networkConf)
-	modifiednpsByte, err := json.Marshal(nps)
-	if err != nil {
-		log.Error(err, "error in Marshaling the modified network pool data")
-		return fmt.Errorf("Error in Marshalling nps -%v", err)
+// releaseSubnets removes the subnets of the network from the allocations of the pool, it returns
+// false when the pool has none of them
+func releaseSubnets(pool *k8sv1alpha1.NetworkPool, cr *k8sv1alpha1.Network) bool {
+	subnets := make(map[string]bool)
+	for _, sn := range append(append([]k8sv1alpha1.IpSubnet{}, cr.Spec.Ipv4Subnets...), cr.Spec.Ipv6Subnets...) {
+		subnets[sn.Subnet] = true
 	}
-
-	modifiednpsmap := make(map[string]string)
-	modifiednpsmap["networkpools"] = string(modifiednpsByte)
-	log.Info("Modified network pools", "networkpool data", modifiednpsmap)
-	//remove above block after debug
-
-	err = CreateNetwork(networkConf)
-	if err != nil {
-		log.Error(err, "Error in the creating the network")
-		return fmt.Errorf("Error in the creating the network -%v", err)
+	release := func(allocations []k8sv1alpha1.IpSubnet) []k8sv1alpha1.IpSubnet {
+		var kept []k8sv1alpha1.IpSubnet
+		for _, a := range allocations {
+			if a.Name == cr.Name && subnets[a.Subnet] {
+				log.Info("Releasing the network subnet to the pool", "network", cr.Name, "subnet", a.Subnet, "pool", pool.Name)
+				continue
+			}
+			kept = append(kept, a)
+		}
+		return kept
 	}
-
-	err = UpdateNetworkPool(nps)
-	if err != nil {
-		log.Error(err, "Error in the updating the config map")
-		return fmt.Errorf("Error in the updating the config map -%v", err)
+	allocations := release(pool.Status.Allocations)
+	ipv6Allocations := release(pool.Status.Ipv6Allocations)
+	if len(allocations) == len(pool.Status.Allocations) && len(ipv6Allocations) == len(pool.Status.Ipv6Allocations) {
+		return false
 	}
 
-	log.Info("Before existing the shared resources: ", "calling thread", thread)
-	return nil
+	pool.Status.Allocations = allocations
+	pool.Status.Ipv6Allocations = ipv6Allocations
+	setPoolUsage(pool, pool.Status.Total)
+	return true
 }
 
 // CreateNetwork create the k8s request for network creation
-func CreateNetwork(sn k8sv1alpha1.IpSubnet) error {
+func CreateNetwork(name string, ipv4, ipv6 []k8sv1alpha1.IpSubnet, pool string) error {
 	var err error
 
-	log.Info("CreateNetwork", "name", sn.Name)
-	log.Info("CreateNetwork", "Subnet", sn.Subnet)
-	log.Info("CreateNetwork", "Gateway", sn.Gateway)
-	log.Info("CreateNetwork", "ExcludeIps", sn.ExcludeIps)
+	log.Info("CreateNetwork", "name", name)
+	log.Info("CreateNetwork", "Ipv4Subnets", ipv4)
+	log.Info("CreateNetwork", "Ipv6Subnets", ipv6)
 
 	k8sv1alpha1Clientset, err := kube.GetKubev1alpha1Config()
 	if err != nil {
@@ -285,7 +468,8 @@ func CreateNetwork(sn k8sv1alpha1.IpSubnet) error {
 	)
 
 	networklabel := make(map[string]string)
-	networklabel["net"] = sn.Name
+	networklabel["net"] = name
+	networklabel[NetworkPoolLabel] = pool
 
 	req := &k8sv1alpha1.Network{
 		TypeMeta: metav1.TypeMeta{
@@ -293,24 +477,26 @@ func CreateNetwork(sn k8sv1alpha1.IpSubnet) error {
 			APIVersion: "k8s.plugin.opnfv.org/v1alpha1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   sn.Name,
+			Name:   name,
 			Labels: networklabel,
 		},
 		Spec: k8sv1alpha1.NetworkSpec{
 			CniType: "ovn4nfv",
-			Ipv4Subnets: []k8sv1alpha1.IpSubnet{
-				{
-					Name:       sn.Name,
-					Subnet:     sn.Subnet,
-					Gateway:    sn.Gateway,
-					ExcludeIps: sn.ExcludeIps,
-				},
-			},
+			// ipv4Subnets is required, an IPv6 only network has an empty list
+			Ipv4Subnets: append([]k8sv1alpha1.IpSubnet{}, ipv4...),
+			Ipv6Subnets: ipv6,
 		},
 	}
 
 	log.Info("Value of the Virtual Network req", "req", req)
-	resp, err = k8sv1alpha1Clientset.Networks("default").Create(context.TODO(), req, metav1.CreateOptions{})
+	resp, err = k8sv1alpha1Clientset.Networks(poolNetworkNamespace).Create(context.TODO(), req, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		// created by a concurrent request for the same network
+		resp, err = k8sv1alpha1Clientset.Networks(poolNetworkNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err == nil && resp.Status.State == k8sv1alpha1.Created {
+			return nil
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -318,10 +504,10 @@ func CreateNetwork(sn k8sv1alpha1.IpSubnet) error {
 	log.Info("Value of the Virtual Network created", "resp", resp)
 
 	status := resp.Status
-	w, err = k8sv1alpha1Clientset.Networks("default").Watch(context.TODO(), metav1.ListOptions{
+	w, err = k8sv1alpha1Clientset.Networks(poolNetworkNamespace).Watch(context.TODO(), metav1.ListOptions{
 		Watch:           true,
 		ResourceVersion: resp.ResourceVersion,
-		FieldSelector:   fields.Set{"metadata.name": sn.Name}.AsSelector().String(),
+		FieldSelector:   fields.Set{"metadata.name": name}.AsSelector().String(),
 		LabelSelector:   labels.SelectorFromSet(networklabel).String(),
 	})
 	if err != nil {
@@ -336,13 +522,13 @@ func CreateNetwork(sn k8sv1alpha1.IpSubnet) error {
 					return
 				}
 				resp = events.Object.(*k8sv1alpha1.Network)
-				log.Info("Network Status", "network name", sn.Name, "network status", resp.Status.State)
+				log.Info("Network Status", "network name", name, "network status", resp.Status.State)
 				status = resp.Status
 				if resp.Status.State != "" {
 					w.Stop()
 				}
 			case <-time.After(5 * time.Second):
-				log.Info("timeout to wait for Network active", "network name", sn.Name)
+				log.Info("timeout to wait for Network active", "network name", name)
 				w.Stop()
 			}
 		}
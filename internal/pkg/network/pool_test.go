@@ -0,0 +1,152 @@
+package network
+
+import (
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// allocation returns an allocation of the subnet to the network
+func allocation(name, subnet string) k8sv1alpha1.IpSubnet {
+	return k8sv1alpha1.IpSubnet{Name: name, Subnet: subnet}
+}
+
+// subnetNames returns the subnets of the allocations
+func subnetNames(allocations []k8sv1alpha1.IpSubnet) []string {
+	var subnets []string
+	for _, a := range allocations {
+		subnets = append(subnets, a.Subnet)
+	}
+	return subnets
+}
+
+var _ = Describe("Test network pool", func() {
+	table.DescribeTable("allocates the first free subnets",
+		func(spec k8sv1alpha1.NetworkPoolSpec, allocations, ipv6Allocations []k8sv1alpha1.IpSubnet, ipv4, ipv6 []string, free int) {
+			pool := &k8sv1alpha1.NetworkPool{
+				ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+				Spec:       spec,
+				Status:     k8sv1alpha1.NetworkPoolStatus{Allocations: allocations, Ipv6Allocations: ipv6Allocations},
+			}
+			sn4, sn6, err := allocateSubnet(pool, "net")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subnetNames(sn4)).To(Equal(ipv4))
+			Expect(subnetNames(sn6)).To(Equal(ipv6))
+			for _, sn := range append(sn4, sn6...) {
+				Expect(sn.Name).To(Equal("net"))
+			}
+			Expect(subnetNames(pool.Status.Allocations)).To(ContainElements(ipv4))
+			Expect(subnetNames(pool.Status.Ipv6Allocations)).To(ContainElements(ipv6))
+			Expect(pool.Status.Free).To(Equal(free))
+		},
+		table.Entry("IPv4 empty pool", k8sv1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22"},
+			nil, nil,
+			[]string{"172.30.16.0/24"}, nil, 3),
+		table.Entry("IPv4 pool with a released subnet", k8sv1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22"},
+			[]k8sv1alpha1.IpSubnet{allocation("a", "172.30.16.0/24"), allocation("c", "172.30.18.0/24")}, nil,
+			[]string{"172.30.17.0/24"}, nil, 1),
+		table.Entry("IPv6 pool", k8sv1alpha1.NetworkPoolSpec{Network: "fd00:10::/32"},
+			nil, []k8sv1alpha1.IpSubnet{allocation("a", "fd00:10::/64")},
+			nil, []string{"fd00:10:0:1::/64"}, maxIPv6PoolSubnets-2),
+		table.Entry("IPv6 pool with an allocation out of the network", k8sv1alpha1.NetworkPoolSpec{Network: "fd00:10::/120"},
+			nil, []k8sv1alpha1.IpSubnet{allocation("a", "fd00:20::/122"), allocation("b", "fd00:10::/122")},
+			nil, []string{"fd00:10::40/122"}, 1),
+		table.Entry("dual-stack pool", k8sv1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22", Ipv6Network: "fd00:10::/48"},
+			[]k8sv1alpha1.IpSubnet{allocation("a", "172.30.16.0/24")}, []k8sv1alpha1.IpSubnet{allocation("a", "fd00:10::/64")},
+			[]string{"172.30.17.0/24"}, []string{"fd00:10:0:1::/64"}, 2),
+		table.Entry("dual-stack pool with an IPv6 subnet taken", k8sv1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22", Ipv6Network: "fd00:10::/48"},
+			nil, []k8sv1alpha1.IpSubnet{allocation("a", "fd00:10::/64")},
+			[]string{"172.30.17.0/24"}, []string{"fd00:10:0:1::/64"}, 2),
+	)
+
+	It("returns the subnets already allocated to the network", func() {
+		pool := &k8sv1alpha1.NetworkPool{
+			Spec: k8sv1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22", Ipv6Network: "fd00:10::/48"},
+			Status: k8sv1alpha1.NetworkPoolStatus{
+				Allocations:     []k8sv1alpha1.IpSubnet{allocation("net", "172.30.18.0/24")},
+				Ipv6Allocations: []k8sv1alpha1.IpSubnet{allocation("net", "fd00:10:0:2::/64")},
+			},
+		}
+		sn4, sn6, err := allocateSubnet(pool, "net")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subnetNames(sn4)).To(Equal([]string{"172.30.18.0/24"}))
+		Expect(subnetNames(sn6)).To(Equal([]string{"fd00:10:0:2::/64"}))
+		Expect(pool.Status.Allocations).To(HaveLen(1))
+		Expect(pool.Status.Ipv6Allocations).To(HaveLen(1))
+	})
+
+	table.DescribeTable("fails when the pool is exhausted",
+		func(spec k8sv1alpha1.NetworkPoolSpec) {
+			pool := &k8sv1alpha1.NetworkPool{Spec: spec}
+			for i := 0; i < 4; i++ {
+				_, _, err := allocateSubnet(pool, string(rune('a'+i)))
+				Expect(err).NotTo(HaveOccurred())
+			}
+			_, _, err := allocateSubnet(pool, "net")
+			Expect(err).To(MatchError("all the network pools are taken"))
+			Expect(pool.Status.Allocated).To(Equal(4))
+			Expect(pool.Status.Free).To(Equal(0))
+		},
+		table.Entry("IPv4 pool", k8sv1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22"}),
+		table.Entry("IPv6 pool", k8sv1alpha1.NetworkPoolSpec{Network: "fd00:10::/120"}),
+		table.Entry("dual-stack pool", k8sv1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22", Ipv6Network: "fd00:10::/120"}),
+	)
+
+	It("releases the subnets of the network only", func() {
+		pool := &k8sv1alpha1.NetworkPool{Spec: k8sv1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22", Ipv6Network: "fd00:10::/48"}}
+		Expect(RefreshPoolStatus(pool)).To(Succeed())
+		var networks []*k8sv1alpha1.Network
+		for _, name := range []string{"a", "b"} {
+			sn4, sn6, err := allocateSubnet(pool, name)
+			Expect(err).NotTo(HaveOccurred())
+			networks = append(networks, &k8sv1alpha1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       k8sv1alpha1.NetworkSpec{Ipv4Subnets: sn4, Ipv6Subnets: sn6},
+			})
+		}
+		Expect(pool.Status.Free).To(Equal(2))
+
+		Expect(releaseSubnets(pool, networks[0])).To(BeTrue())
+		Expect(subnetNames(pool.Status.Allocations)).To(Equal([]string{"172.30.17.0/24"}))
+		Expect(subnetNames(pool.Status.Ipv6Allocations)).To(Equal([]string{"fd00:10:0:1::/64"}))
+		Expect(pool.Status.Free).To(Equal(3))
+		Expect(releaseSubnets(pool, networks[0])).To(BeFalse())
+
+		// a network of another name with the same subnets doesn't release them
+		other := networks[1].DeepCopy()
+		other.Name = "c"
+		Expect(releaseSubnets(pool, other)).To(BeFalse())
+
+		sn4, sn6, err := allocateSubnet(pool, "c")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subnetNames(sn4)).To(Equal([]string{"172.30.16.0/24"}))
+		Expect(subnetNames(sn6)).To(Equal([]string{"fd00:10::/64"}))
+	})
+
+	table.DescribeTable("checks if the pool networks are draining",
+		func(labels, annotations map[string]string, deleting, draining bool) {
+			cr := &k8sv1alpha1.Network{ObjectMeta: metav1.ObjectMeta{Name: "net", Labels: labels, Annotations: annotations}}
+			if deleting {
+				now := metav1.Now()
+				cr.DeletionTimestamp = &now
+			}
+			Expect(NetworkDraining(cr)).To(Equal(draining))
+		},
+		table.Entry("pool network in use", map[string]string{NetworkPoolLabel: "default"}, nil, false, false),
+		table.Entry("marked pool network", map[string]string{NetworkPoolLabel: "default"},
+			map[string]string{NetworkDrainingAnnotation: "2026-10-19T10:00:00Z"}, false, true),
+		table.Entry("pool network being deleted", map[string]string{NetworkPoolLabel: "default"}, nil, true, true),
+		table.Entry("network out of a pool", nil, map[string]string{NetworkDrainingAnnotation: "true"}, true, false),
+	)
+
+	It("imports the legacy allocations once", func() {
+		allocations := []k8sv1alpha1.IpSubnet{allocation("a", "172.30.16.0/24")}
+		imported := []k8sv1alpha1.IpSubnet{allocation("a", "172.30.16.0/24"), allocation("b", "172.30.17.0/24")}
+		allocations = mergeAllocations(allocations, imported)
+		Expect(subnetNames(allocations)).To(Equal([]string{"172.30.16.0/24", "172.30.17.0/24"}))
+		Expect(mergeAllocations(allocations, imported)).To(Equal(allocations))
+	})
+})
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
+	"net"
 	"os"
 	"strconv"
 
@@ -19,9 +21,23 @@ type Config struct {
 	SubnetLen uint
 }
 
+// netConf is the virtual network config file, the IPv4 fields are the ones of Config
+type netConf struct {
+	Network       string
+	SubnetMin     string
+	SubnetMax     string
+	SubnetLen     uint
+	IPv6Network   string
+	IPv6SubnetLen uint
+}
+
 const netConfPath = "/etc/subnet/virtual-net-conf.json"
 const virtualnetwork = "virtual-net"
 
+// maxIPv6PoolSubnets limits the number of subnets of an IPv6 pool, an IPv6 supernet holds far more
+// subnets than networks are ever created and the total of the pool status is an int
+const maxIPv6PoolSubnets = 1 << 30
+
 // ParseConfig return network
 func ParseConfig(s string) ([]v1alpha1.IpSubnet, error) {
 	cfg := new(Config)
@@ -30,6 +46,128 @@ func ParseConfig(s string) ([]v1alpha1.IpSubnet, error) {
 		return nil, err
 	}
 
+	return cfg.subnets()
+}
+
+// poolSubnets are the subnets of the virtual networks of a NetworkPool. In a dual-stack pool, the
+// subnets with the same index are given to the same network. The IPv6 subnets are computed from their
+// index, an IPv6 network holds too many subnets to list them
+type poolSubnets struct {
+	ipv4  []v1alpha1.IpSubnet
+	ipv6  *ipv6Subnets
+	total int
+}
+
+// newPoolSubnets returns the subnets of the NetworkPool spec
+func newPoolSubnets(spec v1alpha1.NetworkPoolSpec) (*poolSubnets, error) {
+	_, ipNet, err := net.ParseCIDR(spec.Network)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pool network %s: %v", spec.Network, err)
+	}
+	if ipNet.IP.To4() == nil {
+		if spec.Ipv6Network != "" {
+			return nil, fmt.Errorf("pool network %s is an IPv6 network, the paired IPv6 network must not be set", spec.Network)
+		}
+		ipv6, err := newIPv6Subnets(ipNet, spec.SubnetLen)
+		if err != nil {
+			return nil, err
+		}
+		return &poolSubnets{ipv6: ipv6, total: ipv6.count}, nil
+	}
+
+	ipv4, err := ipv4PoolSubnets(ipNet, spec)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Ipv6Network == "" {
+		return &poolSubnets{ipv4: ipv4, total: len(ipv4)}, nil
+	}
+
+	_, ipv6Net, err := net.ParseCIDR(spec.Ipv6Network)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pool IPv6 network %s: %v", spec.Ipv6Network, err)
+	}
+	if ipv6Net.IP.To4() != nil {
+		return nil, fmt.Errorf("pool IPv6 network %s is not an IPv6 network", spec.Ipv6Network)
+	}
+	ipv6, err := newIPv6Subnets(ipv6Net, spec.Ipv6SubnetLen)
+	if err != nil {
+		return nil, err
+	}
+	// each network gets a subnet of both families
+	total := len(ipv4)
+	if ipv6.count < total {
+		total = ipv6.count
+	}
+	return &poolSubnets{ipv4: ipv4[:total], ipv6: ipv6, total: total}, nil
+}
+
+// subnets returns the IPv4 and IPv6 subnets of the index, the subnets of a family the pool has not
+// are nil
+func (p *poolSubnets) subnets(i int) (ipv4, ipv6 *v1alpha1.IpSubnet) {
+	if p.ipv4 != nil {
+		sn := p.ipv4[i]
+		ipv4 = &sn
+	}
+	if p.ipv6 != nil {
+		sn := p.ipv6.subnet(i)
+		ipv6 = &sn
+	}
+	return ipv4, ipv6
+}
+
+// nextFree returns the lowest index whose subnets are not in the allocations, -1 when all the
+// subnets are allocated. Only the indexes of the allocations are visited
+func (p *poolSubnets) nextFree(ipv4Allocations, ipv6Allocations []v1alpha1.IpSubnet) int {
+	used := make(map[int]bool)
+	if p.ipv4 != nil {
+		index := make(map[string]int, len(p.ipv4))
+		for i, sn := range p.ipv4 {
+			index[sn.Subnet] = i
+		}
+		for _, a := range ipv4Allocations {
+			if i, ok := index[a.Subnet]; ok {
+				used[i] = true
+			}
+		}
+	}
+	if p.ipv6 != nil {
+		for _, a := range ipv6Allocations {
+			if i, ok := p.ipv6.index(a.Subnet); ok && i < p.total {
+				used[i] = true
+			}
+		}
+	}
+	for i := 0; i < p.total; i++ {
+		if !used[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+// ipv4PoolSubnets returns the IPv4 subnets of the pool
+func ipv4PoolSubnets(ipNet *net.IPNet, spec v1alpha1.NetworkPoolSpec) ([]v1alpha1.IpSubnet, error) {
+	var err error
+	cfg := &Config{
+		Network:   ip.FromIPNet(ipNet),
+		SubnetLen: spec.SubnetLen,
+	}
+	if spec.SubnetMin != "" {
+		if cfg.SubnetMin, err = ip.ParseIP4(spec.SubnetMin); err != nil {
+			return nil, fmt.Errorf("invalid SubnetMin %s: %v", spec.SubnetMin, err)
+		}
+	}
+	if spec.SubnetMax != "" {
+		if cfg.SubnetMax, err = ip.ParseIP4(spec.SubnetMax); err != nil {
+			return nil, fmt.Errorf("invalid SubnetMax %s: %v", spec.SubnetMax, err)
+		}
+	}
+	return cfg.subnets()
+}
+
+// subnets splits the network of the config into the subnets of the virtual networks
+func (cfg *Config) subnets() ([]v1alpha1.IpSubnet, error) {
 	log.V(1).Info("Value of the config", "cfg", cfg)
 
 	if cfg.SubnetLen > 0 {
@@ -63,6 +201,15 @@ func ParseConfig(s string) ([]v1alpha1.IpSubnet, error) {
 
 	subnetSize := ip.IP4(1 << (32 - cfg.SubnetLen))
 
+	// the subnets run from SubnetMin to SubnetMax when they are set, else over the whole network
+	first, last := cfg.Network.IP, cfg.Network.Next().IP-subnetSize
+	if cfg.SubnetMin != ip.IP4(0) {
+		first = cfg.SubnetMin
+	}
+	if cfg.SubnetMax != ip.IP4(0) {
+		last = cfg.SubnetMax
+	}
+
 	if cfg.SubnetMin == ip.IP4(0) {
 		// skip over the first subnet otherwise it causes problems. e.g.
 		// if Network is 10.100.0.0/16, having an interface with 10.0.0.0
@@ -88,9 +235,13 @@ func ParseConfig(s string) ([]v1alpha1.IpSubnet, error) {
 		return nil, fmt.Errorf("SubnetMax is not on a SubnetLen boundary: %v", cfg.SubnetMax)
 	}
 
-	currentIP := cfg.Network.IP
+	if first > last {
+		return nil, fmt.Errorf("SubnetMin %v is after SubnetMax %v", first, last)
+	}
+
+	currentIP := first
 	var vn []v1alpha1.IpSubnet
-	for i := 0; currentIP != cfg.Network.Next().IP; i++ {
+	for i := 0; currentIP <= last && currentIP >= first; i++ {
 		var n v1alpha1.IpSubnet
 		n.Name = "nil"
 		n.Subnet = fmt.Sprintf("%s/%s", currentIP, strconv.FormatUint(uint64(cfg.SubnetLen), 10))
@@ -104,10 +255,86 @@ func ParseConfig(s string) ([]v1alpha1.IpSubnet, error) {
 		return nil, fmt.Errorf("value of virtual network is nil, user subnet is not right")
 	}
 
-	log.Info("Value of the virtual network slice", "virtual networks", vn)
+	log.V(1).Info("Value of the virtual network slice", "virtual networks", vn)
 	return vn, nil
 }
 
+// ipv6Subnets are the subnets of subnetLen of an IPv6 network, the first address of a subnet is its
+// gateway and the second one is excluded, as for the IPv4 subnets
+type ipv6Subnets struct {
+	base      *big.Int
+	subnetLen uint
+	count     int
+}
+
+// newIPv6Subnets splits the IPv6 network into subnets of subnetLen
+func newIPv6Subnets(ipNet *net.IPNet, subnetLen uint) (*ipv6Subnets, error) {
+	prefixLen, _ := ipNet.Mask.Size()
+	if subnetLen == 0 {
+		// Default to a /64 for each virtual network, or split the network into four
+		if prefixLen <= 62 {
+			subnetLen = 64
+		} else {
+			subnetLen = uint(prefixLen) + 2
+		}
+	}
+	if subnetLen > 126 {
+		return nil, errors.New("IPv6 SubnetLen must be less than /127")
+	}
+	if subnetLen < uint(prefixLen)+2 {
+		return nil, errors.New("IPv6 Network must be able to accommodate at least four subnets")
+	}
+
+	count := maxIPv6PoolSubnets
+	if bits := subnetLen - uint(prefixLen); bits < 30 {
+		count = 1 << bits
+	}
+	return &ipv6Subnets{
+		base:      new(big.Int).SetBytes(ipNet.IP.To16()),
+		subnetLen: subnetLen,
+		count:     count,
+	}, nil
+}
+
+// subnet returns the subnet of the index
+func (s *ipv6Subnets) subnet(i int) v1alpha1.IpSubnet {
+	subnet := new(big.Int).Add(s.base, new(big.Int).Lsh(big.NewInt(int64(i)), 128-s.subnetLen))
+	return v1alpha1.IpSubnet{
+		Name:       "nil",
+		Subnet:     fmt.Sprintf("%s/%d", bigToIP(subnet), s.subnetLen),
+		Gateway:    fmt.Sprintf("%s/%d", bigToIP(new(big.Int).Add(subnet, big.NewInt(1))), s.subnetLen),
+		ExcludeIps: bigToIP(new(big.Int).Add(subnet, big.NewInt(2))).String(),
+	}
+}
+
+// index returns the index of the subnet, ok is false when it is not a subnet of the network
+func (s *ipv6Subnets) index(subnet string) (int, bool) {
+	ip, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil || ip.To4() != nil {
+		return 0, false
+	}
+	if ones, _ := ipNet.Mask.Size(); uint(ones) != s.subnetLen || !ip.Equal(ipNet.IP) {
+		return 0, false
+	}
+	offset := new(big.Int).Sub(new(big.Int).SetBytes(ip.To16()), s.base)
+	if offset.Sign() < 0 {
+		return 0, false
+	}
+	i := new(big.Int).Rsh(offset, 128-s.subnetLen)
+	if !i.IsInt64() || i.Int64() >= int64(s.count) {
+		return 0, false
+	}
+	return int(i.Int64()), true
+}
+
+// bigToIP returns the IPv6 address of the integer
+func bigToIP(i *big.Int) net.IP {
+	b := i.Bytes()
+	ip := make(net.IP, net.IPv6len)
+	copy(ip[net.IPv6len-len(b):], b)
+	return ip
+}
+
 // CheckVirtualNetworkConf returns true or false
 func CheckVirtualNetworkConf() (bool, error) {
 	_, err := os.Stat(netConfPath)
@@ -122,17 +349,36 @@ func CheckVirtualNetworkConf() (bool, error) {
 	return true, nil
 }
 
-// CalculateVirtualNetworkSubnet returns virtual networks configuration
-func CalculateVirtualNetworkSubnet() ([]v1alpha1.IpSubnet, error) {
-	netConf, err := ioutil.ReadFile(netConfPath)
+// ReadVirtualNetworkConf returns the NetworkPool spec of the virtual network config file
+func ReadVirtualNetworkConf() (v1alpha1.NetworkPoolSpec, error) {
+	var spec v1alpha1.NetworkPoolSpec
+	data, err := ioutil.ReadFile(netConfPath)
 	if err != nil {
-		return nil, fmt.Errorf("Error in reading the subnet conf file: %v", err.Error())
+		return spec, fmt.Errorf("Error in reading the subnet conf file: %v", err.Error())
 	}
 
-	virtualNets, err := ParseConfig(string(netConf))
-	if err != nil {
-		return nil, fmt.Errorf("Error in Parsing netConfPath file and creating virtual networks : %v", err.Error())
+	return ParseVirtualNetworkConf(data)
+}
+
+// ParseVirtualNetworkConf returns the NetworkPool spec of the virtual network config
+func ParseVirtualNetworkConf(data []byte) (v1alpha1.NetworkPoolSpec, error) {
+	var spec v1alpha1.NetworkPoolSpec
+	cfg := new(netConf)
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return spec, fmt.Errorf("Error in Parsing the virtual network config: %v", err.Error())
 	}
 
-	return virtualNets, nil
+	spec.Network = cfg.Network
+	spec.SubnetLen = cfg.SubnetLen
+	spec.SubnetMin = cfg.SubnetMin
+	spec.SubnetMax = cfg.SubnetMax
+	if spec.Network == "" {
+		// IPv6 only pool
+		spec.Network = cfg.IPv6Network
+		spec.SubnetLen = cfg.IPv6SubnetLen
+	} else {
+		spec.Ipv6Network = cfg.IPv6Network
+		spec.Ipv6SubnetLen = cfg.IPv6SubnetLen
+	}
+	return spec, nil
 }
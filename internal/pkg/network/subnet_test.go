@@ -0,0 +1,112 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	v1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func TestNetwork(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Network Test Suite")
+}
+
+var _ = Describe("Test pool subnets", func() {
+	table.DescribeTable("splits the pool networks",
+		func(spec v1alpha1.NetworkPoolSpec, total int, first4, first6, last6 string) {
+			subnets, err := newPoolSubnets(spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subnets.total).To(Equal(total))
+
+			sn4, sn6 := subnets.subnets(0)
+			if first4 == "" {
+				Expect(sn4).To(BeNil())
+			} else {
+				Expect(sn4.Subnet).To(Equal(first4))
+				Expect(subnets.ipv4).To(HaveLen(total))
+			}
+			if first6 == "" {
+				Expect(sn6).To(BeNil())
+			} else {
+				Expect(sn6.Subnet).To(Equal(first6))
+				_, sn6 = subnets.subnets(total - 1)
+				Expect(sn6.Subnet).To(Equal(last6))
+			}
+		},
+		table.Entry("IPv4 with the default subnet length", v1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22"},
+			4, "172.30.16.0/24", "", ""),
+		table.Entry("IPv4 split into four", v1alpha1.NetworkPoolSpec{Network: "172.30.16.0/26"},
+			4, "172.30.16.0/28", "", ""),
+		table.Entry("IPv4 from SubnetMin to SubnetMax", v1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22", SubnetMin: "172.30.17.0", SubnetMax: "172.30.18.0"},
+			2, "172.30.17.0/24", "", ""),
+		table.Entry("IPv4 from SubnetMin", v1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22", SubnetMin: "172.30.18.0"},
+			2, "172.30.18.0/24", "", ""),
+		table.Entry("IPv6 with the default /64", v1alpha1.NetworkPoolSpec{Network: "fd00:10::/48"},
+			1<<16, "", "fd00:10::/64", "fd00:10:0:ffff::/64"),
+		table.Entry("IPv6 split into four", v1alpha1.NetworkPoolSpec{Network: "fd00:10::/120"},
+			4, "", "fd00:10::/122", "fd00:10::c0/122"),
+		table.Entry("IPv6 capped to the maximum number of subnets", v1alpha1.NetworkPoolSpec{Network: "fd00::/16"},
+			maxIPv6PoolSubnets, "", "fd00::/64", "fd00:0:3fff:ffff::/64"),
+		table.Entry("dual-stack limited by IPv4", v1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22", Ipv6Network: "fd00:10::/48"},
+			4, "172.30.16.0/24", "fd00:10::/64", "fd00:10:0:3::/64"),
+		table.Entry("dual-stack limited by IPv6", v1alpha1.NetworkPoolSpec{Network: "172.30.0.0/16", Ipv6Network: "fd00:10::/120", Ipv6SubnetLen: 122},
+			4, "172.30.0.0/24", "fd00:10::/122", "fd00:10::c0/122"),
+	)
+
+	table.DescribeTable("rejects the invalid pools",
+		func(spec v1alpha1.NetworkPoolSpec) {
+			_, err := newPoolSubnets(spec)
+			Expect(err).To(HaveOccurred())
+		},
+		table.Entry("invalid network", v1alpha1.NetworkPoolSpec{Network: "172.30.16.0"}),
+		table.Entry("IPv4 network too small", v1alpha1.NetworkPoolSpec{Network: "172.30.16.0/29"}),
+		table.Entry("SubnetMin out of the network", v1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22", SubnetMin: "172.30.20.0"}),
+		table.Entry("SubnetMax not on a subnet boundary", v1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22", SubnetMax: "172.30.18.128"}),
+		table.Entry("SubnetMin after SubnetMax", v1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22", SubnetMin: "172.30.18.0", SubnetMax: "172.30.17.0"}),
+		table.Entry("IPv6 network paired with an IPv6 network", v1alpha1.NetworkPoolSpec{Network: "fd00:10::/48", Ipv6Network: "fd00:20::/48"}),
+		table.Entry("IPv4 network as the IPv6 network", v1alpha1.NetworkPoolSpec{Network: "172.30.16.0/22", Ipv6Network: "172.30.32.0/22"}),
+		table.Entry("IPv6 subnet length above /126", v1alpha1.NetworkPoolSpec{Network: "fd00:10::/64", SubnetLen: 127}),
+		table.Entry("IPv6 network holding less than four subnets", v1alpha1.NetworkPoolSpec{Network: "fd00:10::/64", SubnetLen: 65}),
+	)
+
+	table.DescribeTable("computes the index of the IPv6 subnets",
+		func(subnet string, index int, ok bool) {
+			_, ipNet, err := net.ParseCIDR("fd00:10::/48")
+			Expect(err).NotTo(HaveOccurred())
+			subnets, err := newIPv6Subnets(ipNet, 64)
+			Expect(err).NotTo(HaveOccurred())
+
+			i, found := subnets.index(subnet)
+			Expect(found).To(Equal(ok))
+			if ok {
+				Expect(i).To(Equal(index))
+				Expect(subnets.subnet(i).Subnet).To(Equal(subnet))
+			}
+		},
+		table.Entry("first subnet", "fd00:10::/64", 0, true),
+		table.Entry("second subnet", "fd00:10:0:1::/64", 1, true),
+		table.Entry("last subnet", "fd00:10:0:ffff::/64", 1<<16-1, true),
+		table.Entry("subnet of another network", "fd00:11::/64", 0, false),
+		table.Entry("subnet below the network", "fd00:f::/64", 0, false),
+		table.Entry("other subnet length", "fd00:10::/80", 0, false),
+		table.Entry("IPv4 subnet", "172.30.16.0/24", 0, false),
+		table.Entry("invalid subnet", "fd00:10::", 0, false),
+	)
+
+	It("sets the gateway and the excluded address of the IPv6 subnets", func() {
+		_, ipNet, err := net.ParseCIDR("fd00:10::/48")
+		Expect(err).NotTo(HaveOccurred())
+		subnets, err := newIPv6Subnets(ipNet, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		sn := subnets.subnet(2)
+		Expect(sn.Subnet).To(Equal("fd00:10:0:2::/64"))
+		Expect(sn.Gateway).To(Equal("fd00:10:0:2::1/64"))
+		Expect(sn.ExcludeIps).To(Equal("fd00:10:0:2::2"))
+	})
+})
@@ -0,0 +1,110 @@
+package network
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// UsageThresholdEnv sets the percentage of the addresses of a subnet above which the usage is reported
+	UsageThresholdEnv = "NFN_IP_USAGE_THRESHOLD"
+	// UsageInterval is the period of the IP address usage accounting of the networks
+	UsageInterval = time.Minute
+	// UsageHighReason is the reason of the event raised when a subnet crosses the usage threshold
+	UsageHighReason = "IPUsageHigh"
+
+	defaultUsageThreshold = 80
+)
+
+var (
+	subnetAddresses = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nodus_subnet_ip_addresses",
+		Help: "Number of IP addresses of a network subnet by state: total, allocated, excluded or free",
+	}, []string{"kind", "namespace", "network", "subnet", "state"})
+	subnetUsageRatio = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nodus_subnet_ip_usage_ratio",
+		Help: "Ratio of the allocated IP addresses of a network subnet to its assignable addresses",
+	}, []string{"kind", "namespace", "network", "subnet"})
+	subnetUsageHigh = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nodus_subnet_ip_usage_high",
+		Help: "1 when the IP address usage of a network subnet is above the usage threshold, 0 otherwise",
+	}, []string{"kind", "namespace", "network", "subnet"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(subnetAddresses, subnetUsageRatio, subnetUsageHigh)
+}
+
+// UsageThreshold returns the usage threshold in percent, 80 unless set by UsageThresholdEnv
+func UsageThreshold() int {
+	threshold, err := strconv.Atoi(os.Getenv(UsageThresholdEnv))
+	if err != nil || threshold <= 0 || threshold > 100 {
+		return defaultUsageThreshold
+	}
+	return threshold
+}
+
+// UsageRatio returns the ratio of the allocated addresses to the addresses that can be allocated
+func UsageRatio(u k8sv1alpha1.SubnetUsage) float64 {
+	assignable := u.Allocated + u.Free
+	if assignable <= 0 {
+		return 0
+	}
+	return float64(u.Allocated) / float64(assignable)
+}
+
+// usageHigh checks if the usage of the subnet is above the threshold
+func usageHigh(u k8sv1alpha1.SubnetUsage, threshold int) bool {
+	return UsageRatio(u)*100 >= float64(threshold)
+}
+
+// CrossedUsageThreshold returns the subnets whose usage went above the threshold since the previous usage
+func CrossedUsageThreshold(previous, current []k8sv1alpha1.SubnetUsage) []k8sv1alpha1.SubnetUsage {
+	threshold := UsageThreshold()
+	high := make(map[string]bool)
+	for _, u := range previous {
+		high[u.Subnet] = usageHigh(u, threshold)
+	}
+
+	var crossed []k8sv1alpha1.SubnetUsage
+	for _, u := range current {
+		if usageHigh(u, threshold) && !high[u.Subnet] {
+			crossed = append(crossed, u)
+		}
+	}
+	return crossed
+}
+
+// RecordUsageMetrics exports the usage of the subnets of a network, kind is the kind of the network
+func RecordUsageMetrics(kind, namespace, name string, usages []k8sv1alpha1.SubnetUsage) {
+	threshold := UsageThreshold()
+	for _, u := range usages {
+		subnetAddresses.WithLabelValues(kind, namespace, name, u.Subnet, "total").Set(float64(u.Total))
+		subnetAddresses.WithLabelValues(kind, namespace, name, u.Subnet, "allocated").Set(float64(u.Allocated))
+		subnetAddresses.WithLabelValues(kind, namespace, name, u.Subnet, "excluded").Set(float64(u.Excluded))
+		subnetAddresses.WithLabelValues(kind, namespace, name, u.Subnet, "free").Set(float64(u.Free))
+		subnetUsageRatio.WithLabelValues(kind, namespace, name, u.Subnet).Set(UsageRatio(u))
+		high := 0.0
+		if usageHigh(u, threshold) {
+			high = 1
+		}
+		subnetUsageHigh.WithLabelValues(kind, namespace, name, u.Subnet).Set(high)
+	}
+}
+
+// DeleteUsageMetrics removes the usage metrics of the subnets of a deleted network
+func DeleteUsageMetrics(kind, namespace, name string, usages []k8sv1alpha1.SubnetUsage) {
+	for _, u := range usages {
+		for _, state := range []string{"total", "allocated", "excluded", "free"} {
+			subnetAddresses.DeleteLabelValues(kind, namespace, name, u.Subnet, state)
+		}
+		subnetUsageRatio.DeleteLabelValues(kind, namespace, name, u.Subnet)
+		subnetUsageHigh.DeleteLabelValues(kind, namespace, name, u.Subnet)
+	}
+}
@@ -0,0 +1,65 @@
+package network
+
+import (
+	"os"
+
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+// usage returns the usage of the subnet with the allocated and free addresses
+func usage(subnet string, allocated, free int64) k8sv1alpha1.SubnetUsage {
+	return k8sv1alpha1.SubnetUsage{Subnet: subnet, Allocated: allocated, Free: free}
+}
+
+var _ = Describe("Test address usage", func() {
+	AfterEach(func() {
+		os.Unsetenv(UsageThresholdEnv)
+	})
+
+	table.DescribeTable("computes the usage ratio",
+		func(u k8sv1alpha1.SubnetUsage, ratio float64) {
+			Expect(UsageRatio(u)).To(BeNumerically("~", ratio, 1e-9))
+		},
+		table.Entry("empty subnet", usage("10.0.0.0/24", 0, 254), 0.0),
+		table.Entry("subnet in use", usage("10.0.0.0/24", 127, 127), 0.5),
+		table.Entry("full subnet", usage("10.0.0.0/24", 254, 0), 1.0),
+		table.Entry("subnet without assignable address", usage("10.0.0.0/24", 0, 0), 0.0),
+	)
+
+	table.DescribeTable("reads the usage threshold",
+		func(env string, threshold int) {
+			if env != "" {
+				os.Setenv(UsageThresholdEnv, env)
+			}
+			Expect(UsageThreshold()).To(Equal(threshold))
+		},
+		table.Entry("default", "", 80),
+		table.Entry("set", "50", 50),
+		table.Entry("invalid", "high", 80),
+		table.Entry("out of range", "150", 80),
+	)
+
+	table.DescribeTable("reports the subnets crossing the threshold",
+		func(previous, current []k8sv1alpha1.SubnetUsage, crossed []string) {
+			var subnets []string
+			for _, u := range CrossedUsageThreshold(previous, current) {
+				subnets = append(subnets, u.Subnet)
+			}
+			Expect(subnets).To(Equal(crossed))
+		},
+		table.Entry("first usage above the threshold",
+			nil, []k8sv1alpha1.SubnetUsage{usage("10.0.0.0/24", 220, 34)}, []string{"10.0.0.0/24"}),
+		table.Entry("usage going above the threshold",
+			[]k8sv1alpha1.SubnetUsage{usage("10.0.0.0/24", 100, 154)}, []k8sv1alpha1.SubnetUsage{usage("10.0.0.0/24", 220, 34)}, []string{"10.0.0.0/24"}),
+		table.Entry("usage staying above the threshold",
+			[]k8sv1alpha1.SubnetUsage{usage("10.0.0.0/24", 210, 44)}, []k8sv1alpha1.SubnetUsage{usage("10.0.0.0/24", 220, 34)}, nil),
+		table.Entry("usage going below the threshold",
+			[]k8sv1alpha1.SubnetUsage{usage("10.0.0.0/24", 220, 34)}, []k8sv1alpha1.SubnetUsage{usage("10.0.0.0/24", 100, 154)}, nil),
+		table.Entry("only the subnet above the threshold",
+			nil, []k8sv1alpha1.SubnetUsage{usage("10.0.0.0/24", 100, 154), usage("fd00::/120", 250, 5)}, []string{"fd00::/120"}),
+	)
+})
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nfn
+
+// ProtocolVersion is the version of the nfnNotify protocol implemented by this release.
+// Agents not sending a version in the SubscribeContext speak version 0.
+const ProtocolVersion uint32 = 1
+
+const (
+	// CapabilityAck is set by the agents acknowledging the notifications with the Ack call
+	CapabilityAck = "ack"
+	// CapabilityResync is set by the agents skipping the pod interfaces and routes already
+	// present when the pod state is replayed on subscribe
+	CapabilityResync = "resync"
+	// CapabilityHotplug is set by the agents adding and removing the interfaces of running pods
+	CapabilityHotplug = "hot-plug"
+	// CapabilityAttachType is set by the agents handling the macvlan and ipvlan provider networks
+	CapabilityAttachType = "attach-type"
+)
+
+// Capabilities lists the capabilities of the agent of this release
+var Capabilities = []string{
+	CapabilityAck,
+	CapabilityResync,
+	CapabilityHotplug,
+	CapabilityAttachType,
+}
+
+// RequiredCapability returns the capability the agent needs to handle the notification,
+// or an empty string if every agent handles it
+func RequiredCapability(msg *Notification) string {
+	switch payload := msg.Payload.(type) {
+	case *Notification_ProviderNwCreate:
+		// older agents attach every provider network through the OVS bridge
+		if t := payload.ProviderNwCreate.GetAttachType(); t != "" && t != "ovs" {
+			return CapabilityAttachType
+		}
+	}
+	return ""
+}
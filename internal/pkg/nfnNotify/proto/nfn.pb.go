@@ -32,6 +32,10 @@ type SubscribeContext struct {
 	unknownFields protoimpl.UnknownFields
 
 	NodeName string `protobuf:"bytes,1,opt,name=node_name,json=nodeName,proto3" json:"node_name,omitempty"`
+	// Protocol version of the agent, 0 for the agents before the version negotiation
+	ProtocolVersion uint32 `protobuf:"varint,2,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	// Optional features the agent handles, the operator only sends the matching notifications
+	Capabilities []string `protobuf:"bytes,3,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
 }
 
 func (x *SubscribeContext) Reset() {
@@ -73,6 +77,114 @@ func (x *SubscribeContext) GetNodeName() string {
 	return ""
 }
 
+func (x *SubscribeContext) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *SubscribeContext) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+// AckContext acknowledges the notifications up to seq
+type AckContext struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeName string `protobuf:"bytes,1,opt,name=node_name,json=nodeName,proto3" json:"node_name,omitempty"`
+	Seq      uint64 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+}
+
+func (x *AckContext) Reset() {
+	*x = AckContext{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AckContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AckContext) ProtoMessage() {}
+
+func (x *AckContext) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AckContext.ProtoReflect.Descriptor instead.
+func (*AckContext) Descriptor() ([]byte, []int) {
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AckContext) GetNodeName() string {
+	if x != nil {
+		return x.NodeName
+	}
+	return ""
+}
+
+func (x *AckContext) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+type AckResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AckResponse) Reset() {
+	*x = AckResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AckResponse) ProtoMessage() {}
+
+func (x *AckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AckResponse.ProtoReflect.Descriptor instead.
+func (*AckResponse) Descriptor() ([]byte, []int) {
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{2}
+}
+
 type Notification struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -88,12 +200,14 @@ type Notification struct {
 	//	*Notification_PodAddNetwork
 	//	*Notification_PodDelNetwork
 	Payload isNotification_Payload `protobuf_oneof:"payload"`
+	// Sequence number of the notification, increasing per node
+	Seq uint64 `protobuf:"varint,9,opt,name=seq,proto3" json:"seq,omitempty"`
 }
 
 func (x *Notification) Reset() {
 	*x = Notification{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[1]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -106,7 +220,7 @@ func (x *Notification) String() string {
 func (*Notification) ProtoMessage() {}
 
 func (x *Notification) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[1]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -119,7 +233,7 @@ func (x *Notification) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Notification.ProtoReflect.Descriptor instead.
 func (*Notification) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{1}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Notification) GetCniType() string {
@@ -185,6 +299,13 @@ func (x *Notification) GetPodDelNetwork() *PodDelNetwork {
 	return nil
 }
 
+func (x *Notification) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
 type isNotification_Payload interface {
 	isNotification_Payload()
 }
@@ -238,13 +359,16 @@ type ProviderNetworkCreate struct {
 
 	ProviderNwName string      `protobuf:"bytes,1,opt,name=provider_nw_name,json=providerNwName,proto3" json:"provider_nw_name,omitempty"`
 	Vlan           *VlanInfo   `protobuf:"bytes,2,opt,name=vlan,proto3" json:"vlan,omitempty"`
-	Direct         *DirectInfo `protobuf:"bytes,3,opt,name=direct,proto3" json:"direct,omitempty"` // Add other types supported here beyond vlan
+	Direct         *DirectInfo `protobuf:"bytes,3,opt,name=direct,proto3" json:"direct,omitempty"`
+	// Add other types supported here beyond vlan
+	// Pod attachment type, the OVS bridge is not created for macvlan and ipvlan
+	AttachType string `protobuf:"bytes,4,opt,name=attach_type,json=attachType,proto3" json:"attach_type,omitempty"`
 }
 
 func (x *ProviderNetworkCreate) Reset() {
 	*x = ProviderNetworkCreate{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[2]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -257,7 +381,7 @@ func (x *ProviderNetworkCreate) String() string {
 func (*ProviderNetworkCreate) ProtoMessage() {}
 
 func (x *ProviderNetworkCreate) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[2]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -270,7 +394,7 @@ func (x *ProviderNetworkCreate) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProviderNetworkCreate.ProtoReflect.Descriptor instead.
 func (*ProviderNetworkCreate) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{2}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ProviderNetworkCreate) GetProviderNwName() string {
@@ -294,6 +418,13 @@ func (x *ProviderNetworkCreate) GetDirect() *DirectInfo {
 	return nil
 }
 
+func (x *ProviderNetworkCreate) GetAttachType() string {
+	if x != nil {
+		return x.AttachType
+	}
+	return ""
+}
+
 type ProviderNetworkRemove struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -307,7 +438,7 @@ type ProviderNetworkRemove struct {
 func (x *ProviderNetworkRemove) Reset() {
 	*x = ProviderNetworkRemove{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[3]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -320,7 +451,7 @@ func (x *ProviderNetworkRemove) String() string {
 func (*ProviderNetworkRemove) ProtoMessage() {}
 
 func (x *ProviderNetworkRemove) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[3]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -333,7 +464,7 @@ func (x *ProviderNetworkRemove) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProviderNetworkRemove.ProtoReflect.Descriptor instead.
 func (*ProviderNetworkRemove) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{3}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ProviderNetworkRemove) GetProviderNwName() string {
@@ -370,7 +501,7 @@ type VlanInfo struct {
 func (x *VlanInfo) Reset() {
 	*x = VlanInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[4]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -383,7 +514,7 @@ func (x *VlanInfo) String() string {
 func (*VlanInfo) ProtoMessage() {}
 
 func (x *VlanInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[4]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -396,7 +527,7 @@ func (x *VlanInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VlanInfo.ProtoReflect.Descriptor instead.
 func (*VlanInfo) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{4}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *VlanInfo) GetVlanId() string {
@@ -431,7 +562,7 @@ type DirectInfo struct {
 func (x *DirectInfo) Reset() {
 	*x = DirectInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[5]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -444,7 +575,7 @@ func (x *DirectInfo) String() string {
 func (*DirectInfo) ProtoMessage() {}
 
 func (x *DirectInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[5]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -457,7 +588,7 @@ func (x *DirectInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DirectInfo.ProtoReflect.Descriptor instead.
 func (*DirectInfo) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{5}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DirectInfo) GetProviderIntf() string {
@@ -479,7 +610,7 @@ type RouteData struct {
 func (x *RouteData) Reset() {
 	*x = RouteData{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[6]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -492,7 +623,7 @@ func (x *RouteData) String() string {
 func (*RouteData) ProtoMessage() {}
 
 func (x *RouteData) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[6]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -505,7 +636,7 @@ func (x *RouteData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RouteData.ProtoReflect.Descriptor instead.
 func (*RouteData) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{6}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *RouteData) GetDst() string {
@@ -534,7 +665,7 @@ type ContainerRouteInsert struct {
 func (x *ContainerRouteInsert) Reset() {
 	*x = ContainerRouteInsert{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[7]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -547,7 +678,7 @@ func (x *ContainerRouteInsert) String() string {
 func (*ContainerRouteInsert) ProtoMessage() {}
 
 func (x *ContainerRouteInsert) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[7]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -560,7 +691,7 @@ func (x *ContainerRouteInsert) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ContainerRouteInsert.ProtoReflect.Descriptor instead.
 func (*ContainerRouteInsert) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{7}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ContainerRouteInsert) GetContainerId() string {
@@ -589,7 +720,7 @@ type ContainerRouteRemove struct {
 func (x *ContainerRouteRemove) Reset() {
 	*x = ContainerRouteRemove{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[8]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -602,7 +733,7 @@ func (x *ContainerRouteRemove) String() string {
 func (*ContainerRouteRemove) ProtoMessage() {}
 
 func (x *ContainerRouteRemove) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[8]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -615,7 +746,7 @@ func (x *ContainerRouteRemove) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ContainerRouteRemove.ProtoReflect.Descriptor instead.
 func (*ContainerRouteRemove) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{8}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ContainerRouteRemove) GetContainerId() string {
@@ -644,7 +775,7 @@ type PodInfo struct {
 func (x *PodInfo) Reset() {
 	*x = PodInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[9]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -657,7 +788,7 @@ func (x *PodInfo) String() string {
 func (*PodInfo) ProtoMessage() {}
 
 func (x *PodInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[9]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -670,7 +801,7 @@ func (x *PodInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PodInfo.ProtoReflect.Descriptor instead.
 func (*PodInfo) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{9}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *PodInfo) GetNamespace() string {
@@ -698,7 +829,7 @@ type NetConf struct {
 func (x *NetConf) Reset() {
 	*x = NetConf{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[10]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -711,7 +842,7 @@ func (x *NetConf) String() string {
 func (*NetConf) ProtoMessage() {}
 
 func (x *NetConf) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[10]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -724,7 +855,7 @@ func (x *NetConf) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NetConf.ProtoReflect.Descriptor instead.
 func (*NetConf) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{10}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *NetConf) GetData() string {
@@ -748,7 +879,7 @@ type PodAddNetwork struct {
 func (x *PodAddNetwork) Reset() {
 	*x = PodAddNetwork{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[11]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -761,7 +892,7 @@ func (x *PodAddNetwork) String() string {
 func (*PodAddNetwork) ProtoMessage() {}
 
 func (x *PodAddNetwork) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[11]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -774,7 +905,7 @@ func (x *PodAddNetwork) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PodAddNetwork.ProtoReflect.Descriptor instead.
 func (*PodAddNetwork) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{11}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *PodAddNetwork) GetContainerId() string {
@@ -819,7 +950,7 @@ type PodDelNetwork struct {
 func (x *PodDelNetwork) Reset() {
 	*x = PodDelNetwork{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[12]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -832,7 +963,7 @@ func (x *PodDelNetwork) String() string {
 func (*PodDelNetwork) ProtoMessage() {}
 
 func (x *PodDelNetwork) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[12]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -845,7 +976,7 @@ func (x *PodDelNetwork) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PodDelNetwork.ProtoReflect.Descriptor instead.
 func (*PodDelNetwork) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{12}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *PodDelNetwork) GetContainerId() string {
@@ -884,12 +1015,14 @@ type InSync struct {
 	NodeIntfIpAddress   string `protobuf:"bytes,1,opt,name=node_intf_ip_address,json=nodeIntfIpAddress,proto3" json:"node_intf_ip_address,omitempty"`
 	NodeIntfMacAddress  string `protobuf:"bytes,2,opt,name=node_intf_mac_address,json=nodeIntfMacAddress,proto3" json:"node_intf_mac_address,omitempty"`
 	NodeIntfIpv6Address string `protobuf:"bytes,3,opt,name=node_intf_ipv6_address,json=nodeIntfIpv6Address,proto3" json:"node_intf_ipv6_address,omitempty"`
+	// Protocol version of the operator
+	ProtocolVersion uint32 `protobuf:"varint,4,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
 }
 
 func (x *InSync) Reset() {
 	*x = InSync{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[13]
+		mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -902,7 +1035,7 @@ func (x *InSync) String() string {
 func (*InSync) ProtoMessage() {}
 
 func (x *InSync) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[13]
+	mi := &file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -915,7 +1048,7 @@ func (x *InSync) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InSync.ProtoReflect.Descriptor instead.
 func (*InSync) Descriptor() ([]byte, []int) {
-	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{13}
+	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *InSync) GetNodeIntfIpAddress() string {
@@ -939,132 +1072,156 @@ func (x *InSync) GetNodeIntfIpv6Address() string {
 	return ""
 }
 
+func (x *InSync) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
 var File_internal_pkg_nfnNotify_proto_nfn_proto protoreflect.FileDescriptor
 
 var file_internal_pkg_nfnNotify_proto_nfn_proto_rawDesc = []byte{
 	0x0a, 0x26, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6e,
 	0x66, 0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6e,
-	0x66, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2f, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x73,
+	0x66, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x7e, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x73,
 	0x63, 0x72, 0x69, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x1b, 0x0a, 0x09,
 	0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xf2, 0x03, 0x0a, 0x0c, 0x4e, 0x6f,
-	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x6e,
-	0x69, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6e,
-	0x69, 0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x07, 0x69, 0x6e, 0x5f, 0x73, 0x79, 0x6e, 0x63,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x49, 0x6e, 0x53, 0x79, 0x6e, 0x63, 0x48,
-	0x00, 0x52, 0x06, 0x69, 0x6e, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x46, 0x0a, 0x12, 0x70, 0x72, 0x6f,
-	0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x6e, 0x77, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72,
-	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52,
-	0x10, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x4e, 0x77, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x12, 0x46, 0x0a, 0x12, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x6e, 0x77,
-	0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
-	0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52,
-	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x48, 0x00, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
-	0x72, 0x4e, 0x77, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x49, 0x0a, 0x14, 0x63, 0x6f, 0x6e,
-	0x74, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x5f, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x73, 0x65, 0x72,
-	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69,
-	0x6e, 0x65, 0x72, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x48, 0x00,
-	0x52, 0x12, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x52, 0x74, 0x49, 0x6e,
-	0x73, 0x65, 0x72, 0x74, 0x12, 0x49, 0x0a, 0x14, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x5f, 0x72, 0x74, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x6f,
-	0x75, 0x74, 0x65, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x48, 0x00, 0x52, 0x12, 0x63, 0x6f, 0x6e,
-	0x74, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x52, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12,
-	0x38, 0x0a, 0x0f, 0x70, 0x6f, 0x64, 0x5f, 0x61, 0x64, 0x64, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f,
-	0x72, 0x6b, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x50, 0x6f, 0x64, 0x41, 0x64,
-	0x64, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x48, 0x00, 0x52, 0x0d, 0x70, 0x6f, 0x64, 0x41,
-	0x64, 0x64, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x38, 0x0a, 0x0f, 0x70, 0x6f, 0x64,
-	0x5f, 0x64, 0x65, 0x6c, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x08, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x50, 0x6f, 0x64, 0x44, 0x65, 0x6c, 0x4e, 0x65, 0x74, 0x77, 0x6f,
-	0x72, 0x6b, 0x48, 0x00, 0x52, 0x0d, 0x70, 0x6f, 0x64, 0x44, 0x65, 0x6c, 0x4e, 0x65, 0x74, 0x77,
-	0x6f, 0x72, 0x6b, 0x42, 0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x85,
-	0x01, 0x0a, 0x15, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x4e, 0x65, 0x74, 0x77, 0x6f,
-	0x72, 0x6b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x76,
-	0x69, 0x64, 0x65, 0x72, 0x5f, 0x6e, 0x77, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0e, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x4e, 0x77, 0x4e, 0x61,
-	0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x04, 0x76, 0x6c, 0x61, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x09, 0x2e, 0x56, 0x6c, 0x61, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x76, 0x6c, 0x61,
-	0x6e, 0x12, 0x23, 0x0a, 0x06, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0b, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06,
-	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x22, 0x9f, 0x01, 0x0a, 0x15, 0x50, 0x72, 0x6f, 0x76, 0x69,
+	0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x61, 0x70, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x3b, 0x0a, 0x0a, 0x41, 0x63, 0x6b, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x03, 0x73, 0x65, 0x71, 0x22, 0x0d, 0x0a, 0x0b, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x84, 0x04, 0x0a, 0x0c, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x6e, 0x69, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6e, 0x69, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x22, 0x0a, 0x07, 0x69, 0x6e, 0x5f, 0x73, 0x79, 0x6e, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x07, 0x2e, 0x49, 0x6e, 0x53, 0x79, 0x6e, 0x63, 0x48, 0x00, 0x52, 0x06, 0x69, 0x6e,
+	0x53, 0x79, 0x6e, 0x63, 0x12, 0x46, 0x0a, 0x12, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72,
+	0x5f, 0x6e, 0x77, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x4e, 0x77, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x46, 0x0a, 0x12,
+	0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x6e, 0x77, 0x5f, 0x72, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x69,
 	0x64, 0x65, 0x72, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
-	0x12, 0x28, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x6e, 0x77, 0x5f,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x72, 0x6f, 0x76,
-	0x69, 0x64, 0x65, 0x72, 0x4e, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x76, 0x6c,
-	0x61, 0x6e, 0x5f, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x69, 0x6e, 0x74, 0x66, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x76, 0x6c, 0x61, 0x6e, 0x4c, 0x6f, 0x67, 0x69, 0x63,
-	0x61, 0x6c, 0x49, 0x6e, 0x74, 0x66, 0x12, 0x30, 0x0a, 0x14, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
-	0x5f, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x74, 0x66, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x50, 0x72, 0x6f, 0x76,
-	0x69, 0x64, 0x65, 0x72, 0x49, 0x6e, 0x74, 0x66, 0x22, 0x6b, 0x0a, 0x08, 0x56, 0x6c, 0x61, 0x6e,
-	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x17, 0x0a, 0x07, 0x76, 0x6c, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x6c, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x23, 0x0a,
-	0x0d, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x74, 0x66, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x49, 0x6e,
-	0x74, 0x66, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x69, 0x6e,
-	0x74, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61,
-	0x6c, 0x49, 0x6e, 0x74, 0x66, 0x22, 0x31, 0x0a, 0x0a, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x49,
-	0x6e, 0x66, 0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f,
-	0x69, 0x6e, 0x74, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x76,
-	0x69, 0x64, 0x65, 0x72, 0x49, 0x6e, 0x74, 0x66, 0x22, 0x2d, 0x0a, 0x09, 0x52, 0x6f, 0x75, 0x74,
-	0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x03, 0x64, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x77, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x02, 0x67, 0x77, 0x22, 0x5b, 0x0a, 0x14, 0x43, 0x6f, 0x6e, 0x74, 0x61,
-	0x69, 0x6e, 0x65, 0x72, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x12,
-	0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
-	0x49, 0x64, 0x12, 0x20, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x0a, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x05, 0x72,
-	0x6f, 0x75, 0x74, 0x65, 0x22, 0x5b, 0x0a, 0x14, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65,
-	0x72, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x48, 0x00, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x4e, 0x77, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x12, 0x49, 0x0a, 0x14, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x5f, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x6f,
+	0x75, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x48, 0x00, 0x52, 0x12, 0x63, 0x6f, 0x6e,
+	0x74, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x52, 0x74, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x12,
+	0x49, 0x0a, 0x14, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x5f, 0x72, 0x74,
+	0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x48, 0x00, 0x52, 0x12, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x52, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x38, 0x0a, 0x0f, 0x70, 0x6f,
+	0x64, 0x5f, 0x61, 0x64, 0x64, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x50, 0x6f, 0x64, 0x41, 0x64, 0x64, 0x4e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x48, 0x00, 0x52, 0x0d, 0x70, 0x6f, 0x64, 0x41, 0x64, 0x64, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x12, 0x38, 0x0a, 0x0f, 0x70, 0x6f, 0x64, 0x5f, 0x64, 0x65, 0x6c, 0x5f,
+	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x50, 0x6f, 0x64, 0x44, 0x65, 0x6c, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x48, 0x00, 0x52,
+	0x0d, 0x70, 0x6f, 0x64, 0x44, 0x65, 0x6c, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x10,
+	0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71,
+	0x42, 0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0xa6, 0x01, 0x0a, 0x15,
+	0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
+	0x72, 0x5f, 0x6e, 0x77, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x4e, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x1d, 0x0a, 0x04, 0x76, 0x6c, 0x61, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e,
+	0x56, 0x6c, 0x61, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x76, 0x6c, 0x61, 0x6e, 0x12, 0x23,
+	0x0a, 0x06, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b,
+	0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x74, 0x74, 0x61, 0x63, 0x68, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x61, 0x63, 0x68,
+	0x54, 0x79, 0x70, 0x65, 0x22, 0x9f, 0x01, 0x0a, 0x15, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
+	0x72, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x28,
+	0x0a, 0x10, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x6e, 0x77, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x72, 0x4e, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x76, 0x6c, 0x61, 0x6e,
+	0x5f, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x69, 0x6e, 0x74, 0x66, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x76, 0x6c, 0x61, 0x6e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
+	0x49, 0x6e, 0x74, 0x66, 0x12, 0x30, 0x0a, 0x14, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x74, 0x66, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x12, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x72, 0x49, 0x6e, 0x74, 0x66, 0x22, 0x6b, 0x0a, 0x08, 0x56, 0x6c, 0x61, 0x6e, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x17, 0x0a, 0x07, 0x76, 0x6c, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x6c, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x74, 0x66, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x49, 0x6e, 0x74, 0x66,
+	0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x69, 0x6e, 0x74, 0x66,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x49,
+	0x6e, 0x74, 0x66, 0x22, 0x31, 0x0a, 0x0a, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x6e,
+	0x74, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x72, 0x49, 0x6e, 0x74, 0x66, 0x22, 0x2d, 0x0a, 0x09, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x64, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x67, 0x77, 0x22, 0x5b, 0x0a, 0x14, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e,
+	0x65, 0x72, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x12, 0x21, 0x0a,
+	0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x64,
+	0x12, 0x20, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0a, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x05, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x22, 0x5b, 0x0a, 0x14, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f,
+	0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x12, 0x20, 0x0a,
+	0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x22,
+	0x3b, 0x0a, 0x07, 0x50, 0x6f, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61,
+	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x1d, 0x0a, 0x07,
+	0x4e, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x8c, 0x01, 0x0a, 0x0d,
+	0x50, 0x6f, 0x64, 0x41, 0x64, 0x64, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x21, 0x0a,
+	0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x64,
+	0x12, 0x1a, 0x0a, 0x03, 0x70, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e,
+	0x50, 0x6f, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x03, 0x70, 0x6f, 0x64, 0x12, 0x1a, 0x0a, 0x03,
+	0x6e, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x4e, 0x65, 0x74, 0x43,
+	0x6f, 0x6e, 0x66, 0x52, 0x03, 0x6e, 0x65, 0x74, 0x12, 0x20, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x22, 0x8c, 0x01, 0x0a, 0x0d, 0x50,
+	0x6f, 0x64, 0x44, 0x65, 0x6c, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x21, 0x0a, 0x0c,
 	0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x12,
-	0x20, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a,
-	0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x22, 0x3b, 0x0a, 0x07, 0x50, 0x6f, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09,
-	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x1d,
-	0x0a, 0x07, 0x4e, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
-	0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x8c, 0x01,
-	0x0a, 0x0d, 0x50, 0x6f, 0x64, 0x41, 0x64, 0x64, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12,
-	0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
-	0x49, 0x64, 0x12, 0x1a, 0x0a, 0x03, 0x70, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x08, 0x2e, 0x50, 0x6f, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x03, 0x70, 0x6f, 0x64, 0x12, 0x1a,
-	0x0a, 0x03, 0x6e, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x4e, 0x65,
-	0x74, 0x43, 0x6f, 0x6e, 0x66, 0x52, 0x03, 0x6e, 0x65, 0x74, 0x12, 0x20, 0x0a, 0x05, 0x72, 0x6f,
-	0x75, 0x74, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x52, 0x6f, 0x75, 0x74,
-	0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x22, 0x8c, 0x01, 0x0a,
-	0x0d, 0x50, 0x6f, 0x64, 0x44, 0x65, 0x6c, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x21,
-	0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49,
-	0x64, 0x12, 0x1a, 0x0a, 0x03, 0x70, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08,
-	0x2e, 0x50, 0x6f, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x03, 0x70, 0x6f, 0x64, 0x12, 0x1a, 0x0a,
-	0x03, 0x6e, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x4e, 0x65, 0x74,
-	0x43, 0x6f, 0x6e, 0x66, 0x52, 0x03, 0x6e, 0x65, 0x74, 0x12, 0x20, 0x0a, 0x05, 0x72, 0x6f, 0x75,
-	0x74, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65,
-	0x44, 0x61, 0x74, 0x61, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x22, 0xa1, 0x01, 0x0a, 0x06,
-	0x49, 0x6e, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x2f, 0x0a, 0x14, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69,
-	0x6e, 0x74, 0x66, 0x5f, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x74, 0x66, 0x49, 0x70,
-	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x31, 0x0a, 0x15, 0x6e, 0x6f, 0x64, 0x65, 0x5f,
-	0x69, 0x6e, 0x74, 0x66, 0x5f, 0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x74, 0x66,
-	0x4d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x33, 0x0a, 0x16, 0x6e, 0x6f,
-	0x64, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x66, 0x5f, 0x69, 0x70, 0x76, 0x36, 0x5f, 0x61, 0x64, 0x64,
-	0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x6e, 0x6f, 0x64, 0x65,
-	0x49, 0x6e, 0x74, 0x66, 0x49, 0x70, 0x76, 0x36, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x32,
-	0x3c, 0x0a, 0x09, 0x6e, 0x66, 0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x2f, 0x0a, 0x09,
-	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x11, 0x2e, 0x53, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x62, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x1a, 0x0d, 0x2e, 0x4e,
-	0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x30, 0x01, 0x42, 0x3a, 0x5a,
-	0x38, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x6b, 0x72, 0x61,
-	0x69, 0x6e, 0x6f, 0x2d, 0x65, 0x64, 0x67, 0x65, 0x2d, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x2f, 0x69,
-	0x63, 0x6e, 0x2d, 0x6e, 0x6f, 0x64, 0x75, 0x73, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
-	0x6c, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6e, 0x66, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x1a, 0x0a, 0x03, 0x70, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x50,
+	0x6f, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x03, 0x70, 0x6f, 0x64, 0x12, 0x1a, 0x0a, 0x03, 0x6e,
+	0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x4e, 0x65, 0x74, 0x43, 0x6f,
+	0x6e, 0x66, 0x52, 0x03, 0x6e, 0x65, 0x74, 0x12, 0x20, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x22, 0xcc, 0x01, 0x0a, 0x06, 0x49, 0x6e,
+	0x53, 0x79, 0x6e, 0x63, 0x12, 0x2f, 0x0a, 0x14, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x6e, 0x74,
+	0x66, 0x5f, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x11, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x74, 0x66, 0x49, 0x70, 0x41, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x31, 0x0a, 0x15, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x6e,
+	0x74, 0x66, 0x5f, 0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x74, 0x66, 0x4d, 0x61,
+	0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x33, 0x0a, 0x16, 0x6e, 0x6f, 0x64, 0x65,
+	0x5f, 0x69, 0x6e, 0x74, 0x66, 0x5f, 0x69, 0x70, 0x76, 0x36, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x6e,
+	0x74, 0x66, 0x49, 0x70, 0x76, 0x36, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x29, 0x0a,
+	0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x32, 0x5e, 0x0a, 0x09, 0x6e, 0x66, 0x6e, 0x4e,
+	0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x2f, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x12, 0x11, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x43, 0x6f,
+	0x6e, 0x74, 0x65, 0x78, 0x74, 0x1a, 0x0d, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x30, 0x01, 0x12, 0x20, 0x0a, 0x03, 0x41, 0x63, 0x6b, 0x12, 0x0b, 0x2e,
+	0x41, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x3a, 0x5a, 0x38, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x6b, 0x72, 0x61, 0x69, 0x6e, 0x6f, 0x2d, 0x65,
+	0x64, 0x67, 0x65, 0x2d, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x2f, 0x69, 0x63, 0x6e, 0x2d, 0x6e, 0x6f,
+	0x64, 0x75, 0x73, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x6b, 0x67,
+	0x2f, 0x6e, 0x66, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1079,45 +1236,49 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescGZIP() []byte {
 	return file_internal_pkg_nfnNotify_proto_nfn_proto_rawDescData
 }
 
-var file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_internal_pkg_nfnNotify_proto_nfn_proto_goTypes = []interface{}{
 	(*SubscribeContext)(nil),      // 0: SubscribeContext
-	(*Notification)(nil),          // 1: Notification
-	(*ProviderNetworkCreate)(nil), // 2: ProviderNetworkCreate
-	(*ProviderNetworkRemove)(nil), // 3: ProviderNetworkRemove
-	(*VlanInfo)(nil),              // 4: VlanInfo
-	(*DirectInfo)(nil),            // 5: DirectInfo
-	(*RouteData)(nil),             // 6: RouteData
-	(*ContainerRouteInsert)(nil),  // 7: ContainerRouteInsert
-	(*ContainerRouteRemove)(nil),  // 8: ContainerRouteRemove
-	(*PodInfo)(nil),               // 9: PodInfo
-	(*NetConf)(nil),               // 10: NetConf
-	(*PodAddNetwork)(nil),         // 11: PodAddNetwork
-	(*PodDelNetwork)(nil),         // 12: PodDelNetwork
-	(*InSync)(nil),                // 13: InSync
+	(*AckContext)(nil),            // 1: AckContext
+	(*AckResponse)(nil),           // 2: AckResponse
+	(*Notification)(nil),          // 3: Notification
+	(*ProviderNetworkCreate)(nil), // 4: ProviderNetworkCreate
+	(*ProviderNetworkRemove)(nil), // 5: ProviderNetworkRemove
+	(*VlanInfo)(nil),              // 6: VlanInfo
+	(*DirectInfo)(nil),            // 7: DirectInfo
+	(*RouteData)(nil),             // 8: RouteData
+	(*ContainerRouteInsert)(nil),  // 9: ContainerRouteInsert
+	(*ContainerRouteRemove)(nil),  // 10: ContainerRouteRemove
+	(*PodInfo)(nil),               // 11: PodInfo
+	(*NetConf)(nil),               // 12: NetConf
+	(*PodAddNetwork)(nil),         // 13: PodAddNetwork
+	(*PodDelNetwork)(nil),         // 14: PodDelNetwork
+	(*InSync)(nil),                // 15: InSync
 }
 var file_internal_pkg_nfnNotify_proto_nfn_proto_depIdxs = []int32{
-	13, // 0: Notification.in_sync:type_name -> InSync
-	2,  // 1: Notification.provider_nw_create:type_name -> ProviderNetworkCreate
-	3,  // 2: Notification.provider_nw_remove:type_name -> ProviderNetworkRemove
-	7,  // 3: Notification.containter_rt_insert:type_name -> ContainerRouteInsert
-	8,  // 4: Notification.containter_rt_remove:type_name -> ContainerRouteRemove
-	11, // 5: Notification.pod_add_network:type_name -> PodAddNetwork
-	12, // 6: Notification.pod_del_network:type_name -> PodDelNetwork
-	4,  // 7: ProviderNetworkCreate.vlan:type_name -> VlanInfo
-	5,  // 8: ProviderNetworkCreate.direct:type_name -> DirectInfo
-	6,  // 9: ContainerRouteInsert.route:type_name -> RouteData
-	6,  // 10: ContainerRouteRemove.route:type_name -> RouteData
-	9,  // 11: PodAddNetwork.pod:type_name -> PodInfo
-	10, // 12: PodAddNetwork.net:type_name -> NetConf
-	6,  // 13: PodAddNetwork.route:type_name -> RouteData
-	9,  // 14: PodDelNetwork.pod:type_name -> PodInfo
-	10, // 15: PodDelNetwork.net:type_name -> NetConf
-	6,  // 16: PodDelNetwork.route:type_name -> RouteData
+	15, // 0: Notification.in_sync:type_name -> InSync
+	4,  // 1: Notification.provider_nw_create:type_name -> ProviderNetworkCreate
+	5,  // 2: Notification.provider_nw_remove:type_name -> ProviderNetworkRemove
+	9,  // 3: Notification.containter_rt_insert:type_name -> ContainerRouteInsert
+	10, // 4: Notification.containter_rt_remove:type_name -> ContainerRouteRemove
+	13, // 5: Notification.pod_add_network:type_name -> PodAddNetwork
+	14, // 6: Notification.pod_del_network:type_name -> PodDelNetwork
+	6,  // 7: ProviderNetworkCreate.vlan:type_name -> VlanInfo
+	7,  // 8: ProviderNetworkCreate.direct:type_name -> DirectInfo
+	8,  // 9: ContainerRouteInsert.route:type_name -> RouteData
+	8,  // 10: ContainerRouteRemove.route:type_name -> RouteData
+	11, // 11: PodAddNetwork.pod:type_name -> PodInfo
+	12, // 12: PodAddNetwork.net:type_name -> NetConf
+	8,  // 13: PodAddNetwork.route:type_name -> RouteData
+	11, // 14: PodDelNetwork.pod:type_name -> PodInfo
+	12, // 15: PodDelNetwork.net:type_name -> NetConf
+	8,  // 16: PodDelNetwork.route:type_name -> RouteData
 	0,  // 17: nfnNotify.Subscribe:input_type -> SubscribeContext
-	1,  // 18: nfnNotify.Subscribe:output_type -> Notification
-	18, // [18:19] is the sub-list for method output_type
-	17, // [17:18] is the sub-list for method input_type
+	1,  // 18: nfnNotify.Ack:input_type -> AckContext
+	3,  // 19: nfnNotify.Subscribe:output_type -> Notification
+	2,  // 20: nfnNotify.Ack:output_type -> AckResponse
+	19, // [19:21] is the sub-list for method output_type
+	17, // [17:19] is the sub-list for method input_type
 	17, // [17:17] is the sub-list for extension type_name
 	17, // [17:17] is the sub-list for extension extendee
 	0,  // [0:17] is the sub-list for field type_name
@@ -1142,7 +1303,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Notification); i {
+			switch v := v.(*AckContext); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1154,7 +1315,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProviderNetworkCreate); i {
+			switch v := v.(*AckResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1166,7 +1327,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProviderNetworkRemove); i {
+			switch v := v.(*Notification); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1178,7 +1339,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VlanInfo); i {
+			switch v := v.(*ProviderNetworkCreate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1190,7 +1351,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DirectInfo); i {
+			switch v := v.(*ProviderNetworkRemove); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1202,7 +1363,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RouteData); i {
+			switch v := v.(*VlanInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1214,7 +1375,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ContainerRouteInsert); i {
+			switch v := v.(*DirectInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1226,7 +1387,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ContainerRouteRemove); i {
+			switch v := v.(*RouteData); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1238,7 +1399,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PodInfo); i {
+			switch v := v.(*ContainerRouteInsert); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1250,7 +1411,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NetConf); i {
+			switch v := v.(*ContainerRouteRemove); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1262,7 +1423,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PodAddNetwork); i {
+			switch v := v.(*PodInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1274,7 +1435,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PodDelNetwork); i {
+			switch v := v.(*NetConf); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1286,6 +1447,30 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PodAddNetwork); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PodDelNetwork); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*InSync); i {
 			case 0:
 				return &v.state
@@ -1298,7 +1483,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			}
 		}
 	}
-	file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[1].OneofWrappers = []interface{}{
+	file_internal_pkg_nfnNotify_proto_nfn_proto_msgTypes[3].OneofWrappers = []interface{}{
 		(*Notification_InSync)(nil),
 		(*Notification_ProviderNwCreate)(nil),
 		(*Notification_ProviderNwRemove)(nil),
@@ -1313,7 +1498,7 @@ func file_internal_pkg_nfnNotify_proto_nfn_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_internal_pkg_nfnNotify_proto_nfn_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
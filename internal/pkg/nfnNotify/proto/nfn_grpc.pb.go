@@ -23,6 +23,7 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type NfnNotifyClient interface {
 	Subscribe(ctx context.Context, in *SubscribeContext, opts ...grpc.CallOption) (NfnNotify_SubscribeClient, error)
+	Ack(ctx context.Context, in *AckContext, opts ...grpc.CallOption) (*AckResponse, error)
 }
 
 type nfnNotifyClient struct {
@@ -48,6 +49,15 @@ func (c *nfnNotifyClient) Subscribe(ctx context.Context, in *SubscribeContext, o
 	return x, nil
 }
 
+func (c *nfnNotifyClient) Ack(ctx context.Context, in *AckContext, opts ...grpc.CallOption) (*AckResponse, error) {
+	out := new(AckResponse)
+	err := c.cc.Invoke(ctx, "/nfnNotify/Ack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type NfnNotify_SubscribeClient interface {
 	Recv() (*Notification, error)
 	grpc.ClientStream
@@ -70,6 +80,7 @@ func (x *nfnNotifySubscribeClient) Recv() (*Notification, error) {
 // for forward compatibility
 type NfnNotifyServer interface {
 	Subscribe(*SubscribeContext, NfnNotify_SubscribeServer) error
+	Ack(context.Context, *AckContext) (*AckResponse, error)
 	mustEmbedUnimplementedNfnNotifyServer()
 }
 
@@ -80,6 +91,9 @@ type UnimplementedNfnNotifyServer struct {
 func (UnimplementedNfnNotifyServer) Subscribe(*SubscribeContext, NfnNotify_SubscribeServer) error {
 	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
 }
+func (UnimplementedNfnNotifyServer) Ack(context.Context, *AckContext) (*AckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ack not implemented")
+}
 func (UnimplementedNfnNotifyServer) mustEmbedUnimplementedNfnNotifyServer() {}
 
 // UnsafeNfnNotifyServer may be embedded to opt out of forward compatibility for this service.
@@ -114,13 +128,36 @@ func (x *nfnNotifySubscribeServer) Send(m *Notification) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _NfnNotify_Ack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckContext)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NfnNotifyServer).Ack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nfnNotify/Ack",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NfnNotifyServer).Ack(ctx, req.(*AckContext))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NfnNotify_ServiceDesc is the grpc.ServiceDesc for NfnNotify service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var NfnNotify_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "nfnNotify",
 	HandlerType: (*NfnNotifyServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ack",
+			Handler:    _NfnNotify_Ack_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "Subscribe",
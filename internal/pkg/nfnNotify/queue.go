@@ -0,0 +1,256 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nfn
+
+import (
+	"sync"
+	"time"
+
+	pb "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify/proto"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// maxPendingNotifications bounds the notifications queued for a node
+const maxPendingNotifications = 1024
+
+// client keeps the notifications of a node until the agent acknowledges them
+type client struct {
+	sync.Mutex
+	node    string
+	context *pb.SubscribeContext
+	stream  pb.NfnNotify_SubscribeServer
+	// seq is the sequence number of the last queued notification
+	seq uint64
+	// pending holds the unacknowledged notifications, the first `sent` ones were
+	// sent on the current stream
+	pending []*pb.Notification
+	sent    int
+	wake    chan struct{}
+	// version and capabilities are negotiated by the subscription of the agent,
+	// missing records the capabilities required by the notifications not sent
+	version      uint32
+	capabilities map[string]bool
+	missing      map[string]bool
+}
+
+func newClient(node string) *client {
+	return &client{
+		node: node,
+		// Sequence numbers keep increasing across operator restarts
+		seq:  uint64(time.Now().UnixNano()),
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// getClient returns the client of the node, creating it if required
+func (s *serverDB) getClient(nodeName string) *client {
+	s.Lock()
+	defer s.Unlock()
+	c, ok := s.clientList[nodeName]
+	if !ok {
+		c = newClient(nodeName)
+		s.clientList[nodeName] = c
+	}
+	return c
+}
+
+// connectedNodes returns the nodes with an agent subscribed
+func (s *serverDB) connectedNodes() []string {
+	s.RLock()
+	defer s.RUnlock()
+	var nodes []string
+	for name, c := range s.clientList {
+		if c.isConnected() {
+			nodes = append(nodes, name)
+		}
+	}
+	return nodes
+}
+
+func (c *client) isConnected() bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.stream != nil
+}
+
+func (c *client) notify() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// enqueue assigns the next sequence number to a copy of the notification and queues it
+func (c *client) enqueue(msg *pb.Notification) {
+	m := proto.Clone(msg).(*pb.Notification)
+
+	c.Lock()
+	c.seq++
+	m.Seq = c.seq
+	if len(c.pending) >= maxPendingNotifications {
+		log.Info("Notification queue full, dropping the oldest notification", "Node", c.node, "seq", c.pending[0].GetSeq())
+		c.pending = c.pending[1:]
+		if c.sent > 0 {
+			c.sent--
+		}
+	}
+	c.pending = append(c.pending, m)
+	c.Unlock()
+
+	c.notify()
+}
+
+// ack drops the notifications up to seq
+func (c *client) ack(seq uint64) {
+	c.Lock()
+	defer c.Unlock()
+	n := 0
+	for n < len(c.pending) && c.pending[n].GetSeq() <= seq {
+		n++
+	}
+	c.pending = c.pending[n:]
+	c.sent -= n
+	if c.sent < 0 {
+		c.sent = 0
+	}
+}
+
+// attach makes ss the stream of the node, the unacknowledged notifications are sent again
+func (c *client) attach(sc *pb.SubscribeContext, ss pb.NfnNotify_SubscribeServer) {
+	c.Lock()
+	c.context = sc
+	c.stream = ss
+	c.sent = 0
+	c.version = sc.GetProtocolVersion()
+	c.capabilities = map[string]bool{}
+	for _, capability := range sc.GetCapabilities() {
+		c.capabilities[capability] = true
+	}
+	c.missing = map[string]bool{}
+	// A new InSync message ends the replay of the new subscription
+	pending := c.pending[:0]
+	for _, m := range c.pending {
+		if _, ok := m.Payload.(*pb.Notification_InSync); !ok {
+			pending = append(pending, m)
+		}
+	}
+	c.pending = pending
+	c.Unlock()
+
+	c.notify()
+}
+
+// detach removes ss if it is still the stream of the node
+func (c *client) detach(ss pb.NfnNotify_SubscribeServer) {
+	c.Lock()
+	defer c.Unlock()
+	if c.stream == ss {
+		c.context = nil
+		c.stream = nil
+		c.sent = 0
+	}
+}
+
+// supports returns true if the subscribed agent of the node has the capability, the
+// capabilities of a node not subscribed yet are unknown and supported
+func (c *client) supports(capability string) bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.stream == nil || c.capabilities[capability]
+}
+
+// requireCapability records a capability missing on the agent of the node, it returns
+// true if it was not recorded yet
+func (c *client) requireCapability(capability string) bool {
+	c.Lock()
+	defer c.Unlock()
+	if c.stream == nil || c.capabilities[capability] || c.missing[capability] {
+		return false
+	}
+	c.missing[capability] = true
+	return true
+}
+
+// next returns the next notification to send on ss, superseded is true if the node
+// subscribed again with another stream. The notifications the agent can't handle are
+// dropped, missing is true if one of them requires a capability not recorded yet.
+func (c *client) next(ss pb.NfnNotify_SubscribeServer) (msg *pb.Notification, superseded, missing bool) {
+	c.Lock()
+	defer c.Unlock()
+	if c.stream != ss {
+		return nil, true, false
+	}
+	for c.sent < len(c.pending) {
+		m := c.pending[c.sent]
+		capability := pb.RequiredCapability(m)
+		if capability == "" || c.capabilities[capability] {
+			return m, false, missing
+		}
+		log.Info("Agent can't handle the notification, dropping it", "Node", c.node, "seq", m.GetSeq(), "capability", capability)
+		c.pending = append(c.pending[:c.sent], c.pending[c.sent+1:]...)
+		if !c.missing[capability] {
+			c.missing[capability] = true
+			missing = true
+		}
+	}
+	return nil, false, missing
+}
+
+func (c *client) markSent(ss pb.NfnNotify_SubscribeServer, msg *pb.Notification) {
+	c.Lock()
+	defer c.Unlock()
+	// The notification may have been acknowledged or dropped meanwhile
+	if c.stream != ss || c.sent >= len(c.pending) || c.pending[c.sent] != msg {
+		return
+	}
+	if !c.capabilities[pb.CapabilityAck] {
+		// Agents not acknowledging the notifications get them only once
+		c.pending = append(c.pending[:c.sent], c.pending[c.sent+1:]...)
+		return
+	}
+	c.sent++
+}
+
+// run sends the queued notifications on ss until the agent disconnects
+func (c *client) run(ss pb.NfnNotify_SubscribeServer) error {
+	for {
+		msg, superseded, missing := c.next(ss)
+		if missing {
+			updateAgentCondition(c)
+		}
+		if superseded {
+			// pass on a wake up that may be meant for the new stream
+			c.notify()
+			return nil
+		}
+		if msg == nil {
+			select {
+			case <-c.wake:
+			case <-ss.Context().Done():
+				return ss.Context().Err()
+			case <-stopChan:
+				return nil
+			}
+			continue
+		}
+		if err := ss.Send(msg); err != nil {
+			return err
+		}
+		c.markSent(ss, msg)
+	}
+}
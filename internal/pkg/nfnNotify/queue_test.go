@@ -0,0 +1,131 @@
+package nfn
+
+import (
+	"testing"
+
+	pb "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify/proto"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func TestNfnNotify(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Nfn Notify Test Suite")
+}
+
+// fakeStream is a subscription stream, the notifications are not sent on it
+type fakeStream struct {
+	pb.NfnNotify_SubscribeServer
+}
+
+// routeInsert returns a notification every agent handles
+func routeInsert() *pb.Notification {
+	return &pb.Notification{Payload: &pb.Notification_ContainterRtInsert{ContainterRtInsert: &pb.ContainerRouteInsert{}}}
+}
+
+// inSync returns the notification ending the replay of a subscription
+func inSync() *pb.Notification {
+	return &pb.Notification{Payload: &pb.Notification_InSync{InSync: &pb.InSync{}}}
+}
+
+// sendAll marks the queued notifications sent on the stream, as run does
+func sendAll(c *client, ss pb.NfnNotify_SubscribeServer) {
+	for {
+		msg, _, _ := c.next(ss)
+		if msg == nil {
+			return
+		}
+		c.markSent(ss, msg)
+	}
+}
+
+var _ = Describe("Test notification queue", func() {
+	var c *client
+	var ss *fakeStream
+
+	BeforeEach(func() {
+		c = newClient("node")
+		ss = &fakeStream{}
+	})
+
+	It("assigns increasing sequence numbers to copies of the notifications", func() {
+		msg := routeInsert()
+		c.enqueue(msg)
+		c.enqueue(msg)
+		Expect(c.pending).To(HaveLen(2))
+		Expect(c.pending[0]).NotTo(BeIdenticalTo(msg))
+		Expect(msg.GetSeq()).To(BeZero())
+		Expect(c.pending[1].GetSeq()).To(Equal(c.pending[0].GetSeq() + 1))
+		Expect(c.pending[1].GetSeq()).To(Equal(c.seq))
+	})
+
+	It("drops the oldest notification of a full queue", func() {
+		for i := 0; i < maxPendingNotifications+1; i++ {
+			c.enqueue(routeInsert())
+		}
+		Expect(c.pending).To(HaveLen(maxPendingNotifications))
+		Expect(c.pending[len(c.pending)-1].GetSeq()).To(Equal(c.seq))
+		Expect(c.pending[0].GetSeq()).To(Equal(c.seq - maxPendingNotifications + 1))
+	})
+
+	table.DescribeTable("drops the acknowledged notifications",
+		func(sent int, acked int, pending, stillSent int) {
+			c.attach(&pb.SubscribeContext{Capabilities: []string{pb.CapabilityAck}}, ss)
+			for i := 0; i < 3; i++ {
+				c.enqueue(routeInsert())
+			}
+			first := c.pending[0].GetSeq()
+			for i := 0; i < sent; i++ {
+				msg, _, _ := c.next(ss)
+				c.markSent(ss, msg)
+			}
+			Expect(c.sent).To(Equal(sent))
+
+			c.ack(first + uint64(acked) - 1)
+			Expect(c.pending).To(HaveLen(pending))
+			Expect(c.sent).To(Equal(stillSent))
+			if pending != 0 {
+				Expect(c.pending[0].GetSeq()).To(Equal(first + uint64(3-pending)))
+			}
+		},
+		table.Entry("nothing acknowledged", 2, 0, 3, 2),
+		table.Entry("part of the sent notifications acknowledged", 2, 1, 2, 1),
+		table.Entry("all the sent notifications acknowledged", 2, 2, 1, 0),
+		table.Entry("notifications acknowledged beyond the sent ones", 2, 3, 0, 0),
+	)
+
+	table.DescribeTable("keeps the sent notifications until they are acknowledged",
+		func(capabilities []string, pending int) {
+			c.attach(&pb.SubscribeContext{Capabilities: capabilities}, ss)
+			c.enqueue(routeInsert())
+			c.enqueue(routeInsert())
+			sendAll(c, ss)
+			Expect(c.pending).To(HaveLen(pending))
+			Expect(c.sent).To(Equal(pending))
+		},
+		table.Entry("agent acknowledging the notifications", []string{pb.CapabilityAck}, 2),
+		table.Entry("agent not acknowledging the notifications", nil, 0),
+	)
+
+	It("sends the unacknowledged notifications again on a new stream", func() {
+		c.attach(&pb.SubscribeContext{Capabilities: []string{pb.CapabilityAck}}, ss)
+		c.enqueue(routeInsert())
+		c.enqueue(inSync())
+		sendAll(c, ss)
+		Expect(c.sent).To(Equal(2))
+
+		next := &fakeStream{}
+		c.attach(&pb.SubscribeContext{Capabilities: []string{pb.CapabilityAck}}, next)
+		Expect(c.sent).To(BeZero())
+		_, superseded, _ := c.next(ss)
+		Expect(superseded).To(BeTrue())
+
+		// the InSync message of the previous replay is dropped
+		Expect(c.pending).To(HaveLen(1))
+		msg, superseded, _ := c.next(next)
+		Expect(superseded).To(BeFalse())
+		Expect(msg.GetPayload()).To(BeAssignableToTypeOf(&pb.Notification_ContainterRtInsert{}))
+	})
+})
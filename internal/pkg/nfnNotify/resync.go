@@ -0,0 +1,182 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nfn
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
+	v1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	pb "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify/proto"
+
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// podRoutesAnnotation records the routes pushed to the pod by the network chaining, so that
+// they can be replayed when the agent of the node resyncs
+const podRoutesAnnotation = "k8s.plugin.opnfv.org/nfnRoutes"
+
+func getPodRoutes(pod *kapi.Pod) []v1alpha1.Route {
+	var routes []v1alpha1.Route
+	value, ok := pod.Annotations[podRoutesAnnotation]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(value), &routes); err != nil {
+		log.Error(err, "Invalid routes annotation", "pod", pod.Name, "annotation", value)
+		return nil
+	}
+	return routes
+}
+
+// recordPodRoutes adds or removes the routes from the routes annotation of the pod. An added route
+// replaces the route to the same destination, like the ECMP route over new replicas
+func recordPodRoutes(namespace, name string, routes []*pb.RouteData, remove bool) {
+	if len(routes) == 0 || kubeClientset == nil {
+		return
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pod, err := kubeClientset.CoreV1().Pods(namespace).Get(context.TODO(), name, v1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		updated := []v1alpha1.Route{}
+		changed := map[v1alpha1.Route]bool{}
+		replaced := map[string]bool{}
+		for _, r := range routes {
+			changed[v1alpha1.Route{Dst: r.GetDst(), GW: r.GetGw()}] = true
+			if !remove {
+				replaced[r.GetDst()] = true
+			}
+		}
+		for _, r := range getPodRoutes(pod) {
+			if !changed[r] && !replaced[r.Dst] {
+				updated = append(updated, r)
+			}
+		}
+		if !remove {
+			for _, r := range routes {
+				updated = append(updated, v1alpha1.Route{Dst: r.GetDst(), GW: r.GetGw()})
+			}
+		}
+
+		value, err := json.Marshal(updated)
+		if err != nil {
+			return err
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[podRoutesAnnotation] = string(value)
+		_, err = kubeClientset.CoreV1().Pods(namespace).Update(context.TODO(), pod, v1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		log.Error(err, "Failed to record the pod routes", "namespace", namespace, "pod", name)
+	}
+}
+
+// podContainerID returns the ID of the first container of the pod without the runtime prefix
+func podContainerID(pod *kapi.Pod) string {
+	id := pod.Status.ContainerStatuses[0].ContainerID
+	if i := strings.Index(id, "://"); i >= 0 {
+		return id[i+3:]
+	}
+	return id
+}
+
+// replayPodState queues the pod interfaces and the routes of the running pods of the node,
+// the agent skips the interfaces and the routes already present in the pods
+func replayPodState(nodeName string, c *client) error {
+	pods, err := kubeClientset.CoreV1().Pods("").List(context.TODO(), v1.ListOptions{FieldSelector: "spec.nodeName=" + nodeName})
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Spec.HostNetwork || pod.Status.Phase != kapi.PodRunning || len(pod.Status.ContainerStatuses) == 0 {
+			continue
+		}
+		annotation, ok := pod.Annotations[ovn.Ovn4nfvAnnotationTag]
+		if !ok {
+			continue
+		}
+		podInterfaces, err := ovn.DecodePodInterfaces(annotation)
+		if err != nil {
+			log.Error(err, "Invalid ovnInterfaces annotation", "pod", pod.Name)
+			continue
+		}
+
+		podInfo := &pb.PodInfo{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+		}
+		containerID := podContainerID(&pod)
+
+		// The default interface is set up by the CNI when the pod is created
+		var extraInterfaces []*ovn.PodInterface
+		for _, podInterface := range podInterfaces {
+			if podInterface.Interface != "*" {
+				extraInterfaces = append(extraInterfaces, podInterface)
+			}
+		}
+		if len(extraInterfaces) != 0 {
+			data, err := json.Marshal(extraInterfaces)
+			if err != nil {
+				return err
+			}
+			msg := pb.Notification{
+				CniType: "ovn4nfv",
+				Payload: &pb.Notification_PodAddNetwork{
+					PodAddNetwork: &pb.PodAddNetwork{
+						ContainerId: containerID,
+						Pod:         podInfo,
+						Net:         &pb.NetConf{Data: string(data)},
+					},
+				},
+			}
+			c.enqueue(&msg)
+		}
+
+		routes := getPodRoutes(&pod)
+		if len(routes) != 0 {
+			var ins pb.ContainerRouteInsert
+			ins.ContainerId = containerID
+			for _, r := range routes {
+				ins.Route = append(ins.Route, &pb.RouteData{Dst: r.Dst, Gw: r.GW})
+			}
+			msg := pb.Notification{
+				CniType: "ovn4nfv",
+				Payload: &pb.Notification_ContainterRtInsert{
+					ContainterRtInsert: &ins,
+				},
+			}
+			c.enqueue(&msg)
+		}
+	}
+	return nil
+}
@@ -20,8 +20,10 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/auth"
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/kube"
@@ -33,24 +35,25 @@ import (
 	pb "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify/proto"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	kapi "k8s.io/api/core/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 var log = logf.Log.WithName("rpc-server")
 
-type client struct {
-	context *pb.SubscribeContext
-	stream  pb.NfnNotify_SubscribeServer
-}
-
 type serverDB struct {
-	name       string
-	clientList map[string]client
+	name string
+	// clientList holds the notification queue of every node, guarded by the mutex
+	sync.RWMutex
+	clientList map[string]*client
 	pb.UnimplementedNfnNotifyServer
 }
 
@@ -61,32 +64,40 @@ var pnClientset *clientset.Clientset
 var kubeClientset *kubernetes.Clientset
 
 func newServer() *serverDB {
-	return &serverDB{name: "nfnNotifServer", clientList: make(map[string]client)}
+	return &serverDB{name: "nfnNotifServer", clientList: make(map[string]*client)}
 }
 
 // Subscribe stores the client information & sends data
 func (s *serverDB) Subscribe(sc *pb.SubscribeContext, ss pb.NfnNotify_SubscribeServer) error {
 	nodeName := sc.GetNodeName()
-	log.Info("Subscribe request from node", "Node Name", nodeName)
+	log.Info("Subscribe request from node", "Node Name", nodeName, "protocol version", sc.GetProtocolVersion(), "capabilities", sc.GetCapabilities())
 	if nodeName == "" {
 		return fmt.Errorf("Node name can't be empty")
 	}
+	// Only the agent of the node gets its notifications
+	if err := auth.VerifyPeerNode(ss.Context(), nodeName); err != nil {
+		log.Error(err, "Rejected subscription", "Node Name", nodeName)
+		return status.Errorf(codes.PermissionDenied, "subscription for node %s rejected: %v", nodeName, err)
+	}
 
 	nodeIntfMacAddr, nodeIntfIPAddr, nodeIntfIPv6Addr, err := node.AddNodeLogicalPorts(nodeName)
 	if err != nil {
 		return fmt.Errorf("Error in creating node logical port for node- %s: %v", nodeName, err)
 	}
-	cp := client{
-		context: sc,
-		stream:  ss,
+	cp := s.getClient(nodeName)
+	cp.attach(sc, ss)
+	defer cp.detach(ss)
+	updateAgentCondition(cp)
+
+	for _, pn := range nameOwners() {
+		log.Info("Send message", "Provider Network", pn.GetName(), "Namespace", pn.GetNamespace())
+		SendNotif(pn, "create", nodeName)
 	}
-	s.clientList[nodeName] = cp
-
-	providerNetworklist, err := pnClientset.K8sV1alpha1().ProviderNetworks("default").List(context.TODO(), v1.ListOptions{})
-	if err == nil {
-		for _, pn := range providerNetworklist.Items {
-			log.Info("Send message", "Provider Network", pn.GetName())
-			SendNotif(&pn, "create", nodeName)
+	// Older agents would set up the interfaces and routes already present again
+	if cp.supports(pb.CapabilityResync) {
+		log.Info("Replay pod interfaces and routes", "Node Name", nodeName)
+		if err := replayPodState(nodeName, cp); err != nil {
+			log.Error(err, "Unable to replay pod state", "node name", nodeName)
 		}
 	}
 	inSyncMsg := pb.Notification{
@@ -96,30 +107,80 @@ func (s *serverDB) Subscribe(sc *pb.SubscribeContext, ss pb.NfnNotify_SubscribeS
 				NodeIntfIpAddress:   nodeIntfIPAddr,
 				NodeIntfMacAddress:  nodeIntfMacAddr,
 				NodeIntfIpv6Address: nodeIntfIPv6Addr,
+				ProtocolVersion:     pb.ProtocolVersion,
 			},
 		},
 	}
 	log.Info("Send Insync")
-	if err = cp.stream.Send(&inSyncMsg); err != nil {
-		log.Error(err, "Unable to send sync", "node name", nodeName)
-	}
+	cp.enqueue(&inSyncMsg)
 	log.Info("Subscribe Completed")
-	// Keep stream open
-	for {
-		select {
-		case <-stopChan:
-		}
+	// Keep stream open and send the queued notifications until the agent disconnects
+	if err := cp.run(ss); err != nil {
+		log.Info("Node disconnected", "Node Name", nodeName, "reason", err.Error())
 	}
+	return nil
 }
 
-func (s *serverDB) GetClient(nodeName string) client {
-	if val, ok := s.clientList[nodeName]; ok {
-		return val
+// Ack drops the notifications acknowledged by the agent
+func (s *serverDB) Ack(ctx context.Context, ac *pb.AckContext) (*pb.AckResponse, error) {
+	nodeName := ac.GetNodeName()
+	if nodeName == "" {
+		return nil, fmt.Errorf("Node name can't be empty")
+	}
+	if err := auth.VerifyPeerNode(ctx, nodeName); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "ack for node %s rejected: %v", nodeName, err)
 	}
-	return client{}
+	s.getClient(nodeName).ack(ac.GetSeq())
+	return &pb.AckResponse{}, nil
 }
 
+// nameOwners returns the provider networks and ClusterProviderNetworks that own their name, the
+// provider networks of a name created later don't create the network
+func nameOwners() []*v1alpha1.ProviderNetwork {
+	var pns []*v1alpha1.ProviderNetwork
+	providerNetworklist, err := pnClientset.K8sV1alpha1().ProviderNetworks(v1.NamespaceAll).List(context.TODO(), v1.ListOptions{})
+	if err == nil {
+		for i := range providerNetworklist.Items {
+			pns = append(pns, &providerNetworklist.Items[i])
+		}
+	}
+	clusterProviderNetworklist, err := pnClientset.K8sV1alpha1().ClusterProviderNetworks().List(context.TODO(), v1.ListOptions{})
+	if err == nil {
+		for i := range clusterProviderNetworklist.Items {
+			pns = append(pns, clusterProviderNetworklist.Items[i].ProviderNetwork())
+		}
+	}
+
+	owners := make(map[string]*v1alpha1.ProviderNetwork)
+	var names []string
+	for _, pn := range pns {
+		owner, ok := owners[pn.Name]
+		if !ok {
+			names = append(names, pn.Name)
+		}
+		if !ok || pn.OwnsNameBefore(owner) {
+			owners[pn.Name] = pn
+		}
+	}
+	var result []*v1alpha1.ProviderNetwork
+	for _, name := range names {
+		result = append(result, owners[name])
+	}
+	return result
+}
+
+// updatePnStatus sets the state of the provider network, a provider network without
+// namespace is a ClusterProviderNetwork
 func updatePnStatus(pn *v1alpha1.ProviderNetwork, status string) error {
+	if pn.Namespace == "" {
+		cpn, err := pnClientset.K8sV1alpha1().ClusterProviderNetworks().Get(context.TODO(), pn.Name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		cpn.Status.State = status
+		_, err = pnClientset.K8sV1alpha1().ClusterProviderNetworks().UpdateStatus(context.TODO(), cpn, v1.UpdateOptions{})
+		return err
+	}
 	pnCopy := pn.DeepCopy()
 	pnCopy.Status.State = status
 	_, err := pnClientset.K8sV1alpha1().ProviderNetworks(pn.Namespace).Update(context.TODO(), pnCopy, v1.UpdateOptions{})
@@ -137,6 +198,7 @@ func createVlanMsg(pn *v1alpha1.ProviderNetwork) pb.Notification {
 					ProviderIntf: pn.Spec.Vlan.ProviderInterfaceName,
 					LogicalIntf:  pn.Spec.Vlan.LogicalInterfaceName,
 				},
+				AttachType: pn.Spec.GetAttachmentType(),
 			},
 		},
 	}
@@ -165,6 +227,7 @@ func createDirectMsg(pn *v1alpha1.ProviderNetwork) pb.Notification {
 				Direct: &pb.DirectInfo{
 					ProviderIntf: pn.Spec.Direct.ProviderInterfaceName,
 				},
+				AttachType: pn.Spec.GetAttachmentType(),
 			},
 		},
 	}
@@ -256,28 +319,21 @@ func SendNotif(pn *v1alpha1.ProviderNetwork, msgType string, nodeReq string) err
 // sendMsg send notification to client
 func sendMsg(msg pb.Notification, labels string, option string, nodeReq string) error {
 	if option == "all" {
-		for name, client := range notifServer.clientList {
+		// Queue the message for the nodes not connected yet as well
+		for name := range nodeListIterator("") {
 			if nodeReq != "" && nodeReq != name {
 				continue
 			}
-			if client.stream != nil {
-				if err := client.stream.Send(&msg); err != nil {
-					log.Error(err, "Msg Send failed", "Node name", name)
-				}
-			}
+			notifServer.getClient(name).enqueue(&msg)
 		}
 		return nil
 	} else if option == "any" {
 		// Always select the first
-		for _, client := range notifServer.clientList {
-			if client.stream != nil {
-				if err := client.stream.Send(&msg); err != nil {
-					return err
-				}
-				// return after first successful send
-				return nil
-			}
+		nodes := notifServer.connectedNodes()
+		if len(nodes) == 0 {
+			return fmt.Errorf("No node connected")
 		}
+		notifServer.getClient(nodes[0]).enqueue(&msg)
 		return nil
 	}
 	// This is specific case
@@ -285,12 +341,7 @@ func sendMsg(msg pb.Notification, labels string, option string, nodeReq string)
 		if nodeReq != "" && nodeReq != name {
 			continue
 		}
-		client := notifServer.GetClient(name)
-		if client.stream != nil {
-			if err := client.stream.Send(&msg); err != nil {
-				return err
-			}
-		}
+		notifServer.getClient(name).enqueue(&msg)
 	}
 	return nil
 }
@@ -352,15 +403,10 @@ func SendRouteNotif(chainRoutingInfo []chaining.RoutingInfo, msgType string) err
 					ContainterRtInsert: &ins,
 				},
 			}
+			recordPodRoutes(r.Namespace, r.Name, ins.Route, false)
 		}
 
-		client := notifServer.GetClient(r.Node)
-		if client.stream != nil {
-			if err := client.stream.Send(&msg); err != nil {
-				log.Error(err, "Failed to send msg", "Node", r.Node)
-				return err
-			}
-		}
+		notifServer.getClient(r.Node).enqueue(&msg)
 		// TODO: Handle Delete
 	}
 	return err
@@ -416,15 +462,10 @@ func SendDeleteRouteNotif(chainRoutingInfo []chaining.RoutingInfo, msgType strin
 					ContainterRtRemove: &rve,
 				},
 			}
+			recordPodRoutes(r.Namespace, r.Name, rve.Route, true)
 		}
 
-		client := notifServer.GetClient(r.Node)
-		if client.stream != nil {
-			if err := client.stream.Send(&msg); err != nil {
-				log.Error(err, "Failed to send msg", "Node", r.Node)
-				return err
-			}
-		}
+		notifServer.getClient(r.Node).enqueue(&msg)
 	}
 	return err
 }
@@ -466,14 +507,9 @@ func SendPodNetworkNotif(pni []chaining.PodNetworkInfo, msgType string) error {
 					PodAddNetwork: &add,
 				},
 			}
+			recordPodRoutes(p.Namespace, p.Name, add.Route, false)
 		}
-		client := notifServer.GetClient(p.Node)
-		if client.stream != nil {
-			if err := client.stream.Send(&msg); err != nil {
-				log.Error(err, "Failed to send msg", "Node", p.Node)
-				return err
-			}
-		}
+		notifServer.getClient(p.Node).enqueue(&msg)
 		// TODO: Handle Delete
 	}
 	return err
@@ -516,14 +552,9 @@ func SendDeletePodNetworkNotif(pni []chaining.PodNetworkInfo, msgType string) er
 					PodDelNetwork: &rve,
 				},
 			}
+			recordPodRoutes(p.Namespace, p.Name, rve.Route, true)
 		}
-		client := notifServer.GetClient(p.Node)
-		if client.stream != nil {
-			if err := client.stream.Send(&msg); err != nil {
-				log.Error(err, "Failed to send msg", "Node", p.Node)
-				return err
-			}
-		}
+		notifServer.getClient(p.Node).enqueue(&msg)
 	}
 	return err
 }
@@ -548,8 +579,9 @@ func nodeListIterator(labels string) <-chan string {
 	return ch
 }
 
-//SetupNotifServer initilizes the gRpc nfn notif server
-func SetupNotifServer(kConfig *rest.Config) {
+//SetupNotifServer initilizes the gRpc nfn notif server, the admission webhook handlers are
+//served by path with the node certificates
+func SetupNotifServer(kConfig *rest.Config, webhooks map[string]http.Handler) {
 	log.Info("Starting Notif Server")
 	var err error
 
@@ -576,35 +608,56 @@ func SetupNotifServer(kConfig *rest.Config) {
 
 	kubecli := &kube.Kube{KClient: kubeClientset}
 
-	// get certificate
-	crt, err := auth.GetCert(namespace, auth.DefaultCert)
+	certProvider, err := auth.NewCertProvider(kubecli, namespace)
 	if err != nil {
-		log.Error(err, "Error while obtaining the certificate")
+		log.Error(err, "Error while creating the certificate provider")
+		return
 	}
+	// the certificate provider issues and rotates the certificates of the nfn-operator, the nfn-agents
+	// and the CNI servers
+	certProvider.Run(nfnSvcIP, wait.NeverStop)
 
-	// load secret associated with obtained certificate
-	var sec *kapi.Secret
-	if !auth.IsCertIPUpToDate(crt, nfnSvcIP) {
-		// update certificate IP if not up-to-date
-		crt, sec, err = auth.UpdateCertIP(crt, nfnSvcIP)
-		if err != nil {
-			log.Error(err, "Error while updating the certificate")
-		}
-	} else {
-		// wait for secret to be updated
-		sec, err = auth.WaitForSecretIP(kubecli, crt)
-	}
+	// load the secret of the certificate issued for the service IP address
+	sec, err := certProvider.ServerSecret(nfnSvcIP)
 	if err != nil {
 		log.Error(err, "Error while obtaining secret")
+		return
 	}
 
-	// create TLS config from secret
-	serverTLS, err := auth.CreateServerTLSFromSecret(sec)
+	// create TLS config from secret, the certificate is reloaded when the certificate provider renews it
+	reloader, err := auth.NewCertReloader(sec)
 	if err != nil {
 		log.Error(err, "Error while creating TLS configuration")
+		return
+	}
+	// the nfn-agents verify the nfn-operator with the published CA bundle before they have a
+	// certificate, the API server verifies the admission webhooks with it
+	publishCABundle := func(secret *corev1.Secret) {
+		if err := auth.PublishCABundle(kubeClientset, namespace, secret); err != nil {
+			log.Error(err, "Error while publishing the CA bundle")
+		}
+		if err := auth.PublishWebhookCABundle(kubeClientset, auth.WebhookConfigName, secret); err != nil {
+			log.Error(err, "Error while publishing the CA bundle of the admission webhooks")
+		}
+	}
+	publishCABundle(sec)
+	reloader.OnUpdate = publishCABundle
+	reloader.Watch(kubeClientset, namespace, sec.Name, wait.NeverStop)
+
+	// the nfn-agents can't read the node secrets, they obtain their certificate from the nfn-operator
+	mux := http.NewServeMux()
+	mux.Handle(auth.NodeCertPath, auth.NewNodeCertHandler(kubeClientset, namespace, certProvider))
+	for path, handler := range webhooks {
+		mux.Handle(path, handler)
 	}
+	httpsServer := &http.Server{Addr: auth.HTTPSAddr, Handler: mux, TLSConfig: reloader.ServingTLSConfig()}
+	go func() {
+		if err := httpsServer.ListenAndServeTLS("", ""); err != nil {
+			log.Error(err, "failed to serve the node certificates and the admission webhooks")
+		}
+	}()
 
-	s := grpc.NewServer(grpc.Creds(*serverTLS))
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(reloader.ServerTLSConfig())))
 	// Intialize Notify server
 	notifServer = newServer()
 	pb.RegisterNfnNotifyServer(s, notifServer)
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nfn
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	pb "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify/proto"
+
+	kapi "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// AgentConditionType is the node condition reporting if the nfn-agent of the node handles
+// the notifications of the nfn-operator
+const AgentConditionType kapi.NodeConditionType = "NfnAgentCompatible"
+
+// compatibility returns the status, reason and message of the agent node condition
+func (c *client) compatibility() (kapi.ConditionStatus, string, string) {
+	c.Lock()
+	defer c.Unlock()
+	if len(c.missing) != 0 {
+		var missing []string
+		for capability := range c.missing {
+			missing = append(missing, capability)
+		}
+		sort.Strings(missing)
+		return kapi.ConditionFalse, "MissingCapabilities",
+			fmt.Sprintf("nfn-agent protocol version %d lacks the capabilities %s, notifications were dropped", c.version, strings.Join(missing, ","))
+	}
+	if c.version < pb.ProtocolVersion {
+		return kapi.ConditionFalse, "OutdatedAgent",
+			fmt.Sprintf("nfn-agent protocol version %d is older than the nfn-operator protocol version %d", c.version, pb.ProtocolVersion)
+	}
+	return kapi.ConditionTrue, "Compatible", fmt.Sprintf("nfn-agent protocol version %d", c.version)
+}
+
+// updateAgentCondition sets the agent condition of the node of the client
+func updateAgentCondition(c *client) {
+	if kubeClientset == nil {
+		return
+	}
+	status, reason, message := c.compatibility()
+	if status == kapi.ConditionFalse {
+		log.Info("Incompatible nfn-agent", "Node", c.node, "reason", reason, "message", message)
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := kubeClientset.CoreV1().Nodes().Get(context.TODO(), c.node, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		now := v1.Now()
+		condition := kapi.NodeCondition{
+			Type:               AgentConditionType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastHeartbeatTime:  now,
+			LastTransitionTime: now,
+		}
+		found := false
+		for i, cond := range node.Status.Conditions {
+			if cond.Type != AgentConditionType {
+				continue
+			}
+			if cond.Status == status && cond.Reason == reason && cond.Message == message {
+				return nil
+			}
+			if cond.Status == status {
+				condition.LastTransitionTime = cond.LastTransitionTime
+			}
+			node.Status.Conditions[i] = condition
+			found = true
+		}
+		if !found {
+			node.Status.Conditions = append(node.Status.Conditions, condition)
+		}
+		_, err = kubeClientset.CoreV1().Nodes().UpdateStatus(context.TODO(), node, v1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		log.Error(err, "Failed to update the nfn-agent condition", "Node", c.node)
+	}
+}
+
+// NodeSupports returns true if the nfn-agent of the node has the capability. A missing
+// capability is reported in the agent condition of the node.
+func NodeSupports(nodeName, capability string) bool {
+	if notifServer == nil {
+		return true
+	}
+	c := notifServer.getClient(nodeName)
+	if c.supports(capability) {
+		return true
+	}
+	if c.requireCapability(capability) {
+		updateAgentCondition(c)
+	}
+	return false
+}
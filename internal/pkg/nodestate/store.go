@@ -0,0 +1,176 @@
+/*
+ * Copyright 2020 Intel Corporation, Inc
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nodestate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	pb "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify/proto"
+
+	kapi "k8s.io/api/core/v1"
+)
+
+const (
+	// StateDir is the host directory holding the state of the nfn-agent, it survives the
+	// restarts of the agent and of the node
+	StateDir = "/var/lib/nfn-agent"
+
+	stateFile = "state.json"
+	secretDir = "secrets"
+)
+
+// State is the last known desired state of the node, as requested by the nfn-operator, and
+// the network annotations of the pods of the node
+type State struct {
+	ProviderNetworks []*pb.ProviderNetworkCreate `json:"providerNetworks,omitempty"`
+	NodeIntf         *pb.InSync                  `json:"nodeIntf,omitempty"`
+	// PodRoutes are the routes pushed to the containers, by container ID
+	PodRoutes map[string][]*pb.RouteData `json:"podRoutes,omitempty"`
+	// Pods are the network annotations of the pods, by namespace/name
+	Pods map[string]map[string]string `json:"pods,omitempty"`
+	// ContainerRuntimeVersion is the container runtime of the node
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion,omitempty"`
+}
+
+// Store keeps the state in a file so that the nfn-agent restores it when it starts while the
+// nfn-operator or the Kubernetes API are unreachable
+type Store struct {
+	sync.Mutex
+	path  string
+	state State
+}
+
+// Open returns the store of the directory, the state is loaded from the file if it exists
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	s := &Store{path: filepath.Join(dir, stateFile)}
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %v", s.path, err)
+	}
+	return s, nil
+}
+
+// Get returns the state, it must not be modified
+func (s *Store) Get() State {
+	s.Lock()
+	defer s.Unlock()
+	return s.state
+}
+
+// Update changes the state with fn and writes it to the file
+func (s *Store) Update(fn func(*State)) error {
+	s.Lock()
+	defer s.Unlock()
+	fn(&s.state)
+	data, err := json.Marshal(&s.state)
+	if err != nil {
+		return err
+	}
+	return writeFile(s.path, data)
+}
+
+func podKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// PodAnnotations returns the stored network annotations of the pod
+func (s *Store) PodAnnotations(namespace, name string) (map[string]string, bool) {
+	s.Lock()
+	defer s.Unlock()
+	annotations, ok := s.state.Pods[podKey(namespace, name)]
+	return annotations, ok
+}
+
+// SetPodAnnotations stores the network annotations of the pod
+func (s *Store) SetPodAnnotations(namespace, name string, annotations map[string]string) error {
+	return s.Update(func(state *State) {
+		if state.Pods == nil {
+			state.Pods = make(map[string]map[string]string)
+		}
+		state.Pods[podKey(namespace, name)] = annotations
+	})
+}
+
+// PrunePods forgets the annotations of the pods not in the list of namespace/name keys
+func (s *Store) PrunePods(pods map[string]bool) error {
+	return s.Update(func(state *State) {
+		for key := range state.Pods {
+			if !pods[key] {
+				delete(state.Pods, key)
+			}
+		}
+	})
+}
+
+// PodKey returns the key of the pod in the stored pods
+func PodKey(pod *kapi.Pod) string {
+	return podKey(pod.Namespace, pod.Name)
+}
+
+// SaveSecret stores a copy of the secret in the directory, it is used by the CNI server and
+// the CNI shim when the Kubernetes API is unreachable
+func SaveSecret(dir string, secret *kapi.Secret) error {
+	path := filepath.Join(dir, secretDir)
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(&kapi.Secret{
+		ObjectMeta: secret.ObjectMeta,
+		Type:       secret.Type,
+		Data:       secret.Data,
+	})
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(path, secret.Name+".json"), data)
+}
+
+// LoadSecret returns the copy of the secret stored in the directory
+func LoadSecret(dir, name string) (*kapi.Secret, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, secretDir, name+".json"))
+	if err != nil {
+		return nil, err
+	}
+	secret := &kapi.Secret{}
+	if err := json.Unmarshal(data, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// writeFile replaces the file atomically so that a crash never leaves a partial file
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
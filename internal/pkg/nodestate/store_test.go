@@ -0,0 +1,135 @@
+package nodestate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify/proto"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeState(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Node State Test Suite")
+}
+
+var _ = Describe("Test node state store", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "nodestate")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("persists the state across the restarts of the agent", func() {
+		s, err := Open(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Get()).To(Equal(State{}))
+
+		Expect(s.Update(func(state *State) {
+			state.ContainerRuntimeVersion = "containerd://1.5.9"
+			state.ProviderNetworks = []*pb.ProviderNetworkCreate{{ProviderNwName: "pnet"}}
+			state.PodRoutes = map[string][]*pb.RouteData{"c1": {{Dst: "10.0.0.0/24", Gw: "10.0.1.1"}}}
+		})).To(Succeed())
+		Expect(s.SetPodAnnotations("ns", "pod", map[string]string{"k8s.plugin.opnfv.org/nfn-network": "net"})).To(Succeed())
+
+		loaded, err := Open(dir)
+		Expect(err).NotTo(HaveOccurred())
+		state := loaded.Get()
+		Expect(state.ContainerRuntimeVersion).To(Equal("containerd://1.5.9"))
+		Expect(state.ProviderNetworks).To(HaveLen(1))
+		Expect(state.ProviderNetworks[0].GetProviderNwName()).To(Equal("pnet"))
+		Expect(state.PodRoutes["c1"]).To(HaveLen(1))
+		Expect(state.PodRoutes["c1"][0].GetGw()).To(Equal("10.0.1.1"))
+		annotations, ok := loaded.PodAnnotations("ns", "pod")
+		Expect(ok).To(BeTrue())
+		Expect(annotations).To(HaveKeyWithValue("k8s.plugin.opnfv.org/nfn-network", "net"))
+	})
+
+	It("replaces the state file atomically", func() {
+		s, err := Open(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Update(func(state *State) { state.ContainerRuntimeVersion = "v1" })).To(Succeed())
+
+		// a write interrupted by a crash leaves the temporary file, the state file is intact
+		path := filepath.Join(dir, stateFile)
+		Expect(ioutil.WriteFile(path+".tmp", []byte(`{"containerRuntimeVersion":`), 0600)).To(Succeed())
+		loaded, err := Open(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Get().ContainerRuntimeVersion).To(Equal("v1"))
+
+		Expect(loaded.Update(func(state *State) { state.ContainerRuntimeVersion = "v2" })).To(Succeed())
+		_, err = os.Stat(path + ".tmp")
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		info, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	table.DescribeTable("loads the state file",
+		func(content string, valid bool) {
+			if content != "" {
+				Expect(ioutil.WriteFile(filepath.Join(dir, stateFile), []byte(content), 0600)).To(Succeed())
+			}
+			_, err := Open(dir)
+			Expect(err == nil).To(Equal(valid))
+		},
+		table.Entry("missing file", "", true),
+		table.Entry("state of the agent", `{"containerRuntimeVersion":"v1","pods":{"ns/pod":{}}}`, true),
+		table.Entry("partial file", `{"containerRuntimeVersion":`, false),
+	)
+
+	table.DescribeTable("prunes the annotations of the deleted pods",
+		func(pods map[string]bool, kept []string) {
+			s, err := Open(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.SetPodAnnotations("ns", "a", map[string]string{})).To(Succeed())
+			Expect(s.SetPodAnnotations("ns", "b", map[string]string{})).To(Succeed())
+			Expect(s.PrunePods(pods)).To(Succeed())
+
+			loaded, err := Open(dir)
+			Expect(err).NotTo(HaveOccurred())
+			var keys []string
+			for key := range loaded.Get().Pods {
+				keys = append(keys, key)
+			}
+			if kept == nil {
+				Expect(keys).To(BeEmpty())
+			} else {
+				Expect(keys).To(ConsistOf(kept))
+			}
+		},
+		table.Entry("all the pods running", map[string]bool{"ns/a": true, "ns/b": true}, []string{"ns/a", "ns/b"}),
+		table.Entry("a pod deleted", map[string]bool{"ns/b": true, "ns/c": true}, []string{"ns/b"}),
+		table.Entry("all the pods deleted", map[string]bool{}, nil),
+	)
+
+	It("stores a copy of the secrets", func() {
+		secret := &kapi.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodus-cni-cert", Namespace: "kube-system"},
+			Type:       kapi.SecretTypeTLS,
+			Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+		}
+		Expect(SaveSecret(dir, secret)).To(Succeed())
+		loaded, err := LoadSecret(dir, "nodus-cni-cert")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Namespace).To(Equal("kube-system"))
+		Expect(loaded.Type).To(Equal(kapi.SecretTypeTLS))
+		Expect(loaded.Data).To(Equal(secret.Data))
+
+		_, err = LoadSecret(dir, "nodus-cert")
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,291 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/kube"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/network"
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// OvnSubnetSource is the kind of the subnets of the default network set by OVN_SUBNET and OVN_SUBNET_V6
+	OvnSubnetSource = "OVN_SUBNET"
+	// ServiceSubnetSource is the kind of the service subnet of the kubeadm cluster configuration
+	ServiceSubnetSource = "ServiceSubnet"
+	// HostNetworkSource is the kind of the network of the default gateway interface of the host of
+	// the nfn-operator, its overlaps are only reported as warnings
+	HostNetworkSource = "HostNetwork"
+	// AddressConflictReason is the reason of the events raised for overlapping subnets
+	AddressConflictReason = "AddressConflict"
+)
+
+// AddressSource is a subnet used in the cluster and the object it comes from. The namespace and
+// the name are empty for the sources which are not objects
+type AddressSource struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Subnet    *net.IPNet
+	// pool is the NetworkPool of the subnet of a network created from a pool
+	pool string
+}
+
+func (s AddressSource) String() string {
+	switch {
+	case s.Namespace != "":
+		return fmt.Sprintf("%s %s/%s (%s)", s.Kind, s.Namespace, s.Name, s.Subnet)
+	case s.Name != "":
+		return fmt.Sprintf("%s %s (%s)", s.Kind, s.Name, s.Subnet)
+	}
+	return fmt.Sprintf("%s (%s)", s.Kind, s.Subnet)
+}
+
+// sameObject checks if the sources come from the same object
+func (s AddressSource) sameObject(o AddressSource) bool {
+	return s.Kind == o.Kind && s.Namespace == o.Namespace && s.Name == o.Name
+}
+
+// overlaps checks if the subnets of the sources overlap. The subnets of an object do not conflict
+// with each other, nor the subnet of a network with the pool it is taken from
+func (s AddressSource) overlaps(o AddressSource) bool {
+	if s.sameObject(o) {
+		return false
+	}
+	if (o.Kind == "NetworkPool" && s.pool == o.Name) || (s.Kind == "NetworkPool" && o.pool == s.Name) {
+		return false
+	}
+	return s.Subnet.Contains(o.Subnet.IP) || o.Subnet.Contains(s.Subnet.IP)
+}
+
+// AddressRegistry is the address space of the cluster: the subnets of the default network, the
+// networks, the provider networks, the network pools, the service subnet and the host network
+type AddressRegistry struct {
+	sources []AddressSource
+}
+
+// clusterSourcesTTL is how long the address sources which are not objects are cached
+const clusterSourcesTTL = 10 * time.Minute
+
+// clusterSources caches the address sources which are not objects, the kubeadm configuration and
+// the host network are not read again on every reconcile
+var clusterSources struct {
+	sync.Mutex
+	sources []AddressSource
+	expires time.Time
+}
+
+// getClusterSources returns the subnets of the default network, the service subnet and the host
+// network. The sources which cannot be read, like the service subnet of a cluster not set up by
+// kubeadm, are left out
+func getClusterSources() []AddressSource {
+	clusterSources.Lock()
+	defer clusterSources.Unlock()
+	if time.Now().Before(clusterSources.expires) {
+		return clusterSources.sources
+	}
+
+	r := &AddressRegistry{}
+	if ovnConf != nil {
+		r.add(AddressSource{Kind: OvnSubnetSource}, ovnConf.Subnetv4, ovnConf.Subnetv6)
+	}
+	if k8sv1ClientSet, err := kube.GetKubeConfig(); err != nil {
+		log.V(1).Info("Skipping the service subnet", "error", err.Error())
+	} else {
+		kubecli := &kube.Kube{KClient: k8sv1ClientSet}
+		if kn, err := kubecli.GetControlPlaneServiceIPRange(); err != nil {
+			log.V(1).Info("Skipping the service subnet", "error", err.Error())
+		} else {
+			r.add(AddressSource{Kind: ServiceSubnetSource}, strings.Split(kn.ServiceSubnet, ",")...)
+		}
+	}
+	if hostNet, err := network.GetHostNetwork(); err != nil {
+		log.V(1).Info("Skipping the host network", "error", err.Error())
+	} else {
+		r.add(AddressSource{Kind: HostNetworkSource}, hostNet)
+	}
+
+	clusterSources.sources = r.sources
+	clusterSources.expires = time.Now().Add(clusterSourcesTTL)
+	return r.sources
+}
+
+// NewAddressRegistry reads the address sources of the cluster, the objects are read with the
+// client, usually the cached client of the manager
+func NewAddressRegistry(ctx context.Context, c client.Reader) (*AddressRegistry, error) {
+	r := &AddressRegistry{}
+	r.sources = append(r.sources, getClusterSources()...)
+
+	networks := &k8sv1alpha1.NetworkList{}
+	if err := c.List(ctx, networks); err != nil {
+		return nil, err
+	}
+	for _, n := range networks.Items {
+		if n.DeletionTimestamp.IsZero() {
+			r.addNetwork(&n)
+		}
+	}
+	providerNetworks := &k8sv1alpha1.ProviderNetworkList{}
+	if err := c.List(ctx, providerNetworks); err != nil {
+		return nil, err
+	}
+	for _, pn := range providerNetworks.Items {
+		if pn.DeletionTimestamp.IsZero() {
+			r.addProviderNetwork("ProviderNetwork", &pn)
+		}
+	}
+	clusterProviderNetworks := &k8sv1alpha1.ClusterProviderNetworkList{}
+	err := c.List(ctx, clusterProviderNetworks)
+	if err != nil && !notServed(err) {
+		return nil, err
+	}
+	if err == nil {
+		for _, cpn := range clusterProviderNetworks.Items {
+			if cpn.DeletionTimestamp.IsZero() {
+				r.addProviderNetwork("ClusterProviderNetwork", &k8sv1alpha1.ProviderNetwork{ObjectMeta: cpn.ObjectMeta, Spec: cpn.Spec})
+			}
+		}
+	}
+	pools := &k8sv1alpha1.NetworkPoolList{}
+	err = c.List(ctx, pools)
+	if err != nil && !notServed(err) {
+		return nil, err
+	}
+	if err == nil {
+		for _, p := range pools.Items {
+			r.add(AddressSource{Kind: "NetworkPool", Name: p.Name}, p.Spec.Network, p.Spec.Ipv6Network)
+		}
+	}
+	return r, nil
+}
+
+// notServed checks if the error is returned for a resource not installed in the cluster
+func notServed(err error) bool {
+	return errors.IsNotFound(err) || meta.IsNoMatchError(err)
+}
+
+// add adds the subnets of the source, the empty and invalid subnets are skipped
+func (r *AddressRegistry) add(source AddressSource, subnets ...string) {
+	for _, subnet := range subnets {
+		subnet = strings.TrimSpace(subnet)
+		if subnet == "" {
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(subnet)
+		if err != nil {
+			log.Info("Skipping the invalid subnet", "source", source.Kind, "namespace", source.Namespace, "name", source.Name, "subnet", subnet)
+			continue
+		}
+		s := source
+		s.Subnet = cidr
+		r.sources = append(r.sources, s)
+	}
+}
+
+// networkSources returns the sources of the subnets of a network
+func networkSources(kind string, obj metav1.Object, pool string, ipv4Subnets, ipv6Subnets []k8sv1alpha1.IpSubnet) *AddressRegistry {
+	r := &AddressRegistry{}
+	source := AddressSource{Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), pool: pool}
+	for _, sn := range append(append([]k8sv1alpha1.IpSubnet{}, ipv4Subnets...), ipv6Subnets...) {
+		r.add(source, sn.Subnet)
+	}
+	return r
+}
+
+// addNetwork adds the subnets of the Network
+func (r *AddressRegistry) addNetwork(cr *k8sv1alpha1.Network) {
+	n := networkSources("Network", cr, network.NetworkPoolOf(cr), cr.Spec.Ipv4Subnets, cr.Spec.Ipv6Subnets)
+	r.sources = append(r.sources, n.sources...)
+}
+
+// addProviderNetwork adds the subnets of the ProviderNetwork or ClusterProviderNetwork of the kind
+func (r *AddressRegistry) addProviderNetwork(kind string, cr *k8sv1alpha1.ProviderNetwork) {
+	n := networkSources(kind, cr, "", cr.Spec.Ipv4Subnets, cr.Spec.Ipv6Subnets)
+	r.sources = append(r.sources, n.sources...)
+}
+
+// conflicts returns the sources of the registry overlapping the subnets of the other registry. The
+// host network is only known for the node of the nfn-operator, its overlaps are returned apart as
+// warnings instead of conflicts
+func (r *AddressRegistry) conflicts(o *AddressRegistry) (conflicts, warnings []string) {
+	seen := make(map[string]bool)
+	for _, s := range o.sources {
+		for _, c := range r.sources {
+			if !s.overlaps(c) || seen[c.String()] {
+				continue
+			}
+			seen[c.String()] = true
+			if c.Kind == HostNetworkSource {
+				warnings = append(warnings, c.String())
+			} else {
+				conflicts = append(conflicts, c.String())
+			}
+		}
+	}
+	sort.Strings(conflicts)
+	sort.Strings(warnings)
+	return conflicts, warnings
+}
+
+// NetworkConflicts returns the sources overlapping the subnets of the Network and the overlaps
+// with the host network
+func (r *AddressRegistry) NetworkConflicts(cr *k8sv1alpha1.Network) (conflicts, warnings []string) {
+	n := networkSources("Network", cr, network.NetworkPoolOf(cr), cr.Spec.Ipv4Subnets, cr.Spec.Ipv6Subnets)
+	return r.conflicts(n)
+}
+
+// ProviderNetworkConflicts returns the sources overlapping the subnets of the ProviderNetwork or
+// ClusterProviderNetwork of the kind and the overlaps with the host network
+func (r *AddressRegistry) ProviderNetworkConflicts(kind string, cr *k8sv1alpha1.ProviderNetwork) (conflicts, warnings []string) {
+	n := networkSources(kind, cr, "", cr.Spec.Ipv4Subnets, cr.Spec.Ipv6Subnets)
+	return r.conflicts(n)
+}
+
+// PoolConflicts returns the sources overlapping the supernets of the NetworkPool and the overlaps
+// with the host network
+func (r *AddressRegistry) PoolConflicts(pool *k8sv1alpha1.NetworkPool) (conflicts, warnings []string) {
+	p := &AddressRegistry{}
+	p.add(AddressSource{Kind: "NetworkPool", Name: pool.Name}, pool.Spec.Network, pool.Spec.Ipv6Network)
+	return r.conflicts(p)
+}
+
+// Conflicts returns the pairs of overlapping sources of the registry
+func (r *AddressRegistry) Conflicts() [][2]AddressSource {
+	var conflicts [][2]AddressSource
+	for i, s := range r.sources {
+		for _, o := range r.sources[i+1:] {
+			if s.overlaps(o) {
+				conflicts = append(conflicts, [2]AddressSource{s, o})
+			}
+		}
+	}
+	return conflicts
+}
+
+// CheckAddressConflicts logs the overlapping sources of the address space of the cluster
+func CheckAddressConflicts(c client.Reader) error {
+	r, err := NewAddressRegistry(context.TODO(), c)
+	if err != nil {
+		log.Error(err, "Failed to read the address sources")
+		return err
+	}
+	for _, c := range r.Conflicts() {
+		if c[0].Kind == HostNetworkSource || c[1].Kind == HostNetworkSource {
+			log.Info("Subnet overlaps the host network of the nfn-operator node", "source", c[0].String(), "conflict", c[1].String())
+			continue
+		}
+		log.Error(fmt.Errorf("overlapping subnets"), "Address conflict", "source", c[0].String(), "conflict", c[1].String())
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package ovn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// AddressValidationPath is the path of the admission webhook validating the subnets
+const AddressValidationPath = "/validate-subnets"
+
+// addressValidator rejects the networks, provider networks and network pools whose subnets
+// overlap the address space of the cluster when they are created or their subnets are updated
+type addressValidator struct {
+	client client.Reader
+}
+
+// NewAddressValidator returns the admission webhook handler validating the subnets against the
+// address space read with the client
+func NewAddressValidator(c client.Reader) (http.Handler, error) {
+	return admission.StandaloneWebhook(&admission.Webhook{Handler: &addressValidator{client: c}},
+		admission.StandaloneOptions{Logger: log.WithName("address-validator")})
+}
+
+func (v *addressValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	obj, subnets, err := requestSubnets(req.Kind.Kind, req.Object.Raw)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if obj == nil || obj.GetDeletionTimestamp() != nil {
+		// the finalizers of the objects being deleted are removed whatever their subnets
+		return admission.Allowed("")
+	}
+	if req.Operation == admissionv1.Update {
+		// only the subnet changes are checked, the metadata and status updates are allowed
+		_, oldSubnets, err := requestSubnets(req.Kind.Kind, req.OldObject.Raw)
+		if err == nil && reflect.DeepEqual(subnets, oldSubnets) {
+			return admission.Allowed("")
+		}
+	}
+
+	registry, err := NewAddressRegistry(ctx, v.client)
+	if err != nil {
+		// the controllers check the conflicts again, don't block the request
+		log.Error(err, "Failed to read the address sources")
+		return admission.Allowed("").WithWarnings(fmt.Sprintf("address conflicts not checked: %v", err))
+	}
+
+	var conflicts, warnings []string
+	switch cr := obj.(type) {
+	case *k8sv1alpha1.Network:
+		conflicts, warnings = registry.NetworkConflicts(cr)
+	case *k8sv1alpha1.ProviderNetwork:
+		conflicts, warnings = registry.ProviderNetworkConflicts(req.Kind.Kind, cr)
+	case *k8sv1alpha1.NetworkPool:
+		conflicts, warnings = registry.PoolConflicts(cr)
+	}
+
+	for i, w := range warnings {
+		warnings[i] = fmt.Sprintf("subnets overlap %s", w)
+	}
+	if len(conflicts) > 0 {
+		return admission.Denied(fmt.Sprintf("subnets overlap %s", strings.Join(conflicts, ", "))).WithWarnings(warnings...)
+	}
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+// requestSubnets decodes the object of the kind and returns its subnets, the object is nil for the
+// kinds without subnets
+func requestSubnets(kind string, raw []byte) (metav1.Object, []string, error) {
+	var subnets []string
+	switch kind {
+	case "Network":
+		cr := &k8sv1alpha1.Network{}
+		if err := json.Unmarshal(raw, cr); err != nil {
+			return nil, nil, err
+		}
+		for _, sn := range append(append([]k8sv1alpha1.IpSubnet{}, cr.Spec.Ipv4Subnets...), cr.Spec.Ipv6Subnets...) {
+			subnets = append(subnets, sn.Subnet)
+		}
+		return cr, subnets, nil
+	case "ProviderNetwork", "ClusterProviderNetwork":
+		cr := &k8sv1alpha1.ProviderNetwork{}
+		if err := json.Unmarshal(raw, cr); err != nil {
+			return nil, nil, err
+		}
+		for _, sn := range append(append([]k8sv1alpha1.IpSubnet{}, cr.Spec.Ipv4Subnets...), cr.Spec.Ipv6Subnets...) {
+			subnets = append(subnets, sn.Subnet)
+		}
+		return cr, subnets, nil
+	case "NetworkPool":
+		pool := &k8sv1alpha1.NetworkPool{}
+		if err := json.Unmarshal(raw, pool); err != nil {
+			return nil, nil, err
+		}
+		return pool, []string{pool.Spec.Network, pool.Spec.Ipv6Network}, nil
+	}
+	return nil, nil, nil
+}
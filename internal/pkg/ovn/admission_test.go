@@ -0,0 +1,94 @@
+package ovn
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// poolNetwork returns a network of the default namespace created from a pool before the
+// NetworkPoolLabel, it only has the net label
+func poolNetwork(name, subnet string) *k8sv1alpha1.Network {
+	return &k8sv1alpha1.Network{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"net": name}},
+		Spec:       k8sv1alpha1.NetworkSpec{Ipv4Subnets: []k8sv1alpha1.IpSubnet{{Name: "subnet", Subnet: subnet}}},
+	}
+}
+
+// networkRequest returns the admission request of the operation on the network
+func networkRequest(op admissionv1.Operation, cr, old *k8sv1alpha1.Network) admission.Request {
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: op,
+		Kind:      metav1.GroupVersionKind{Group: "k8s.plugin.opnfv.org", Version: "v1alpha1", Kind: "Network"},
+	}}
+	req.Object.Raw, _ = json.Marshal(cr)
+	if old != nil {
+		req.OldObject.Raw, _ = json.Marshal(old)
+	}
+	return req
+}
+
+var _ = Describe("Test address validation", func() {
+	var validator *addressValidator
+
+	BeforeEach(func() {
+		// the sources out of the API objects are not read in the tests
+		clusterSources.Lock()
+		clusterSources.sources = nil
+		clusterSources.expires = time.Now().Add(time.Hour)
+		clusterSources.Unlock()
+
+		scheme := runtime.NewScheme()
+		Expect(k8sv1alpha1.AddToScheme(scheme)).To(Succeed())
+		pool := &k8sv1alpha1.NetworkPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       k8sv1alpha1.NetworkPoolSpec{Network: "172.30.0.0/16"},
+		}
+		validator = &addressValidator{client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(pool, poolNetwork("net-a", "172.30.1.0/24")).Build()}
+	})
+
+	table.DescribeTable("validates the network requests",
+		func(op admissionv1.Operation, cr, old *k8sv1alpha1.Network, allowed bool) {
+			resp := validator.Handle(context.TODO(), networkRequest(op, cr, old))
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+		table.Entry("network of the default pool", admissionv1.Create,
+			poolNetwork("net-b", "172.30.2.0/24"), nil, true),
+		table.Entry("network overlapping another network", admissionv1.Create,
+			poolNetwork("net-b", "172.30.1.0/25"), nil, false),
+		table.Entry("network of another namespace overlapping the pool", admissionv1.Create,
+			func() *k8sv1alpha1.Network {
+				cr := poolNetwork("net-b", "172.30.2.0/24")
+				cr.Namespace = "tenant"
+				return cr
+			}(), nil, false),
+		table.Entry("metadata update of an overlapping network", admissionv1.Update,
+			func() *k8sv1alpha1.Network {
+				cr := poolNetwork("net-b", "172.30.1.0/25")
+				cr.Finalizers = []string{"nfnCleanUpNetwork"}
+				return cr
+			}(), poolNetwork("net-b", "172.30.1.0/25"), true),
+		table.Entry("subnet update overlapping another network", admissionv1.Update,
+			poolNetwork("net-b", "172.30.1.0/25"), poolNetwork("net-b", "172.30.2.0/24"), false),
+		table.Entry("overlapping network being deleted", admissionv1.Update,
+			func() *k8sv1alpha1.Network {
+				cr := poolNetwork("net-b", "172.30.1.0/26")
+				now := metav1.Now()
+				cr.DeletionTimestamp = &now
+				return cr
+			}(), poolNetwork("net-b", "172.30.2.0/24"), true),
+		table.Entry("deletion", admissionv1.Delete,
+			poolNetwork("net-b", "172.30.1.0/25"), nil, true),
+	)
+})
@@ -157,13 +157,25 @@ func GetPnBridge(externalID string) []string {
 	return brList
 }
 
-// Update ovn-bridge-mappings
-func updateOvnBridgeMapping(brName, nwName, action string) error {
+// BridgeExists checks if the OVS bridge exists
+func BridgeExists(brName string) bool {
+	_, _, err := RunOVSVsctl("br-exists", brName)
+	return err == nil
+}
+
+// BridgeHasPort checks if the interface is a port of the OVS bridge
+func BridgeHasPort(brName, intfName string) bool {
+	stdout, _, err := RunOVSVsctl("port-to-br", intfName)
+	return err == nil && stdout == brName
+}
+
+// GetOvnBridgeMappings returns the ovn-bridge-mappings of the node, network name to bridge name
+func GetOvnBridgeMappings() (map[string]string, error) {
 	stdout, stderr, err := RunOVSVsctl("get", "open", ".", "external-ids:ovn-bridge-mappings")
 	if err != nil {
 		if !strings.Contains(stderr, "no key") {
 			log.Error(err, "Failed to get ovn-bridge-mappings", "stdout", stdout, "stderr", stderr)
-			return err
+			return nil, err
 		}
 	}
 	// Convert csv string to map
@@ -172,13 +184,22 @@ func updateOvnBridgeMapping(brName, nwName, action string) error {
 		am := strings.Split(stdout, ",")
 		for _, label := range am {
 			l := strings.Split(label, ":")
-			if len(l) == 0 {
-				log.Error(fmt.Errorf("Syntax error label: %v", label), "ovnBridgeMapping")
-				return nil
+			if len(l) < 2 {
+				return nil, fmt.Errorf("Syntax error label: %v", label)
 			}
 			mm[strings.TrimSpace(l[0])] = strings.TrimSpace(l[1])
 		}
 	}
+	return mm, nil
+}
+
+// Update ovn-bridge-mappings
+func updateOvnBridgeMapping(brName, nwName, action string) error {
+	mm, err := GetOvnBridgeMappings()
+	if err != nil {
+		return err
+	}
+	var stdout, stderr string
 	if action == "add" {
 		mm[nwName] = brName
 	} else if action == "delete" {
@@ -409,6 +430,39 @@ func GetNetworkSubnet(nw string) (string, error) {
 	return stdout, nil
 }
 
+// PodPortCount returns the number of pod ports of the logical switch
+func PodPortCount(logicalSwitch string) (int, error) {
+	stdout, stderr, err := RunOVNNbctl("--data=bare", "--no-heading",
+		"--columns=ports", "find", "logical_switch", "name="+logicalSwitch)
+	if err != nil {
+		log.Error(err, "Failed to get the ports of the logical switch", "stderr", stderr)
+		return 0, err
+	}
+	ports := strings.Fields(stdout)
+	if len(ports) == 0 {
+		return 0, nil
+	}
+
+	stdout, stderr, err = RunOVNNbctl("--data=bare", "--no-heading",
+		"--columns=_uuid", "find", "logical_switch_port", "external_ids:pod=true")
+	if err != nil {
+		log.Error(err, "Failed to list the pod ports", "stderr", stderr)
+		return 0, err
+	}
+	podPorts := make(map[string]bool)
+	for _, p := range strings.Fields(stdout) {
+		podPorts[p] = true
+	}
+
+	count := 0
+	for _, p := range ports {
+		if podPorts[p] {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func GetIPAdressForPod(nw string, name string) (string, error) {
 	_, stderr, err := RunOVNNbctl("--data=bare", "--no-heading",
 		"--columns=name", "find", "logical_switch", "name="+nw)
@@ -0,0 +1,431 @@
+package ovn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/kube"
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	"github.com/containernetworking/cni/pkg/invoke"
+	"github.com/containernetworking/cni/pkg/types/current"
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// ipamCNIVersion is the CNI spec version used when calling IPAM plugins
+	ipamCNIVersion = "0.4.0"
+	// defaultIpamPluginDir is the default directory of the IPAM plugin binaries
+	defaultIpamPluginDir = "/opt/cni/bin"
+	// ipamPluginDirEnv overrides the IPAM plugin binary directory
+	ipamPluginDirEnv = "NFN_IPAM_PLUGIN_DIR"
+	// hostLocalType is the IPAM plugin keeping its allocations in local files
+	hostLocalType = "host-local"
+	// defaultHostLocalDataDir is the default directory of the host-local allocations
+	defaultHostLocalDataDir = "/var/lib/cni/networks"
+	// reservedContainerID is the container ID of the host-local allocation files holding the
+	// addresses of the IPReservations, host-local has no excluded addresses
+	reservedContainerID = "nodus-ipreservation"
+	// maxReservedFiles limits the host-local allocation files written for the IPReservations
+	maxReservedFiles = 4096
+)
+
+// hostLocalLock serializes the host-local allocations with the update of the files of the
+// IPReservations, an address requested by a pod is not reserved again meanwhile
+var hostLocalLock sync.Mutex
+
+// ipamCache keeps the delegated IPAM configuration per logical switch, a nil configuration
+// marks a network without delegated IPAM or a switch without Network CR, like the default network
+// and the provider networks. The configuration of a network missing after a restart of the
+// nfn-operator is looked up once from its Network CR, the entries are replaced by the Network
+// reconciles.
+type ipamCache struct {
+	sync.RWMutex
+	conf map[string]*k8sv1alpha1.IpamSpec
+}
+
+// set stores the IPAM configuration of the network, the local state of the plugin is restored
+// from the allocations of the network status the first time the network is seen
+func (c *ipamCache) set(logicalSwitch string, cr *k8sv1alpha1.Network) {
+	c.Lock()
+	defer c.Unlock()
+	var spec *k8sv1alpha1.IpamSpec
+	if cr.Spec.Ipam != nil && cr.Spec.Ipam.Type != "" {
+		spec = cr.Spec.Ipam.DeepCopy()
+	}
+	if prev := c.conf[logicalSwitch]; prev == nil && spec != nil {
+		restoreIpamState(logicalSwitch, spec, cr.Status.IpamAllocations)
+	}
+	c.conf[logicalSwitch] = spec
+}
+
+func (c *ipamCache) remove(logicalSwitch string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.conf, logicalSwitch)
+}
+
+func (c *ipamCache) get(logicalSwitch string) (*k8sv1alpha1.IpamSpec, bool) {
+	c.RLock()
+	spec, ok := c.conf[logicalSwitch]
+	c.RUnlock()
+	if !ok {
+		cr, err := getNetwork(logicalSwitch)
+		if err != nil {
+			log.Error(err, "Failed to get the network of the logical switch", "logicalSwitch", logicalSwitch)
+			return nil, false
+		}
+		if cr == nil {
+			// the switch is not looked up again until a Network of this name is reconciled
+			c.Lock()
+			if _, ok := c.conf[logicalSwitch]; !ok {
+				c.conf[logicalSwitch] = nil
+			}
+			c.Unlock()
+			return nil, false
+		}
+		c.set(logicalSwitch, cr)
+		c.RLock()
+		spec = c.conf[logicalSwitch]
+		c.RUnlock()
+	}
+	return spec, spec != nil
+}
+
+// getNetwork returns the Network CR of the logical switch, nil if there is none
+func getNetwork(logicalSwitch string) (*k8sv1alpha1.Network, error) {
+	k8sv1alpha1Clientset, err := kube.GetKubev1alpha1Config()
+	if err != nil {
+		return nil, err
+	}
+	networks, err := k8sv1alpha1Clientset.Networks("").List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", logicalSwitch).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(networks.Items) == 0 {
+		return nil, nil
+	}
+	return &networks.Items[0], nil
+}
+
+// updateIpamAllocations updates the IPAM allocations of the network status with the update function
+func updateIpamAllocations(logicalSwitch string, update func([]k8sv1alpha1.IpamAllocation) []k8sv1alpha1.IpamAllocation) error {
+	k8sv1alpha1Clientset, err := kube.GetKubev1alpha1Config()
+	if err != nil {
+		return err
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cr, err := getNetwork(logicalSwitch)
+		if err != nil || cr == nil {
+			return err
+		}
+		cr.Status.IpamAllocations = update(cr.Status.IpamAllocations)
+		_, err = k8sv1alpha1Clientset.Networks(cr.Namespace).UpdateStatus(context.TODO(), cr, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// recordIpamAllocation keeps the addresses allocated by the IPAM plugin in the network status
+func recordIpamAllocation(logicalSwitch, containerID, ifName string, ips []string) {
+	err := updateIpamAllocations(logicalSwitch, func(allocations []k8sv1alpha1.IpamAllocation) []k8sv1alpha1.IpamAllocation {
+		allocations = removeIpamAllocation(allocations, containerID, ifName)
+		return append(allocations, k8sv1alpha1.IpamAllocation{ContainerID: containerID, IfName: ifName, IPs: ips})
+	})
+	if err != nil {
+		log.Error(err, "Failed to record the IPAM allocation", "logicalSwitch", logicalSwitch, "containerID", containerID, "ips", ips)
+	}
+}
+
+// forgetIpamAllocation removes the addresses released by the IPAM plugin from the network status
+func forgetIpamAllocation(logicalSwitch, containerID, ifName string) {
+	err := updateIpamAllocations(logicalSwitch, func(allocations []k8sv1alpha1.IpamAllocation) []k8sv1alpha1.IpamAllocation {
+		return removeIpamAllocation(allocations, containerID, ifName)
+	})
+	if err != nil {
+		log.Error(err, "Failed to remove the IPAM allocation", "logicalSwitch", logicalSwitch, "containerID", containerID)
+	}
+}
+
+func removeIpamAllocation(allocations []k8sv1alpha1.IpamAllocation, containerID, ifName string) []k8sv1alpha1.IpamAllocation {
+	kept := []k8sv1alpha1.IpamAllocation{}
+	for _, a := range allocations {
+		if a.ContainerID != containerID || a.IfName != ifName {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// restoreIpamState recreates the allocation files of the host-local plugin from the allocations
+// of the network status, the files are lost when the nfn-operator is rescheduled. The other
+// plugins keep their state outside of the nfn-operator.
+func restoreIpamState(network string, spec *k8sv1alpha1.IpamSpec, allocations []k8sv1alpha1.IpamAllocation) {
+	if spec.Type != hostLocalType || len(allocations) == 0 {
+		return
+	}
+
+	dir := filepath.Join(hostLocalDataDir(spec), network)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Error(err, "Failed to create the host-local directory", "dir", dir)
+		return
+	}
+	for _, a := range allocations {
+		for _, ip := range a.IPs {
+			path := filepath.Join(dir, ip)
+			if _, err := os.Stat(path); err == nil {
+				continue
+			}
+			// the host-local file holds the container ID and the interface name of the allocation
+			if err := ioutil.WriteFile(path, []byte(a.ContainerID+"\r\n"+a.IfName), 0644); err != nil {
+				log.Error(err, "Failed to restore the host-local allocation", "network", network, "ip", ip)
+			}
+		}
+	}
+	log.Info("Restored the host-local allocations", "network", network, "count", len(allocations))
+}
+
+// hostLocalDataDir returns the directory of the host-local allocations of the IPAM configuration
+func hostLocalDataDir(spec *k8sv1alpha1.IpamSpec) string {
+	if spec.Config != "" {
+		conf := struct {
+			DataDir string `json:"dataDir"`
+		}{}
+		if err := json.Unmarshal([]byte(spec.Config), &conf); err == nil && conf.DataDir != "" {
+			return conf.DataDir
+		}
+	}
+	return defaultHostLocalDataDir
+}
+
+// reserveHostLocalAddresses keeps a host-local allocation file for each excluded address so that
+// host-local doesn't give them, the files of the addresses not excluded anymore are removed
+func reserveHostLocalAddresses(network string, spec *k8sv1alpha1.IpamSpec, excluded []reservedRange) error {
+	dir := filepath.Join(hostLocalDataDir(spec), network)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	reserved := make(map[string]bool)
+	for i := int64(0); i < maxReservedFiles; i++ {
+		ip, ok := reservedAddress(excluded, i)
+		if !ok {
+			break
+		}
+		reserved[ip.String()] = true
+	}
+	if len(reserved) == maxReservedFiles {
+		log.Info("Too many reserved addresses for host-local, the last ones are not excluded", "network", network, "max", maxReservedFiles)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if reserved[f.Name()] {
+			delete(reserved, f.Name())
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err == nil && strings.HasPrefix(string(data), reservedContainerID+"\r\n") {
+			if err := os.Remove(filepath.Join(dir, f.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	for ip := range reserved {
+		// an address already allocated to a pod keeps its file
+		if err := ioutil.WriteFile(filepath.Join(dir, ip), []byte(reservedContainerID+"\r\nreserved"), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ipamNetConf builds the network configuration handed over to the IPAM plugin. The requested
+// addresses are passed with the "ips" runtime config and the excluded addresses are appended to the
+// "exclude" subnets of the IPAM configuration
+func ipamNetConf(network string, spec *k8sv1alpha1.IpamSpec, requested []string, excluded []reservedRange) ([]byte, error) {
+	ipam := map[string]interface{}{}
+	if spec.Config != "" {
+		if err := json.Unmarshal([]byte(spec.Config), &ipam); err != nil {
+			return nil, fmt.Errorf("invalid ipam config for network %s: %v", network, err)
+		}
+	}
+	ipam["type"] = spec.Type
+	if len(excluded) > 0 {
+		exclude, _ := ipam["exclude"].([]interface{})
+		for _, r := range excluded {
+			for _, cidr := range rangeCIDRs(r) {
+				exclude = append(exclude, cidr)
+			}
+		}
+		ipam["exclude"] = exclude
+	}
+
+	conf := map[string]interface{}{
+		"cniVersion": ipamCNIVersion,
+		"name":       network,
+		"ipam":       ipam,
+	}
+	if len(requested) > 0 {
+		conf["runtimeConfig"] = map[string]interface{}{"ips": requested}
+	}
+	return json.Marshal(conf)
+}
+
+func ipamArgs(command, containerID, ifName string) *invoke.Args {
+	pluginDir := os.Getenv(ipamPluginDirEnv)
+	if pluginDir == "" {
+		pluginDir = defaultIpamPluginDir
+	}
+	return &invoke.Args{
+		Command:     command,
+		ContainerID: containerID,
+		// IPAM plugins don't enter the namespace, but the CNI spec requires it
+		NetNS:  filepath.Join("/var/run/netns", containerID),
+		IfName: ifName,
+		Path:   pluginDir,
+	}
+}
+
+func ipamPluginPath(args *invoke.Args, spec *k8sv1alpha1.IpamSpec) (string, error) {
+	return invoke.FindInPath(spec.Type, strings.Split(args.Path, string(os.PathListSeparator)))
+}
+
+// ipamAllocate calls the delegated IPAM plugin of the network and returns the allocated IP addresses.
+// The plugin is asked for the requested addresses and doesn't give the excluded addresses
+func ipamAllocate(network string, spec *k8sv1alpha1.IpamSpec, containerID, ifName string, requested []string, excluded []reservedRange) ([]string, error) {
+	netConf, err := ipamNetConf(network, spec, requested, excluded)
+	if err != nil {
+		return nil, err
+	}
+	args := ipamArgs("ADD", containerID, ifName)
+	pluginPath, err := ipamPluginPath(args, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Type == hostLocalType {
+		hostLocalLock.Lock()
+		defer hostLocalLock.Unlock()
+		if err := reserveHostLocalAddresses(network, spec, excluded); err != nil {
+			return nil, err
+		}
+	}
+	r, err := invoke.ExecPluginWithResult(context.TODO(), pluginPath, netConf, args, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := current.NewResultFromResult(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.IPs) == 0 {
+		return nil, fmt.Errorf("IPAM plugin %s returned no IP address for network %s", spec.Type, network)
+	}
+
+	ips := []string{}
+	for _, ip := range result.IPs {
+		ips = append(ips, ip.Address.IP.String())
+	}
+	for _, want := range requested {
+		if !containsAddress(ips, want) {
+			// the plugin doesn't support the requested addresses
+			if err := invoke.ExecPluginWithoutResult(context.TODO(), pluginPath, netConf, ipamArgs("DEL", containerID, ifName), nil); err != nil {
+				log.Error(err, "Failed to release the IPAM addresses", "network", network, "type", spec.Type)
+			}
+			return nil, fmt.Errorf("IPAM plugin %s returned %v instead of the requested address %s for network %s", spec.Type, ips, want, network)
+		}
+	}
+	return ips, nil
+}
+
+// containsAddress checks if the address is one of the addresses
+func containsAddress(addresses []string, address string) bool {
+	ip := parseIP(address)
+	for _, a := range addresses {
+		if ip != nil && ip.Equal(parseIP(a)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ipamRelease calls the delegated IPAM plugin of the network to release the addresses of the container
+func ipamRelease(network string, spec *k8sv1alpha1.IpamSpec, containerID, ifName string) error {
+	netConf, err := ipamNetConf(network, spec, nil, nil)
+	if err != nil {
+		return err
+	}
+	args := ipamArgs("DEL", containerID, ifName)
+	pluginPath, err := ipamPluginPath(args, spec)
+	if err != nil {
+		return err
+	}
+
+	return invoke.ExecPluginWithoutResult(context.TODO(), pluginPath, netConf, args, nil)
+}
+
+// ipamContainerID returns the identifier the IPAM plugins use to track the pod allocation
+func ipamContainerID(pod *kapi.Pod) string {
+	if pod.UID != "" {
+		return string(pod.UID)
+	}
+	return fmt.Sprintf("%s_%s", pod.Namespace, pod.Name)
+}
+
+// ipamIfName returns the interface name reported to the IPAM plugin
+func ipamIfName(portName, namespace, name string) string {
+	ifName := strings.TrimPrefix(portName, fmt.Sprintf("%s_%s", namespace, name))
+	ifName = strings.TrimPrefix(ifName, "_")
+	if ifName == "" {
+		return "eth0"
+	}
+	return ifName
+}
+
+// releaseIpamAddresses releases the delegated IPAM addresses of the logical port, if any
+func (oc *Controller) releaseIpamAddresses(portName string) {
+	stdout, stderr, err := RunOVNNbctl("--data=bare", "--no-heading",
+		"--columns=external_ids", "find", "logical_switch_port", "name="+portName)
+	if err != nil {
+		log.Error(err, "Failed to get the external ids of the logical port", "portName", portName, "stderr", stderr)
+		return
+	}
+	externalIDs := map[string]string{}
+	for _, field := range strings.Fields(stdout) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) == 2 {
+			externalIDs[kv[0]] = kv[1]
+		}
+	}
+	containerID, ok := externalIDs["ipam_id"]
+	if !ok {
+		return
+	}
+	logicalSwitch := externalIDs["logical_switch"]
+	ifName := externalIDs["ipam_ifname"]
+
+	spec, ok := oc.ipam.get(logicalSwitch)
+	if !ok {
+		log.Info("No IPAM configuration found for the network, skipping address release", "logicalSwitch", logicalSwitch, "portName", portName)
+		return
+	}
+	if err := ipamRelease(logicalSwitch, spec, containerID, ifName); err != nil {
+		log.Error(err, "Failed to release the IPAM addresses", "portName", portName, "type", spec.Type)
+		return
+	}
+	forgetIpamAllocation(logicalSwitch, containerID, ifName)
+}
@@ -0,0 +1,81 @@
+package ovn
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Test delegated IPAM", func() {
+	It("passes the requested and excluded addresses to the plugin", func() {
+		excluded, err := reservationRanges([]string{"10.0.0.8..10.0.0.11"})
+		Expect(err).NotTo(HaveOccurred())
+		spec := &k8sv1alpha1.IpamSpec{Type: "whereabouts", Config: `{"range":"10.0.0.0/24","exclude":["10.0.0.0/29"]}`}
+		b, err := ipamNetConf("net", spec, []string{"10.0.0.20"}, excluded)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b).To(MatchJSON(`{
+			"cniVersion": "0.4.0",
+			"name": "net",
+			"ipam": {"type": "whereabouts", "range": "10.0.0.0/24", "exclude": ["10.0.0.0/29", "10.0.0.8/30"]},
+			"runtimeConfig": {"ips": ["10.0.0.20"]}
+		}`))
+
+		b, err = ipamNetConf("net", spec, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		conf := map[string]interface{}{}
+		Expect(json.Unmarshal(b, &conf)).To(Succeed())
+		Expect(conf).NotTo(HaveKey("runtimeConfig"))
+		Expect(conf["ipam"]).To(HaveKeyWithValue("exclude", []interface{}{"10.0.0.0/29"}))
+	})
+
+	It("keeps host-local allocation files for the reserved addresses", func() {
+		dataDir, err := ioutil.TempDir("", "host-local")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dataDir)
+		spec := &k8sv1alpha1.IpamSpec{Type: hostLocalType, Config: fmt.Sprintf(`{"dataDir":%q}`, dataDir)}
+		dir := filepath.Join(dataDir, "net")
+		Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "10.0.0.11"), []byte("pod\r\neth0"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "10.0.0.30"), []byte(reservedContainerID+"\r\nreserved"), 0644)).To(Succeed())
+
+		excluded, err := reservationRanges([]string{"10.0.0.10..10.0.0.12"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reserveHostLocalAddresses("net", spec, excluded)).To(Succeed())
+
+		files, err := ioutil.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Name())
+		}
+		// the file of a reservation removed is deleted, the allocation of a pod is kept
+		Expect(names).To(ConsistOf("10.0.0.10", "10.0.0.11", "10.0.0.12"))
+		data, err := ioutil.ReadFile(filepath.Join(dir, "10.0.0.11"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("pod\r\neth0"))
+	})
+
+	It("caches the switches without delegated IPAM until their network is reconciled", func() {
+		c := &ipamCache{conf: map[string]*k8sv1alpha1.IpamSpec{"ovn-default": nil}}
+		_, ok := c.get("ovn-default")
+		Expect(ok).To(BeFalse())
+
+		cr := &k8sv1alpha1.Network{ObjectMeta: metav1.ObjectMeta{Name: "ovn-default"}}
+		cr.Spec.Ipam = &k8sv1alpha1.IpamSpec{Type: "whereabouts"}
+		c.set("ovn-default", cr)
+		spec, ok := c.get("ovn-default")
+		Expect(ok).To(BeTrue())
+		Expect(spec.Type).To(Equal("whereabouts"))
+
+		c.remove("ovn-default")
+		Expect(c.conf).NotTo(HaveKey("ovn-default"))
+	})
+})
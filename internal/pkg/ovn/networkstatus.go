@@ -0,0 +1,115 @@
+package ovn
+
+import (
+	"encoding/json"
+	"strings"
+
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+)
+
+const (
+	// NetworkStatusAnnotationTag is the network status annotation defined by the
+	// Kubernetes Network Plumbing Working Group
+	NetworkStatusAnnotationTag = "k8s.v1.cni.cncf.io/network-status"
+	// primaryInterfaceName is the name of the pod interface attached to the default network
+	primaryInterfaceName = "eth0"
+)
+
+// PodInterface is an entry of the ovnInterfaces pod annotation
+type PodInterface struct {
+	IPAddress      []string `json:"ip_address"`
+	MacAddress     string   `json:"mac_address"`
+	GatewayIP      []string `json:"gateway_ip"`
+	DefaultGateway string   `json:"defaultGateway,omitempty"`
+	Interface      string   `json:"interface"`
+	Network        string   `json:"network,omitempty"`
+	interfaceTuning
+}
+
+// DNS is the DNS configuration of a network-status entry
+type DNS struct {
+	Nameservers []string `json:"nameservers,omitempty"`
+	Domain      string   `json:"domain,omitempty"`
+	Search      []string `json:"search,omitempty"`
+	Options     []string `json:"options,omitempty"`
+}
+
+// NetworkStatus is an entry of the network-status pod annotation
+type NetworkStatus struct {
+	Name      string   `json:"name"`
+	Interface string   `json:"interface,omitempty"`
+	IPs       []string `json:"ips,omitempty"`
+	Mac       string   `json:"mac,omitempty"`
+	Default   bool     `json:"default,omitempty"`
+	DNS       DNS      `json:"dns"`
+}
+
+// NetworkDNS returns the DNS of a network-status entry of the network with the DNS spec
+func NetworkDNS(spec k8sv1alpha1.DnsSpec) DNS {
+	return DNS{
+		Nameservers: spec.Nameservers,
+		Domain:      spec.Domain,
+		Search:      spec.Search,
+		Options:     spec.Options,
+	}
+}
+
+func encodePodInterfaces(podInterfaces []*PodInterface) (string, error) {
+	b, err := json.Marshal(podInterfaces)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// DecodePodInterfaces parses the ovnInterfaces pod annotation
+func DecodePodInterfaces(annotation string) ([]*PodInterface, error) {
+	var podInterfaces []*PodInterface
+	// annotations written by older releases have escaped quotes
+	if err := json.Unmarshal([]byte(strings.ReplaceAll(annotation, "\\", "")), &podInterfaces); err != nil {
+		return nil, err
+	}
+	return podInterfaces, nil
+}
+
+// GetNetworkStatus returns the network-status annotation of the pod interfaces, the DNS of an
+// entry is the DNS of its network in dns
+func GetNetworkStatus(podInterfaces []*PodInterface, dns map[string]DNS) (string, error) {
+	// The CNI sets the default route on the first interface requesting it, or on the
+	// interface of the default network otherwise
+	defaultIndex := -1
+	for i, podInterface := range podInterfaces {
+		if podInterface.Interface != "*" && podInterface.DefaultGateway == "true" {
+			defaultIndex = i
+			break
+		}
+	}
+
+	status := []NetworkStatus{}
+	for i, podInterface := range podInterfaces {
+		entry := NetworkStatus{
+			Name:      podInterface.Network,
+			Interface: podInterface.Interface,
+			Mac:       podInterface.MacAddress,
+			Default:   i == defaultIndex,
+		}
+		if podInterface.Interface == "*" {
+			entry.Interface = primaryInterfaceName
+			entry.Default = defaultIndex == -1
+		}
+		if entry.Name == "" {
+			entry.Name = entry.Interface
+		}
+		entry.DNS = dns[podInterface.Network]
+		for _, address := range podInterface.IPAddress {
+			entry.IPs = append(entry.IPs, strings.Split(address, "/")[0])
+		}
+		status = append(status, entry)
+	}
+
+	b, err := json.Marshal(status)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
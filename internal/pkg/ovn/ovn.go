@@ -1,6 +1,7 @@
 package ovn
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"os"
@@ -14,11 +15,16 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	kexec "k8s.io/utils/exec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type Controller struct {
 	gatewayCache map[string]string
+	ipam         *ipamCache
+	tuning       *tuningCache
+	client       client.Reader
 }
 
 type OVNNetworkConf struct {
@@ -84,6 +90,7 @@ type NetInterface struct {
 	IPAddress      string
 	MacAddress     string
 	GWIPaddress    string
+	NetworkPool    string
 }
 
 var ovnCtl *Controller
@@ -110,10 +117,18 @@ func NewOvnController(exec kexec.Interface) (*Controller, error) {
 
 	ovnCtl = &Controller{
 		gatewayCache: make(map[string]string),
+		ipam:         &ipamCache{conf: make(map[string]*k8sv1alpha1.IpamSpec)},
+		tuning:       &tuningCache{conf: make(map[string]*interfaceTuning)},
 	}
 	return ovnCtl, nil
 }
 
+// SetClient sets the client the controller reads the IPReservations and the pods with, the cached
+// client of the manager
+func (oc *Controller) SetClient(c client.Reader) {
+	oc.client = c
+}
+
 // GetOvnController returns OVN controller for creating logical networks
 func GetOvnController() (*Controller, error) {
 	if ovnCtl != nil {
@@ -150,10 +165,19 @@ func (oc *Controller) AddLogicalPorts(pod *kapi.Pod, ovnNetObjs []map[string]int
 		}
 	}
 
-	var ovnString, outStr string
+	var podInterfaces []*PodInterface
 	var defaultInterface bool
+	var portNames []string
+
+	defer func() {
+		if value == "" {
+			// Delete the ports added before the failure, with the one which failed
+			for _, portName := range portNames {
+				oc.deleteLogicalPort(portName)
+			}
+		}
+	}()
 
-	ovnString = "["
 	var ns NetInterface
 	for _, net := range ovnNetObjs {
 		err := mapstructure.Decode(net, &ns)
@@ -165,7 +189,7 @@ func (oc *Controller) AddLogicalPorts(pod *kapi.Pod, ovnNetObjs []map[string]int
 		if !oc.FindLogicalSwitch(ns.Name) && IsExtraInterfaces == false {
 			log.Info("Logical Switch not found, create the network")
 			th := "pod"
-			err := network.CreateNetworkFromPool(ns.Name, th)
+			err := network.CreateNetworkFromPool(ns.Name, ns.NetworkPool, pod.Namespace, th)
 			if err != nil {
 				log.Error(err, "Error in creating networkpool or network")
 				return
@@ -193,36 +217,43 @@ func (oc *Controller) AddLogicalPorts(pod *kapi.Pod, ovnNetObjs []map[string]int
 			portName = fmt.Sprintf("%s_%s", pod.Namespace, pod.Name)
 			ns.Interface = "*"
 		}
-		outStr = oc.addLogicalPortWithSwitch(pod, ns.Name, ns.IPAddress, ns.MacAddress, ns.GWIPaddress, portName)
-		if outStr == "" {
+		portNames = append(portNames, portName)
+		podInterface := oc.addLogicalPortWithSwitch(pod, ns.Name, ns.IPAddress, ns.MacAddress, ns.GWIPaddress, portName)
+		if podInterface == nil {
+			return
+		}
+		// The idle networks are marked before their ports are counted, the mark is checked after
+		// the port is added so that either the port is counted or the pod sees the mark
+		if oc.networkDraining(ns.Name) {
+			log.Info("Network is deleted for being idle, the pod is attached once it is created again", "network", ns.Name, "pod", pod.Name)
 			return
 		}
-		last := len(outStr) - 1
-		tmpString := outStr[:last]
-		tmpString += "," + "\\\"defaultGateway\\\":" + "\\\"" + ns.DefaultGateway + "\\\""
-		tmpString += "," + "\\\"interface\\\":" + "\\\"" + ns.Interface + "\\\"}"
-		ovnString += tmpString
-		ovnString += ","
+		podInterface.DefaultGateway = ns.DefaultGateway
+		podInterface.Interface = ns.Interface
+		podInterface.Network = ns.Name
+		if tuning, ok := oc.tuning.get(ns.Name); ok {
+			podInterface.interfaceTuning = *tuning
+		}
+		podInterfaces = append(podInterfaces, podInterface)
 	}
-	var last int
 	if defaultInterface == false && !IsExtraInterfaces {
 		// Add Default interface
 		portName := fmt.Sprintf("%s_%s", pod.Namespace, pod.Name)
-		outStr = oc.addLogicalPortWithSwitch(pod, Ovn4nfvDefaultNw, "", "", "", portName)
-		if outStr == "" {
+		portNames = append(portNames, portName)
+		podInterface := oc.addLogicalPortWithSwitch(pod, Ovn4nfvDefaultNw, "", "", "", portName)
+		if podInterface == nil {
 			return
 		}
-		last := len(outStr) - 1
-		tmpString := outStr[:last]
-		tmpString += "," + "\\\"interface\\\":" + "\\\"" + "*" + "\\\"}"
-		ovnString += tmpString
-		ovnString += ","
+		podInterface.Interface = "*"
+		podInterface.Network = Ovn4nfvDefaultNw
+		podInterfaces = append(podInterfaces, podInterface)
+	}
+	value, err := encodePodInterfaces(podInterfaces)
+	if err != nil {
+		log.Error(err, "Failed to encode the pod interfaces", "pod", pod.Name)
+		return
 	}
-	last = len(ovnString) - 1
-	ovnString = ovnString[:last]
-	ovnString += "]"
 	key = Ovn4nfvAnnotationTag
-	value = ovnString
 	return key, value
 }
 
@@ -244,6 +275,7 @@ func (oc *Controller) DeleteLogicalPorts(name, namespace string) {
 		if strings.Contains(existingPort, logicalPort) {
 			// found, delete this logical port
 			log.Info("Deleting", "Port", existingPort)
+			oc.releaseIpamAddresses(existingPort)
 			stdout, stderr, err := RunOVNNbctl("--if-exists", "lsp-del",
 				existingPort)
 			if err != nil {
@@ -254,11 +286,48 @@ func (oc *Controller) DeleteLogicalPorts(name, namespace string) {
 	return
 }
 
+// networkDraining checks if the network is created from a pool and about to be deleted for being
+// idle. The network is read from the cache, the idle networks are deleted after the mark had time
+// to reach it. A network that can't be read is not draining
+func (oc *Controller) networkDraining(name string) bool {
+	if oc.client == nil {
+		return false
+	}
+	cr := &k8sv1alpha1.Network{}
+	if err := oc.client.Get(context.TODO(), network.PoolNetworkKey(name), cr); err != nil {
+		if !errors.IsNotFound(err) {
+			log.Error(err, "Failed to check if the network is draining", "network", name)
+		}
+		return false
+	}
+	return network.NetworkDraining(cr)
+}
+
+// DeleteLogicalPort deletes the OVN port of a single pod interface
+func (oc *Controller) DeleteLogicalPort(name, namespace, ifName string) error {
+	return oc.deleteLogicalPort(fmt.Sprintf("%s_%s_%s", namespace, name, ifName))
+}
+
+// deleteLogicalPort deletes the OVN port and releases its delegated IPAM addresses
+func (oc *Controller) deleteLogicalPort(portName string) error {
+	log.Info("Deleting", "Port", portName)
+	oc.releaseIpamAddresses(portName)
+	stdout, stderr, err := RunOVNNbctl("--if-exists", "lsp-del", portName)
+	if err != nil {
+		log.Error(err, "Error in deleting pod's logical port ", "stdout", stdout, "stderr", stderr)
+		return err
+	}
+	return nil
+}
+
 // CreateNetwork in OVN controller
 func (oc *Controller) CreateNetwork(cr *k8sv1alpha1.Network) error {
 	// Currently only these fields are supported
 	name := cr.Name
 
+	oc.ipam.set(getIPv4LogicalSwitchName(name), cr)
+	oc.tuning.set(getIPv4LogicalSwitchName(name), newInterfaceTuning(cr.Spec.Mtu, cr.Spec.TxQueueLen, cr.Spec.Offload))
+
 	if len(cr.Spec.Ipv4Subnets) > 0 {
 		subnet := cr.Spec.Ipv4Subnets[0].Subnet
 		gatewayIP := cr.Spec.Ipv4Subnets[0].Gateway
@@ -329,6 +398,9 @@ func (oc *Controller) DeleteNetwork(cr *k8sv1alpha1.Network) error {
 
 	name := cr.Name
 
+	oc.ipam.remove(getIPv4LogicalSwitchName(name))
+	oc.tuning.set(getIPv4LogicalSwitchName(name), nil)
+
 	err := deleteLogicalRouterPort(getIPv4LogicalRouterPortName(name))
 	if err != nil {
 		return err
@@ -389,6 +461,8 @@ func (oc *Controller) CreateProviderNetwork(cr *k8sv1alpha1.ProviderNetwork) err
 	// Currently only these fields are supported
 	name := cr.Name
 
+	oc.tuning.set(getIPv4LogicalSwitchName(name), newProviderInterfaceTuning(cr))
+
 	if len(cr.Spec.Ipv4Subnets) > 0 {
 		subnet := cr.Spec.Ipv4Subnets[0].Subnet
 		gatewayIP := cr.Spec.Ipv4Subnets[0].Gateway
@@ -423,6 +497,8 @@ func (oc *Controller) DeleteProviderNetwork(cr *k8sv1alpha1.ProviderNetwork) err
 
 	name := cr.Name
 
+	oc.tuning.set(getIPv4LogicalSwitchName(name), nil)
+
 	err := deleteLogicalSwitch(getIPv4LogicalSwitchName(name))
 	if err != nil {
 		return err
@@ -636,7 +712,7 @@ func (oc *Controller) getNodeLogicalPortIPAddr(pod *kapi.Pod) (ipAddr, ipv6Addr
 	return ipAddr, ipv6Addr, nil
 }
 
-func (oc *Controller) addLogicalPortWithSwitch(pod *kapi.Pod, logicalSwitch, ipAddress, macAddress, gwipAddress, portName string) (annotation string) {
+func (oc *Controller) addLogicalPortWithSwitch(pod *kapi.Pod, logicalSwitch, ipAddress, macAddress, gwipAddress, portName string) (podInterface *PodInterface) {
 	var out, stderr string
 	var err error
 	var isStaticIP bool
@@ -644,8 +720,74 @@ func (oc *Controller) addLogicalPortWithSwitch(pod *kapi.Pod, logicalSwitch, ipA
 		return
 	}
 
+	var ipamSpec *k8sv1alpha1.IpamSpec
+	var ipamID, ipamIfname string
+	var portAdded bool
+	defer func() {
+		if podInterface != nil {
+			return
+		}
+		// on failure the port is removed and the delegated IPAM addresses are released
+		if portAdded {
+			oc.deleteLogicalPort(portName)
+		} else if ipamID != "" {
+			if err := ipamRelease(logicalSwitch, ipamSpec, ipamID, ipamIfname); err != nil {
+				log.Error(err, "Failed to release the IPAM addresses", "portName", portName, "type", ipamSpec.Type)
+			}
+		}
+	}()
+
 	log.V(1).Info("Creating logical port for on switch", "portName", portName, "logicalSwitch", logicalSwitch)
 
+	externalIds := []string{
+		"external-ids:namespace=" + pod.Namespace,
+		"external-ids:logical_switch=" + logicalSwitch,
+		"external-ids:pod=true",
+	}
+
+	// the static address of the nfn-network annotation is not allocated by the delegated IPAM,
+	// the address reserved to the pod is requested from it
+	staticAddress := ipAddress != ""
+	if !staticAddress {
+		if ipAddress, err = oc.podReservedAddress(pod, logicalSwitch); err != nil {
+			log.Error(err, "Failed to get the reserved address", "portName", portName)
+			return
+		}
+	} else if err = oc.checkStaticAddress(pod, logicalSwitch, ipAddress); err != nil {
+		log.Error(err, "Rejecting the address of the nfn-network annotation", "portName", portName)
+		return
+	}
+	if ipAddress != "" {
+		if err = checkAddressInUse(logicalSwitch, portName, ipAddress); err != nil {
+			log.Error(err, "Rejecting the address of the port", "portName", portName)
+			return
+		}
+	}
+
+	if !staticAddress {
+		var ok bool
+		if ipamSpec, ok = oc.ipam.get(logicalSwitch); ok {
+			requested := strings.Fields(ipAddress)
+			excluded, err := oc.ipamExclusions(logicalSwitch, requested)
+			if err != nil {
+				log.Error(err, "Failed to get the reserved addresses", "portName", portName)
+				return
+			}
+			ipamID = ipamContainerID(pod)
+			ipamIfname = ipamIfName(portName, pod.Namespace, pod.Name)
+			ips, err := ipamAllocate(logicalSwitch, ipamSpec, ipamID, ipamIfname, requested, excluded)
+			if err != nil {
+				log.Error(err, "Failed to allocate IP address from the delegated IPAM", "portName", portName, "type", ipamSpec.Type)
+				return
+			}
+			log.V(1).Info("Allocated IP address from the delegated IPAM", "portName", portName, "type", ipamSpec.Type, "ips", ips)
+			ipAddress = strings.Join(ips, " ")
+			externalIds = append(externalIds,
+				"external-ids:ipam_id="+ipamID,
+				"external-ids:ipam_ifname="+ipamIfname)
+		}
+	}
+
 	if ipAddress != "" && macAddress != "" {
 		isStaticIP = true
 	}
@@ -655,31 +797,32 @@ func (oc *Controller) addLogicalPortWithSwitch(pod *kapi.Pod, logicalSwitch, ipA
 	}
 
 	if isStaticIP {
-		out, stderr, err = RunOVNNbctl("--may-exist", "lsp-add",
+		args := []string{"--may-exist", "lsp-add",
 			logicalSwitch, portName, "--", "lsp-set-addresses", portName,
 			fmt.Sprintf("%s %s", macAddress, ipAddress), "--", "--if-exists",
 			"clear", "logical_switch_port", portName, "dynamic_addresses", "--", "set",
-			"logical_switch_port", portName,
-			"external-ids:namespace="+pod.Namespace,
-			"external-ids:logical_switch="+logicalSwitch,
-			"external-ids:pod=true")
+			"logical_switch_port", portName}
+		out, stderr, err = RunOVNNbctl(append(args, externalIds...)...)
 		if err != nil {
 			log.Error(err, "Failed to add logical port to switch", "out", out, "stderr", stderr)
 			return
 		}
+		portAdded = true
+		if ipamID != "" {
+			recordIpamAllocation(logicalSwitch, ipamID, ipamIfname, strings.Fields(ipAddress))
+		}
 	} else {
-		out, stderr, err = RunOVNNbctl("--wait=sb", "--",
+		args := []string{"--wait=sb", "--",
 			"--may-exist", "lsp-add", logicalSwitch, portName,
 			"--", "lsp-set-addresses",
 			portName, "dynamic", "--", "set",
-			"logical_switch_port", portName,
-			"external-ids:namespace="+pod.Namespace,
-			"external-ids:logical_switch="+logicalSwitch,
-			"external-ids:pod=true")
+			"logical_switch_port", portName}
+		out, stderr, err = RunOVNNbctl(append(args, externalIds...)...)
 		if err != nil {
 			log.Error(err, "Error while creating logical port %s ", "portName", portName, "stdout", out, "stderr", stderr)
 			return
 		}
+		portAdded = true
 	}
 
 	count := 30
@@ -753,27 +896,25 @@ func (oc *Controller) addLogicalPortWithSwitch(pod *kapi.Pod, logicalSwitch, ipA
 		ipv6Addr = fmt.Sprintf("%s/%s", addresses[ipv6Index], gwMasks[ipv6GWIndex])
 	}
 
-	addrStr := composeAddresses(ipAddr, ipv6Addr)
-	gatewayStr := composeAddresses(gatewayIP, gatewayIPv6)
-
-	annotation = fmt.Sprintf(`{\"ip_address\":[%s], \"mac_address\":\"%s\", \"gateway_ip\": [%s]}`, addrStr, macAddr, gatewayStr)
+	podInterface = &PodInterface{
+		IPAddress:  composeAddresses(ipAddr, ipv6Addr),
+		MacAddress: macAddr,
+		GatewayIP:  composeAddresses(gatewayIP, gatewayIPv6),
+	}
 
-	return annotation
+	return podInterface
 }
 
-func composeAddresses(ipv4, ipv6 string) string {
-	addrStr := ""
+func composeAddresses(ipv4, ipv6 string) []string {
+	addresses := []string{}
 	if ipv4 != "" {
-		addrStr += "\\\"" + ipv4 + "\\\""
-	}
-	if addrStr != "" && ipv6 != "" {
-		addrStr += ","
+		addresses = append(addresses, ipv4)
 	}
 	if ipv6 != "" {
-		addrStr += "\\\"" + ipv6 + "\\\""
+		addresses = append(addresses, ipv6)
 	}
 
-	return addrStr
+	return addresses
 }
 
 func GetSFCNetworkIfname() (f func() string) {
@@ -0,0 +1,443 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/kube"
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reservedRange is a range of reserved addresses
+type reservedRange struct {
+	ipRange
+	v4 bool
+}
+
+// reservationRanges parses the addresses of an IPReservation, a single address or a range
+// "first..last" per entry
+func reservationRanges(ips []string) ([]reservedRange, error) {
+	var ranges []reservedRange
+	for _, entry := range ips {
+		bounds := strings.SplitN(strings.TrimSpace(entry), "..", 2)
+		first := net.ParseIP(bounds[0])
+		last := first
+		if len(bounds) == 2 {
+			last = net.ParseIP(bounds[1])
+		}
+		if first == nil || last == nil || (first.To4() == nil) != (last.To4() == nil) {
+			return nil, fmt.Errorf("invalid reserved address %q", entry)
+		}
+		r := reservedRange{ipRange{ipToInt(first), ipToInt(last)}, first.To4() != nil}
+		if r.first.Cmp(r.last) > 0 {
+			return nil, fmt.Errorf("invalid reserved address range %q", entry)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// ReservedIpsOverlap validates the addresses of two IPReservations and checks if they have an
+// address in common
+func ReservedIpsOverlap(a, b []string) (bool, error) {
+	ra, err := reservationRanges(a)
+	if err != nil {
+		return false, err
+	}
+	rb, err := reservationRanges(b)
+	if err != nil {
+		return false, err
+	}
+	return rangesOverlap(ra, rb), nil
+}
+
+// rangesOverlap checks if two lists of reserved addresses have an address in common
+func rangesOverlap(a, b []reservedRange) bool {
+	for _, ra := range a {
+		for _, rb := range b {
+			if ra.v4 == rb.v4 && ra.first.Cmp(rb.last) <= 0 && rb.first.Cmp(ra.last) <= 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reservedAddress returns the address with the index in the reserved ranges
+func reservedAddress(ranges []reservedRange, index int64) (net.IP, bool) {
+	i := big.NewInt(index)
+	for _, r := range ranges {
+		size := new(big.Int).Add(new(big.Int).Sub(r.last, r.first), big.NewInt(1))
+		if i.Cmp(size) < 0 {
+			return intToAddr(new(big.Int).Add(r.first, i), r.v4), true
+		}
+		i.Sub(i, size)
+	}
+	return nil, false
+}
+
+// reservedContains checks if the address is one of the reserved addresses
+func reservedContains(ranges []reservedRange, ip net.IP) bool {
+	for _, r := range ranges {
+		if r.v4 == (ip.To4() != nil) && inRanges([]ipRange{r.ipRange}, ipToInt(ip)) {
+			return true
+		}
+	}
+	return false
+}
+
+// intToAddr returns the IPv4 or IPv6 address of the integer
+func intToAddr(i *big.Int, v4 bool) net.IP {
+	size := net.IPv6len
+	if v4 {
+		size = net.IPv4len
+	}
+	b := i.Bytes()
+	ip := make(net.IP, size)
+	copy(ip[size-len(b):], b)
+	return ip
+}
+
+// withoutAddress returns the ranges without the address, a range holding the address is split
+func withoutAddress(ranges []reservedRange, ip net.IP) []reservedRange {
+	v4 := ip.To4() != nil
+	i := ipToInt(ip)
+	var kept []reservedRange
+	for _, r := range ranges {
+		if r.v4 != v4 || !inRanges([]ipRange{r.ipRange}, i) {
+			kept = append(kept, r)
+			continue
+		}
+		if r.first.Cmp(i) < 0 {
+			kept = append(kept, reservedRange{ipRange{r.first, new(big.Int).Sub(i, big.NewInt(1))}, v4})
+		}
+		if i.Cmp(r.last) < 0 {
+			kept = append(kept, reservedRange{ipRange{new(big.Int).Add(i, big.NewInt(1)), r.last}, v4})
+		}
+	}
+	return kept
+}
+
+// rangeCIDRs returns the smallest list of CIDRs covering the range
+func rangeCIDRs(r reservedRange) []string {
+	bits := 8 * net.IPv6len
+	if r.v4 {
+		bits = 8 * net.IPv4len
+	}
+	var cidrs []string
+	first := new(big.Int).Set(r.first)
+	for first.Cmp(r.last) <= 0 {
+		// the largest block aligned on first and ending before last
+		size := 0
+		for size < bits && first.Bit(size) == 0 {
+			end := new(big.Int).Add(first, new(big.Int).Lsh(big.NewInt(1), uint(size+1)))
+			if end.Sub(end, big.NewInt(1)).Cmp(r.last) > 0 {
+				break
+			}
+			size++
+		}
+		cidrs = append(cidrs, fmt.Sprintf("%s/%d", intToAddr(first, r.v4), bits-size))
+		first.Add(first, new(big.Int).Lsh(big.NewInt(1), uint(size)))
+	}
+	return cidrs
+}
+
+// reservationIndex returns the index of the address reserved to the pod by a reservation of the
+// pod name or the StatefulSet of the pod. ok is false when the reservation is not for the pod
+func reservationIndex(r *k8sv1alpha1.IPReservation, pod *kapi.Pod) (index int64, selected, ok bool) {
+	if r.Namespace != pod.Namespace {
+		return 0, false, false
+	}
+	switch {
+	case r.Spec.PodName != "":
+		return 0, false, r.Spec.PodName == pod.Name
+	case r.Spec.StatefulSet != "":
+		for _, owner := range pod.OwnerReferences {
+			if owner.Kind != "StatefulSet" || owner.Name != r.Spec.StatefulSet {
+				continue
+			}
+			ordinal, err := strconv.ParseInt(strings.TrimPrefix(pod.Name, owner.Name+"-"), 10, 64)
+			if err != nil || ordinal < 0 {
+				return 0, false, false
+			}
+			return ordinal, false, true
+		}
+	case r.Spec.Selector != nil:
+		selector, err := metav1.LabelSelectorAsSelector(r.Spec.Selector)
+		if err != nil {
+			return 0, false, false
+		}
+		return 0, true, selector.Matches(labels.Set(pod.Labels))
+	}
+	return 0, false, false
+}
+
+// allocateReservedAddress picks the address of the reservation for the pod and records it in the
+// allocations of the reservation. A pod selected by the selector keeps its allocated address, or gets
+// the first address not allocated to an existing pod
+func allocateReservedAddress(r *k8sv1alpha1.IPReservation, pod *kapi.Pod, podExists func(name string) bool) (string, error) {
+	index, selected, ok := reservationIndex(r, pod)
+	if !ok {
+		return "", nil
+	}
+	ranges, err := reservationRanges(r.Spec.Ips)
+	if err != nil {
+		return "", err
+	}
+
+	var ip string
+	var allocations []k8sv1alpha1.IPAllocation
+	taken := make(map[string]bool)
+	for _, a := range r.Status.Allocations {
+		if a.Pod == pod.Name {
+			if selected {
+				ip = a.IP
+			}
+			continue
+		}
+		if !podExists(a.Pod) {
+			// the address of a deleted pod is free again
+			continue
+		}
+		allocations = append(allocations, a)
+		taken[a.IP] = true
+	}
+
+	if ip == "" && !selected {
+		addr, ok := reservedAddress(ranges, index)
+		if !ok {
+			return "", fmt.Errorf("no address with index %d in IPReservation %s/%s", index, r.Namespace, r.Name)
+		}
+		ip = addr.String()
+	}
+	for i := int64(0); ip == ""; i++ {
+		addr, ok := reservedAddress(ranges, i)
+		if !ok {
+			return "", fmt.Errorf("all the addresses of IPReservation %s/%s are allocated", r.Namespace, r.Name)
+		}
+		if !taken[addr.String()] {
+			ip = addr.String()
+		}
+	}
+	if taken[ip] {
+		return "", fmt.Errorf("address %s of IPReservation %s/%s is allocated to another pod", ip, r.Namespace, r.Name)
+	}
+
+	r.Status.Allocations = append(allocations, k8sv1alpha1.IPAllocation{Pod: pod.Name, IP: ip})
+	sort.Slice(r.Status.Allocations, func(i, j int) bool { return r.Status.Allocations[i].Pod < r.Status.Allocations[j].Pod })
+	return ip, nil
+}
+
+// ReservationNetworkField is the field the IPReservations are indexed by in the cache, the name of
+// their network
+const ReservationNetworkField = "spec.network"
+
+// IndexReservations indexes the IPReservations of the cache by network
+func IndexReservations(indexer client.FieldIndexer) error {
+	return indexer.IndexField(context.TODO(), &k8sv1alpha1.IPReservation{}, ReservationNetworkField, func(obj client.Object) []string {
+		return []string{obj.(*k8sv1alpha1.IPReservation).Spec.Network}
+	})
+}
+
+// networkReservations returns the IPReservations of the network from the cache, sorted by name
+func (oc *Controller) networkReservations(network string, opts ...client.ListOption) ([]k8sv1alpha1.IPReservation, error) {
+	if oc.client == nil {
+		return nil, fmt.Errorf("OVN Controller has no client")
+	}
+	reservations := &k8sv1alpha1.IPReservationList{}
+	err := oc.client.List(context.TODO(), reservations, append(opts, client.MatchingFields{ReservationNetworkField: network})...)
+	if notServed(err) {
+		// the IPReservation CRD is not installed
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(reservations.Items, func(i, j int) bool { return reservations.Items[i].Name < reservations.Items[j].Name })
+	return reservations.Items, nil
+}
+
+// podReservedAddress returns the address reserved to the pod in the network, empty when no
+// IPReservation of the namespace of the pod is for the pod. The reservations are read from the cache,
+// the one for the pod is read again from the API server to allocate the address
+func (oc *Controller) podReservedAddress(pod *kapi.Pod, network string) (string, error) {
+	reservations, err := oc.networkReservations(network, client.InNamespace(pod.Namespace))
+	if err != nil {
+		return "", err
+	}
+	podExists := func(name string) bool {
+		err := oc.client.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: name}, &kapi.Pod{})
+		return !errors.IsNotFound(err)
+	}
+
+	for _, item := range reservations {
+		if item.Status.State != k8sv1alpha1.Created || !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if _, _, ok := reservationIndex(&item, pod); !ok {
+			continue
+		}
+
+		k8sv1alpha1Clientset, err := kube.GetKubev1alpha1Config()
+		if err != nil {
+			return "", err
+		}
+		var ip string
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			r, err := k8sv1alpha1Clientset.IPReservations(pod.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			ip, err = allocateReservedAddress(r, pod, podExists)
+			if err != nil {
+				return err
+			}
+			_, err = k8sv1alpha1Clientset.IPReservations(pod.Namespace).UpdateStatus(context.TODO(), r, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+		log.Info("Using the reserved address", "pod", pod.Name, "namespace", pod.Namespace, "network", network, "IPReservation", item.Name, "ip", ip)
+		return ip, nil
+	}
+	return "", nil
+}
+
+// ipamExclusions returns the addresses reserved by the IPReservations of the network, the delegated
+// IPAM plugin doesn't give them to other pods. The addresses requested for the pod are not excluded
+func (oc *Controller) ipamExclusions(network string, requested []string) ([]reservedRange, error) {
+	reservations, err := oc.networkReservations(network)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []reservedRange
+	for _, item := range reservations {
+		if !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		r, err := reservationRanges(item.Spec.Ips)
+		if err != nil {
+			continue
+		}
+		ranges = append(ranges, r...)
+	}
+	for _, f := range requested {
+		if ip := parseIP(f); ip != nil {
+			ranges = withoutAddress(ranges, ip)
+		}
+	}
+	return ranges, nil
+}
+
+// checkStaticAddress rejects the static addresses of the pod reserved to other pods
+func (oc *Controller) checkStaticAddress(pod *kapi.Pod, network, ipAddress string) error {
+	reservations, err := oc.networkReservations(network)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range reservations {
+		if !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if _, _, ok := reservationIndex(&item, pod); ok {
+			continue
+		}
+		ranges, err := reservationRanges(item.Spec.Ips)
+		if err != nil {
+			continue
+		}
+		for _, f := range strings.Fields(ipAddress) {
+			if ip := parseIP(f); ip != nil && reservedContains(ranges, ip) {
+				return fmt.Errorf("address %s is reserved by IPReservation %s/%s", f, item.Namespace, item.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// checkAddressInUse rejects the static addresses already used by another port of the logical switch
+func checkAddressInUse(logicalSwitch, portName, ipAddress string) error {
+	ports, err := switchPortAddresses(logicalSwitch)
+	if err != nil {
+		return err
+	}
+	for _, f := range strings.Fields(ipAddress) {
+		ip := parseIP(f)
+		if ip == nil {
+			continue
+		}
+		for _, p := range ports {
+			if p.name == portName {
+				continue
+			}
+			for _, used := range p.ips {
+				if used.Equal(ip) {
+					return fmt.Errorf("address %s is used by port %s", f, p.name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// SetReservedAddresses excludes the reserved IPv4 addresses of the subnet of the network from the
+// dynamic addresses, along with the excludeIps of the network. OVN gives the dynamic IPv6 addresses
+// from the MAC address so the IPv6 reservations are not excluded
+func SetReservedAddresses(network, excludeIps string, reserved []string) error {
+	logicalSwitch := getIPv4LogicalSwitchName(network)
+	subnet, err := GetNetworkSubnet(logicalSwitch)
+	if err != nil {
+		return err
+	}
+	_, cidr, err := net.ParseCIDR(strings.Trim(subnet, `"`))
+	if err != nil || cidr.IP.To4() == nil {
+		// no switch or no IPv4 subnet
+		return nil
+	}
+
+	excluded := strings.Fields(excludeIps)
+	ranges, err := reservationRanges(reserved)
+	if err != nil {
+		return err
+	}
+	for _, r := range ranges {
+		first, last := intToAddr(r.first, r.v4), intToAddr(r.last, r.v4)
+		if !r.v4 || !cidr.Contains(first) || !cidr.Contains(last) {
+			continue
+		}
+		if first.Equal(last) {
+			excluded = append(excluded, first.String())
+		} else {
+			excluded = append(excluded, first.String()+".."+last.String())
+		}
+	}
+
+	var stdout, stderr string
+	if len(excluded) == 0 {
+		stdout, stderr, err = RunOVNNbctl("remove", "logical_switch", logicalSwitch, "other_config", "exclude_ips")
+	} else {
+		stdout, stderr, err = RunOVNNbctl("set", "logical_switch", logicalSwitch,
+			fmt.Sprintf("other_config:exclude_ips=\"%s\"", strings.Join(excluded, " ")))
+	}
+	if err != nil {
+		log.Error(err, "Failed to set the excluded addresses of the logical switch", "logicalSwitch", logicalSwitch, "stdout", stdout, "stderr", stderr)
+		return err
+	}
+	return nil
+}
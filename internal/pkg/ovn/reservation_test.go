@@ -0,0 +1,219 @@
+package ovn
+
+import (
+	"net"
+
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reservation returns an IPReservation of the addresses in the namespace "ns"
+func reservation(spec k8sv1alpha1.IPReservationSpec, allocations ...k8sv1alpha1.IPAllocation) *k8sv1alpha1.IPReservation {
+	return &k8sv1alpha1.IPReservation{
+		ObjectMeta: metav1.ObjectMeta{Name: "reservation", Namespace: "ns"},
+		Spec:       spec,
+		Status:     k8sv1alpha1.IPReservationStatus{Allocations: allocations},
+	}
+}
+
+// statefulSetPod returns the replica of the StatefulSet with the ordinal
+func statefulSetPod(statefulSet, name string) *kapi.Pod {
+	return &kapi.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       "ns",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: statefulSet}},
+	}}
+}
+
+// labeledPod returns a pod with the label app=web
+func labeledPod(name string) *kapi.Pod {
+	return &kapi.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: map[string]string{"app": "web"}}}
+}
+
+// podsExist returns a podExists func for the pods
+func podsExist(names ...string) func(string) bool {
+	return func(name string) bool {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+var webSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+
+var _ = Describe("Test IP reservations", func() {
+	table.DescribeTable("parses the reserved addresses",
+		func(ips []string, valid bool, addresses []string) {
+			ranges, err := reservationRanges(ips)
+			if !valid {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			var got []string
+			for i := int64(0); ; i++ {
+				ip, ok := reservedAddress(ranges, i)
+				if !ok {
+					break
+				}
+				got = append(got, ip.String())
+			}
+			Expect(got).To(Equal(addresses))
+		},
+		table.Entry("single addresses", []string{"10.0.0.10", "10.0.0.5"}, true, []string{"10.0.0.10", "10.0.0.5"}),
+		table.Entry("range", []string{"10.0.0.10..10.0.0.12", "fd00::1"}, true, []string{"10.0.0.10", "10.0.0.11", "10.0.0.12", "fd00::1"}),
+		table.Entry("IPv6 range", []string{" fd00::fe..fd00::101 "}, true, []string{"fd00::fe", "fd00::ff", "fd00::100", "fd00::101"}),
+		table.Entry("invalid address", []string{"10.0.0.256"}, false, nil),
+		table.Entry("reversed range", []string{"10.0.0.12..10.0.0.10"}, false, nil),
+		table.Entry("range of mixed families", []string{"10.0.0.10..fd00::1"}, false, nil),
+	)
+
+	It("checks if an address is reserved", func() {
+		ranges, err := reservationRanges([]string{"10.0.0.10..10.0.0.12", "fd00::1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reservedContains(ranges, net.ParseIP("10.0.0.11"))).To(BeTrue())
+		Expect(reservedContains(ranges, net.ParseIP("10.0.0.13"))).To(BeFalse())
+		Expect(reservedContains(ranges, net.ParseIP("fd00::1"))).To(BeTrue())
+		Expect(reservedContains(ranges, net.ParseIP("::ffff:10.0.0.10"))).To(BeTrue())
+	})
+
+	table.DescribeTable("excludes the reserved addresses but the requested ones",
+		func(ips []string, requested string, cidrs []string) {
+			ranges, err := reservationRanges(ips)
+			Expect(err).NotTo(HaveOccurred())
+			ranges = withoutAddress(ranges, net.ParseIP(requested))
+			var got []string
+			for _, r := range ranges {
+				got = append(got, rangeCIDRs(r)...)
+			}
+			Expect(got).To(Equal(cidrs))
+		},
+		table.Entry("address out of the ranges", []string{"10.0.0.10"}, "10.0.0.11", []string{"10.0.0.10/32"}),
+		table.Entry("aligned range", []string{"10.0.0.8..10.0.0.15"}, "10.0.0.20", []string{"10.0.0.8/29"}),
+		table.Entry("unaligned range", []string{"10.0.0.7..10.0.0.17"}, "10.0.0.20",
+			[]string{"10.0.0.7/32", "10.0.0.8/29", "10.0.0.16/31"}),
+		table.Entry("requested address in a range", []string{"10.0.0.8..10.0.0.15"}, "10.0.0.11",
+			[]string{"10.0.0.8/31", "10.0.0.10/32", "10.0.0.12/30"}),
+		table.Entry("requested address alone", []string{"10.0.0.10", "fd00::1..fd00::2"}, "10.0.0.10", []string{"fd00::1/128", "fd00::2/128"}),
+		table.Entry("IPv6 range", []string{"fd00::..fd00::ff"}, "10.0.0.10", []string{"fd00::/120"}),
+	)
+
+	table.DescribeTable("detects the overlapping reservations",
+		func(a, b []string, overlap, valid bool) {
+			got, err := ReservedIpsOverlap(a, b)
+			if !valid {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(Equal(overlap))
+		},
+		table.Entry("same address", []string{"10.0.0.10"}, []string{"10.0.0.10"}, true, true),
+		table.Entry("address in a range", []string{"10.0.0.10..10.0.0.20"}, []string{"10.0.0.15"}, true, true),
+		table.Entry("ranges sharing their bounds", []string{"10.0.0.10..10.0.0.20"}, []string{"10.0.0.20..10.0.0.30"}, true, true),
+		table.Entry("adjacent ranges", []string{"10.0.0.10..10.0.0.20"}, []string{"10.0.0.21..10.0.0.30"}, false, true),
+		table.Entry("IPv4 and IPv6 addresses", []string{"0.0.0.1"}, []string{"::1"}, false, true),
+		table.Entry("invalid address", []string{"10.0.0.10"}, []string{"10.0.0"}, false, false),
+	)
+
+	table.DescribeTable("finds the index of the pod in the reservation",
+		func(spec k8sv1alpha1.IPReservationSpec, pod *kapi.Pod, index int64, selected, ok bool) {
+			i, s, found := reservationIndex(reservation(spec), pod)
+			Expect(found).To(Equal(ok))
+			Expect(s).To(Equal(selected))
+			Expect(i).To(Equal(index))
+		},
+		table.Entry("pod name", k8sv1alpha1.IPReservationSpec{PodName: "db"},
+			&kapi.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ns"}}, int64(0), false, true),
+		table.Entry("other pod name", k8sv1alpha1.IPReservationSpec{PodName: "db"},
+			&kapi.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns"}}, int64(0), false, false),
+		table.Entry("pod of another namespace", k8sv1alpha1.IPReservationSpec{PodName: "db"},
+			&kapi.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "other"}}, int64(0), false, false),
+		table.Entry("StatefulSet ordinal", k8sv1alpha1.IPReservationSpec{StatefulSet: "db"},
+			statefulSetPod("db", "db-3"), int64(3), false, true),
+		table.Entry("pod of another StatefulSet", k8sv1alpha1.IPReservationSpec{StatefulSet: "db"},
+			statefulSetPod("cache", "cache-3"), int64(0), false, false),
+		table.Entry("StatefulSet pod without ordinal", k8sv1alpha1.IPReservationSpec{StatefulSet: "db"},
+			statefulSetPod("db", "db-main"), int64(0), false, false),
+		table.Entry("selected pod", k8sv1alpha1.IPReservationSpec{Selector: webSelector},
+			labeledPod("web-x"), int64(0), true, true),
+		table.Entry("pod not selected", k8sv1alpha1.IPReservationSpec{Selector: webSelector},
+			&kapi.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ns"}}, int64(0), true, false),
+	)
+
+	Context("allocating the reserved addresses", func() {
+		It("gives the replicas of a StatefulSet the address of their ordinal", func() {
+			r := reservation(k8sv1alpha1.IPReservationSpec{StatefulSet: "db", Ips: []string{"10.0.0.10", "10.0.0.20..10.0.0.21"}})
+			ip, err := allocateReservedAddress(r, statefulSetPod("db", "db-2"), podsExist())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ip).To(Equal("10.0.0.21"))
+
+			_, err = allocateReservedAddress(r, statefulSetPod("db", "db-3"), podsExist())
+			Expect(err).To(HaveOccurred())
+			Expect(r.Status.Allocations).To(Equal([]k8sv1alpha1.IPAllocation{{Pod: "db-2", IP: "10.0.0.21"}}))
+		})
+
+		It("doesn't allocate an address to a pod out of the reservation", func() {
+			r := reservation(k8sv1alpha1.IPReservationSpec{PodName: "db", Ips: []string{"10.0.0.10"}})
+			ip, err := allocateReservedAddress(r, labeledPod("web-a"), podsExist())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ip).To(BeEmpty())
+			Expect(r.Status.Allocations).To(BeEmpty())
+		})
+
+		It("keeps the address of a selected pod", func() {
+			r := reservation(k8sv1alpha1.IPReservationSpec{Selector: webSelector, Ips: []string{"10.0.0.10..10.0.0.12"}},
+				k8sv1alpha1.IPAllocation{Pod: "web-a", IP: "10.0.0.10"},
+				k8sv1alpha1.IPAllocation{Pod: "web-b", IP: "10.0.0.12"})
+			ip, err := allocateReservedAddress(r, labeledPod("web-b"), podsExist("web-a", "web-b"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ip).To(Equal("10.0.0.12"))
+
+			ip, err = allocateReservedAddress(r, labeledPod("web-c"), podsExist("web-a", "web-b", "web-c"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ip).To(Equal("10.0.0.11"))
+			Expect(r.Status.Allocations).To(Equal([]k8sv1alpha1.IPAllocation{
+				{Pod: "web-a", IP: "10.0.0.10"},
+				{Pod: "web-b", IP: "10.0.0.12"},
+				{Pod: "web-c", IP: "10.0.0.11"},
+			}))
+		})
+
+		It("frees the addresses of the deleted pods", func() {
+			r := reservation(k8sv1alpha1.IPReservationSpec{Selector: webSelector, Ips: []string{"10.0.0.10..10.0.0.11"}},
+				k8sv1alpha1.IPAllocation{Pod: "web-a", IP: "10.0.0.10"},
+				k8sv1alpha1.IPAllocation{Pod: "web-b", IP: "10.0.0.11"})
+			ip, err := allocateReservedAddress(r, labeledPod("web-c"), podsExist("web-b", "web-c"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ip).To(Equal("10.0.0.10"))
+			Expect(r.Status.Allocations).To(Equal([]k8sv1alpha1.IPAllocation{
+				{Pod: "web-b", IP: "10.0.0.11"},
+				{Pod: "web-c", IP: "10.0.0.10"},
+			}))
+		})
+
+		It("fails when all the addresses are allocated", func() {
+			r := reservation(k8sv1alpha1.IPReservationSpec{Selector: webSelector, Ips: []string{"10.0.0.10"}},
+				k8sv1alpha1.IPAllocation{Pod: "web-a", IP: "10.0.0.10"})
+			_, err := allocateReservedAddress(r, labeledPod("web-b"), podsExist("web-a", "web-b"))
+			Expect(err).To(MatchError("all the addresses of IPReservation ns/reservation are allocated"))
+			Expect(r.Status.Allocations).To(HaveLen(1))
+		})
+
+		It("fails when the address of the pod is allocated to another pod", func() {
+			r := reservation(k8sv1alpha1.IPReservationSpec{PodName: "db", Ips: []string{"10.0.0.10"}},
+				k8sv1alpha1.IPAllocation{Pod: "old-db", IP: "10.0.0.10"})
+			pod := &kapi.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ns"}}
+			_, err := allocateReservedAddress(r, pod, podsExist("old-db"))
+			Expect(err).To(MatchError("address 10.0.0.10 of IPReservation ns/reservation is allocated to another pod"))
+		})
+	})
+})
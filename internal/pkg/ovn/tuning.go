@@ -0,0 +1,97 @@
+package ovn
+
+import (
+	"sync"
+
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+)
+
+// interfaceTuning holds the per network settings applied by the CNI to the pod interfaces
+type interfaceTuning struct {
+	Mtu               int             `json:"mtu,omitempty"`
+	TxQueueLen        int             `json:"txqueuelen,omitempty"`
+	Offload           map[string]bool `json:"offload,omitempty"`
+	ProviderInterface string          `json:"provider_interface,omitempty"`
+	// AttachType, AttachMode and AttachMaster describe the macvlan or ipvlan sub-interface
+	// created on the node instead of the OVS veth pair
+	AttachType   string `json:"attach_type,omitempty"`
+	AttachMode   string `json:"attach_mode,omitempty"`
+	AttachMaster string `json:"attach_master,omitempty"`
+}
+
+// tuningCache keeps the interface settings per logical switch
+type tuningCache struct {
+	sync.RWMutex
+	conf map[string]*interfaceTuning
+}
+
+func (c *tuningCache) set(logicalSwitch string, tuning *interfaceTuning) {
+	c.Lock()
+	defer c.Unlock()
+	if tuning == nil || tuning.isEmpty() {
+		delete(c.conf, logicalSwitch)
+		return
+	}
+	c.conf[logicalSwitch] = tuning
+}
+
+func (c *tuningCache) get(logicalSwitch string) (*interfaceTuning, bool) {
+	c.RLock()
+	defer c.RUnlock()
+	tuning, ok := c.conf[logicalSwitch]
+	return tuning, ok
+}
+
+func (t *interfaceTuning) isEmpty() bool {
+	return t.Mtu == 0 && t.TxQueueLen == 0 && len(t.Offload) == 0 && t.AttachType == ""
+}
+
+func newInterfaceTuning(mtu, txQueueLen int, offload *k8sv1alpha1.OffloadSpec) *interfaceTuning {
+	tuning := &interfaceTuning{
+		Mtu:        mtu,
+		TxQueueLen: txQueueLen,
+	}
+	if offload != nil {
+		tuning.Offload = map[string]bool{}
+		for name, value := range map[string]*bool{
+			"tx":  offload.Tx,
+			"rx":  offload.Rx,
+			"tso": offload.Tso,
+			"gso": offload.Gso,
+			"gro": offload.Gro,
+		} {
+			if value != nil {
+				tuning.Offload[name] = *value
+			}
+		}
+	}
+	return tuning
+}
+
+func newProviderInterfaceTuning(cr *k8sv1alpha1.ProviderNetwork) *interfaceTuning {
+	tuning := newInterfaceTuning(cr.Spec.Mtu, cr.Spec.TxQueueLen, cr.Spec.Offload)
+
+	tuning.ProviderInterface = cr.Spec.Direct.ProviderInterfaceName
+	if cr.Spec.ProviderNetType == "VLAN" {
+		tuning.ProviderInterface = cr.Spec.Vlan.ProviderInterfaceName
+	}
+
+	switch attachType := cr.Spec.GetAttachmentType(); attachType {
+	case k8sv1alpha1.MacvlanAttachment, k8sv1alpha1.IpvlanAttachment:
+		tuning.AttachType = attachType
+		tuning.AttachMode = cr.Spec.Attachment.Mode
+		tuning.AttachMaster = tuning.ProviderInterface
+		if cr.Spec.ProviderNetType == "VLAN" {
+			tuning.AttachMaster = GetVlanLogicalInterfaceName(cr.Name, cr.Spec.Vlan.VlanId, cr.Spec.Vlan.LogicalInterfaceName)
+		}
+	}
+	return tuning
+}
+
+// GetVlanLogicalInterfaceName returns the name of the VLAN interface created on the nodes
+func GetVlanLogicalInterfaceName(name, vlanID, logicalInterfaceName string) string {
+	if logicalInterfaceName != "" {
+		return logicalInterfaceName
+	}
+	return name + "." + vlanID
+}
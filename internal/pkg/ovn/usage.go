@@ -0,0 +1,269 @@
+package ovn
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"sort"
+	"strings"
+
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+)
+
+// portAddresses are the IP addresses of a logical switch port, the namespace is set for pod ports
+type portAddresses struct {
+	name      string
+	namespace string
+	ips       []net.IP
+}
+
+// ipRange is an inclusive range of IP addresses
+type ipRange struct {
+	first, last *big.Int
+}
+
+// NetworkUsage returns the IP address usage of the subnets of the network and of the namespaces of
+// the pods attached to it. The addresses are read from the ports of the logical switches of the network
+func NetworkUsage(name string, ipv4Subnets, ipv6Subnets []k8sv1alpha1.IpSubnet) ([]k8sv1alpha1.SubnetUsage, []k8sv1alpha1.NamespaceUsage, error) {
+	ports, err := switchPortAddresses(getIPv4LogicalSwitchName(name), getIPv6LogicalSwitchName(name))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var subnets []k8sv1alpha1.SubnetUsage
+	var cidrs []*net.IPNet
+	for _, sn := range append(append([]k8sv1alpha1.IpSubnet{}, ipv4Subnets...), ipv6Subnets...) {
+		_, cidr, err := net.ParseCIDR(sn.Subnet)
+		if err != nil {
+			log.Info("Skipping the usage of the invalid subnet", "network", name, "subnet", sn.Subnet)
+			continue
+		}
+		cidrs = append(cidrs, cidr)
+		subnets = append(subnets, subnetUsage(sn, cidr, ports))
+	}
+
+	allocated := make(map[string]int64)
+	for _, p := range ports {
+		if p.namespace == "" {
+			continue
+		}
+		for _, ip := range p.ips {
+			for _, cidr := range cidrs {
+				if cidr.Contains(ip) {
+					allocated[p.namespace]++
+					break
+				}
+			}
+		}
+	}
+	var namespaces []k8sv1alpha1.NamespaceUsage
+	for ns, n := range allocated {
+		namespaces = append(namespaces, k8sv1alpha1.NamespaceUsage{Namespace: ns, Allocated: n})
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Namespace < namespaces[j].Namespace })
+
+	return subnets, namespaces, nil
+}
+
+// subnetUsage counts the addresses of the subnet. The gateway and the excludeIps are excluded, the
+// addresses of the ports in the excluded ranges are not counted as allocated
+func subnetUsage(sn k8sv1alpha1.IpSubnet, cidr *net.IPNet, ports []portAddresses) k8sv1alpha1.SubnetUsage {
+	ones, bits := cidr.Mask.Size()
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	first := ipToInt(cidr.IP)
+	last := new(big.Int).Sub(new(big.Int).Add(first, size), big.NewInt(1))
+	// the network address is never given, nor the broadcast address of an IPv4 subnet
+	if size.Cmp(big.NewInt(2)) > 0 {
+		first.Add(first, big.NewInt(1))
+		if bits == 8*net.IPv4len {
+			last.Sub(last, big.NewInt(1))
+		}
+	}
+	total := new(big.Int).Add(new(big.Int).Sub(last, first), big.NewInt(1))
+
+	ranges := parseExcludeIps(sn.ExcludeIps)
+	if gw := parseIP(sn.Gateway); gw != nil {
+		ranges = append(ranges, ipRange{ipToInt(gw), ipToInt(gw)})
+	}
+	ranges = clipRanges(ranges, first, last)
+	excluded := new(big.Int)
+	for _, r := range ranges {
+		excluded.Add(excluded, new(big.Int).Add(new(big.Int).Sub(r.last, r.first), big.NewInt(1)))
+	}
+
+	seen := make(map[string]bool)
+	var allocated int64
+	for _, p := range ports {
+		for _, ip := range p.ips {
+			if !cidr.Contains(ip) || seen[ip.String()] || inRanges(ranges, ipToInt(ip)) {
+				continue
+			}
+			seen[ip.String()] = true
+			allocated++
+		}
+	}
+
+	free := new(big.Int).Sub(total, excluded)
+	free.Sub(free, big.NewInt(allocated))
+	if free.Sign() < 0 {
+		free.SetInt64(0)
+	}
+
+	return k8sv1alpha1.SubnetUsage{
+		Name:      sn.Name,
+		Subnet:    sn.Subnet,
+		Total:     capInt64(total),
+		Allocated: allocated,
+		Excluded:  capInt64(excluded),
+		Free:      capInt64(free),
+	}
+}
+
+// switchPortAddresses returns the IP addresses of the ports of the logical switches, only the
+// ports of the switches are listed
+func switchPortAddresses(logicalSwitches ...string) ([]portAddresses, error) {
+	var uuids []string
+	for _, ls := range logicalSwitches {
+		stdout, stderr, err := RunOVNNbctl("--data=bare", "--no-heading",
+			"--columns=ports", "find", "logical_switch", "name="+ls)
+		if err != nil {
+			log.Error(err, "Failed to get the ports of the logical switch", "stderr", stderr)
+			return nil, err
+		}
+		uuids = append(uuids, strings.Fields(stdout)...)
+	}
+	if len(uuids) == 0 {
+		return nil, nil
+	}
+
+	args := append([]string{"--data=bare", "--no-heading", "--format=csv",
+		"--columns=name,addresses,dynamic_addresses,external_ids", "list", "logical_switch_port"}, uuids...)
+	stdout, stderr, err := RunOVNNbctl(args...)
+	if err != nil {
+		log.Error(err, "Failed to list the logical switch ports", "stderr", stderr)
+		return nil, err
+	}
+	r := csv.NewReader(strings.NewReader(stdout))
+	r.FieldsPerRecord = 4
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the logical switch ports: %v", err)
+	}
+
+	var ports []portAddresses
+	for _, record := range records {
+		p := portAddresses{name: record[0], namespace: podNamespace(record[3])}
+		seen := make(map[string]bool)
+		// static addresses are "mac ip...", dynamic addresses are in dynamic_addresses
+		for _, f := range strings.Fields(record[1] + " " + record[2]) {
+			ip := net.ParseIP(strings.Trim(f, `"[],`))
+			if ip == nil || seen[ip.String()] {
+				continue
+			}
+			seen[ip.String()] = true
+			p.ips = append(p.ips, ip)
+		}
+		ports = append(ports, p)
+	}
+	return ports, nil
+}
+
+// podNamespace returns the namespace of a pod port from its external ids
+func podNamespace(externalIDs string) string {
+	var namespace string
+	var pod bool
+	for _, f := range strings.Fields(externalIDs) {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "namespace":
+			namespace = kv[1]
+		case "pod":
+			pod = kv[1] == "true"
+		}
+	}
+	if !pod {
+		return ""
+	}
+	return namespace
+}
+
+// parseExcludeIps returns the ranges of the excludeIps, e.g. "10.0.0.2 10.0.0.10..10.0.0.20"
+func parseExcludeIps(excludeIps string) []ipRange {
+	var ranges []ipRange
+	for _, f := range strings.Fields(excludeIps) {
+		bounds := strings.SplitN(f, "..", 2)
+		first := parseIP(bounds[0])
+		last := first
+		if len(bounds) == 2 {
+			last = parseIP(bounds[1])
+		}
+		if first == nil || last == nil {
+			log.Info("Skipping the invalid excluded IP address", "excludeIps", f)
+			continue
+		}
+		ranges = append(ranges, ipRange{ipToInt(first), ipToInt(last)})
+	}
+	return ranges
+}
+
+// parseIP parses an address with or without a prefix length
+func parseIP(s string) net.IP {
+	if ip, _, err := net.ParseCIDR(s); err == nil {
+		return ip
+	}
+	return net.ParseIP(s)
+}
+
+// clipRanges returns the ranges within [first, last], sorted and merged
+func clipRanges(ranges []ipRange, first, last *big.Int) []ipRange {
+	var clipped []ipRange
+	for _, r := range ranges {
+		f, l := r.first, r.last
+		if f.Cmp(first) < 0 {
+			f = first
+		}
+		if l.Cmp(last) > 0 {
+			l = last
+		}
+		if f.Cmp(l) <= 0 {
+			clipped = append(clipped, ipRange{f, l})
+		}
+	}
+	sort.Slice(clipped, func(i, j int) bool { return clipped[i].first.Cmp(clipped[j].first) < 0 })
+
+	var merged []ipRange
+	for _, r := range clipped {
+		if n := len(merged); n > 0 && r.first.Cmp(new(big.Int).Add(merged[n-1].last, big.NewInt(1))) <= 0 {
+			if r.last.Cmp(merged[n-1].last) > 0 {
+				merged[n-1].last = r.last
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// inRanges checks if the address is in one of the ranges
+func inRanges(ranges []ipRange, i *big.Int) bool {
+	for _, r := range ranges {
+		if i.Cmp(r.first) >= 0 && i.Cmp(r.last) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// capInt64 returns the integer, capped to the largest int64
+func capInt64(i *big.Int) int64 {
+	if !i.IsInt64() {
+		return math.MaxInt64
+	}
+	return i.Int64()
+}
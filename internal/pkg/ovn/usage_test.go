@@ -0,0 +1,93 @@
+package ovn
+
+import (
+	"math"
+	"math/big"
+	"net"
+	"testing"
+
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func TestOvn(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OVN Test Suite")
+}
+
+// port returns the addresses of a port
+func port(name, namespace string, ips ...string) portAddresses {
+	p := portAddresses{name: name, namespace: namespace}
+	for _, ip := range ips {
+		p.ips = append(p.ips, net.ParseIP(ip))
+	}
+	return p
+}
+
+var _ = Describe("Test subnet usage", func() {
+	table.DescribeTable("counts the addresses of the subnet",
+		func(sn k8sv1alpha1.IpSubnet, ports []portAddresses, total, allocated, excluded, free int64) {
+			_, cidr, err := net.ParseCIDR(sn.Subnet)
+			Expect(err).NotTo(HaveOccurred())
+
+			u := subnetUsage(sn, cidr, ports)
+			Expect(u.Name).To(Equal(sn.Name))
+			Expect(u.Subnet).To(Equal(sn.Subnet))
+			Expect(u.Total).To(Equal(total))
+			Expect(u.Allocated).To(Equal(allocated))
+			Expect(u.Excluded).To(Equal(excluded))
+			Expect(u.Free).To(Equal(free))
+		},
+		table.Entry("IPv4 subnet without network and broadcast addresses",
+			k8sv1alpha1.IpSubnet{Name: "subnet", Subnet: "172.16.33.0/24"}, nil,
+			int64(254), int64(0), int64(0), int64(254)),
+		table.Entry("IPv4 subnet with the gateway and excluded addresses",
+			k8sv1alpha1.IpSubnet{Name: "subnet", Subnet: "172.16.33.0/24", Gateway: "172.16.33.1/24", ExcludeIps: "172.16.33.2 172.16.33.10..172.16.33.19"},
+			[]portAddresses{port("a", "ns", "172.16.33.3"), port("b", "ns", "172.16.33.4")},
+			int64(254), int64(2), int64(12), int64(240)),
+		table.Entry("ports in the excluded ranges and out of the subnet are not counted",
+			k8sv1alpha1.IpSubnet{Name: "subnet", Subnet: "172.16.33.0/24", ExcludeIps: "172.16.33.10..172.16.33.19"},
+			[]portAddresses{port("a", "ns", "172.16.33.12"), port("b", "ns", "172.16.34.4"), port("c", "", "172.16.33.30", "fd00::30")},
+			int64(254), int64(1), int64(10), int64(243)),
+		table.Entry("an address of several ports is counted once",
+			k8sv1alpha1.IpSubnet{Name: "subnet", Subnet: "172.16.33.0/24"},
+			[]portAddresses{port("a", "ns", "172.16.33.5"), port("b", "ns", "172.16.33.5")},
+			int64(254), int64(1), int64(0), int64(253)),
+		table.Entry("excluded ranges are clipped to the subnet and merged",
+			k8sv1alpha1.IpSubnet{Name: "subnet", Subnet: "172.16.33.0/28", ExcludeIps: "172.16.32.250..172.16.33.3 172.16.33.2..172.16.33.5 172.16.33.14..172.16.34.1 invalid"},
+			nil,
+			int64(14), int64(0), int64(6), int64(8)),
+		table.Entry("small IPv4 subnet keeps all its addresses",
+			k8sv1alpha1.IpSubnet{Name: "subnet", Subnet: "172.16.33.0/31"}, nil,
+			int64(2), int64(0), int64(0), int64(2)),
+		table.Entry("IPv6 subnet keeps its last address",
+			k8sv1alpha1.IpSubnet{Name: "subnet", Subnet: "fd00:10::/120", Gateway: "fd00:10::1/120"},
+			[]portAddresses{port("a", "ns", "fd00:10::3")},
+			int64(255), int64(1), int64(1), int64(253)),
+		table.Entry("large IPv6 subnet is capped",
+			k8sv1alpha1.IpSubnet{Name: "subnet", Subnet: "fd00:10::/64", Gateway: "fd00:10::1/64"},
+			[]portAddresses{port("a", "ns", "fd00:10::3")},
+			int64(math.MaxInt64), int64(1), int64(1), int64(math.MaxInt64)),
+	)
+
+	table.DescribeTable("reads the namespace of the pod ports",
+		func(externalIDs, namespace string) {
+			Expect(podNamespace(externalIDs)).To(Equal(namespace))
+		},
+		table.Entry("pod port", "logical_switch=net namespace=tenant-a pod=true", "tenant-a"),
+		table.Entry("port that is not a pod port", "namespace=tenant-a", ""),
+		table.Entry("no external ids", "", ""),
+	)
+
+	It("merges the excluded ranges", func() {
+		ranges := clipRanges(parseExcludeIps("10.0.0.8..10.0.0.9 10.0.0.2 10.0.0.3..10.0.0.5 10.0.0.20"), big.NewInt(0), ipToInt(net.ParseIP("10.0.0.10")))
+		Expect(ranges).To(HaveLen(2))
+		Expect(ranges[0].first).To(Equal(ipToInt(net.ParseIP("10.0.0.2"))))
+		Expect(ranges[0].last).To(Equal(ipToInt(net.ParseIP("10.0.0.5"))))
+		Expect(ranges[1].first).To(Equal(ipToInt(net.ParseIP("10.0.0.8"))))
+		Expect(ranges[1].last).To(Equal(ipToInt(net.ParseIP("10.0.0.9"))))
+	})
+})
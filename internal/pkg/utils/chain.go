@@ -19,7 +19,9 @@ package nfn
 import (
 	"context"
 	"fmt"
+	"net"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -29,6 +31,8 @@ import (
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/network"
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
 	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+	pnv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/generated/clientset/versioned/typed/k8s/v1alpha1"
+	"github.com/akraino-edge-stack/icn-nodus/pkg/utils"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog"
@@ -40,7 +44,10 @@ import (
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/docker/docker/client"
 	"github.com/mitchellh/mapstructure"
+	"github.com/vishvananda/netlink"
+	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/json"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -79,51 +86,203 @@ const (
 	SFCTail          = "sfctail"
 )
 
+// GatewaySeparator separates the gateways of an ECMP route over the replicas of a network function
+const GatewaySeparator = ","
+
+// PendingError is returned when the chain waits for a network function to have a ready replica
+type PendingError struct {
+	Reason string
+}
+
+func (e *PendingError) Error() string {
+	return e.Reason
+}
+
+// pendingNetworkFunction returns the PendingError of the network function with the pod label
+func pendingNetworkFunction(namespace, podLabel string) error {
+	return &PendingError{Reason: fmt.Sprintf("no ready replica of the network function %s in namespace %s", podLabel, namespace)}
+}
+
 //IsEmpty return true or false
 func (r RoutingInfo) IsEmpty() bool {
 	return reflect.DeepEqual(r, RoutingInfo{})
 }
 
-// CheckPodStatusFromPodLabel return error
-func CheckPodStatusFromPodLabel(podLabel string) (bool, string, error) {
-	var err error
+// CheckPodStatusFromPodLabel returns the names of the ready replicas of the network function with
+// the pod label, false when no replica is ready
+func CheckPodStatusFromPodLabel(namespace, podLabel string) (bool, []string, error) {
+	pods, err := readyReplicas(namespace, podLabel)
+	if err != nil {
+		return false, nil, err
+	}
 
+	if len(pods) == 0 {
+		log.Info("No ready replica of the network function", "namespace", namespace, "podLabel", podLabel)
+		return false, nil, nil
+	}
+
+	var podNames []string
+	for _, pod := range pods {
+		podNames = append(podNames, pod.GetName())
+	}
+	return true, podNames, nil
+}
+
+// PodReady checks if the pod is running and its first container is ready, as the replicas of the
+// network functions are selected
+func PodReady(pod *corev1.Pod) bool {
+	return pod.DeletionTimestamp.IsZero() && pod.Status.Phase == corev1.PodRunning &&
+		len(pod.Status.ContainerStatuses) != 0 && pod.Status.ContainerStatuses[0].Ready
+}
+
+// readyReplicas returns the ready pods with the label sorted by name, the replicas of a network
+// function sharing the traffic of the chain
+func readyReplicas(namespace, podLabel string) ([]corev1.Pod, error) {
 	// Get a config to talk to the apiserver
 	clientset, err := kube.GetKubeConfig()
 	if err != nil {
 		log.Error(err, "Error in kube clientset")
-		return false, "", err
+		return nil, err
 	}
 
-	pods, err := clientset.CoreV1().Pods("default").List(context.TODO(), v1.ListOptions{LabelSelector: podLabel})
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), v1.ListOptions{LabelSelector: podLabel})
 	if err != nil {
-		log.Error(err, "Error in kube clientset in listing the pods for default namespace with label", "podLabel", podLabel)
-		return false, "", err
+		log.Error(err, "Error in kube clientset in listing the pods for namespace with label", "namespace", namespace, "podLabel", podLabel)
+		return nil, err
 	}
 
-	if len(pods.Items) != 1 {
-		err := fmt.Errorf("Currently load balancing is not supported, expected SFC deployment has only 1 replica")
-		log.Error(err, "Error in kube clientset in listing the pods for namespace", "podLabel", podLabel)
-		return false, "", err
+	var ready []corev1.Pod
+	for _, pod := range pods.Items {
+		if PodReady(&pod) {
+			ready = append(ready, pod)
+		}
 	}
+	sort.Slice(ready, func(i, j int) bool { return ready[i].GetName() < ready[j].GetName() })
+	return ready, nil
+}
 
-	podName := pods.Items[0].GetName()
-
-	for {
-		_, nf, err := checkPodstatus(podName)
+// replicaGateways returns the addresses of the replicas on the network. The addresses of several
+// replicas are the gateways of an ECMP route separated by GatewaySeparator
+func replicaGateways(networkname string, podNames []string) (string, error) {
+	var gws []string
+	for _, podName := range podNames {
+		ip, err := ovn.GetIPAdressForPod(networkname, podName)
 		if err != nil {
-			return false, "", err
-		}
-		if nf {
-			break
+			return "", err
 		}
+		gws = append(gws, ip)
 	}
+	return strings.Join(gws, GatewaySeparator), nil
+}
 
-	return true, podName, nil
+// getProviderNetwork returns the provider network with the name from any namespace, or the
+// ClusterProviderNetwork with the name. The provider network names are the OVN logical switch
+// names so they are unique in the cluster.
+func getProviderNetwork(cs *pnv1alpha1.K8sV1alpha1Client, name string) (*k8sv1alpha1.ProviderNetwork, error) {
+	pnList, err := cs.ProviderNetworks(v1.NamespaceAll).List(context.TODO(), v1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()})
+	if err != nil {
+		return nil, err
+	}
+	if len(pnList.Items) != 0 {
+		return &pnList.Items[0], nil
+	}
+	cpn, err := cs.ClusterProviderNetworks().Get(context.TODO(), name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return cpn.ProviderNetwork(), nil
+}
+
+// nfNamespace returns the namespace of the network function pods of the chain, the namespace of
+// the RouteSpec or else the namespace of the NetworkChaining
+func nfNamespace(cr *k8sv1alpha1.NetworkChaining) string {
+	if cr.Spec.RoutingSpec.Namespace != "" {
+		return cr.Spec.RoutingSpec.Namespace
+	}
+	if cr.Namespace != "" {
+		return cr.Namespace
+	}
+	return v1.NamespaceDefault
+}
+
+// networksFromLabel returns the networks with the label in the namespace, or in any namespace if
+// the namespace has none. The networks created from the network pools are in the default namespace
+func networksFromLabel(cs *pnv1alpha1.K8sV1alpha1Client, namespace, label string) ([]k8sv1alpha1.Network, error) {
+	nets, err := cs.Networks(namespace).List(context.TODO(), v1.ListOptions{LabelSelector: label})
+	if err != nil {
+		return nil, err
+	}
+	if len(nets.Items) != 0 || namespace == v1.NamespaceAll {
+		return nets.Items, nil
+	}
+	nets, err = cs.Networks(v1.NamespaceAll).List(context.TODO(), v1.ListOptions{LabelSelector: label})
+	if err != nil {
+		return nil, err
+	}
+	return nets.Items, nil
+}
+
+// getNetwork returns the network with the name in the namespace, or from any namespace. The
+// network names are the OVN logical switch names so they are unique in the cluster.
+func getNetwork(cs *pnv1alpha1.K8sV1alpha1Client, namespace, name string) (*k8sv1alpha1.Network, error) {
+	net, err := cs.Networks(namespace).Get(context.TODO(), name, v1.GetOptions{})
+	if err == nil || !errors.IsNotFound(err) {
+		return net, err
+	}
+	nets, err := cs.Networks(v1.NamespaceAll).List(context.TODO(), v1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()})
+	if err != nil {
+		return nil, err
+	}
+	if len(nets.Items) == 0 {
+		return nil, fmt.Errorf("network %s not found", name)
+	}
+	return &nets.Items[0], nil
+}
+
+// emptySelector checks if the label selector has no requirement
+func emptySelector(selector v1.LabelSelector) bool {
+	return len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0
+}
+
+// selectedNamespaces returns the namespaces selected by the namespace selector of a routing network
+// of the chain. As for a NetworkPolicy, an empty selector selects the namespace of the NetworkChaining
+func selectedNamespaces(clientset *kubernetes.Clientset, cr *k8sv1alpha1.NetworkChaining, rn k8sv1alpha1.RoutingNetwork) ([]string, error) {
+	if emptySelector(rn.NamespaceSelector) {
+		return []string{cr.Namespace}, nil
+	}
+	selector, err := v1.LabelSelectorAsSelector(&rn.NamespaceSelector)
+	if err != nil {
+		return nil, err
+	}
+	nslist, err := clientset.CoreV1().Namespaces().List(context.TODO(), v1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for _, ns := range nslist.Items {
+		namespaces = append(namespaces, ns.GetName())
+	}
+	return namespaces, nil
+}
+
+// selectsPod checks if a routing network of a NetworkChaining of the namespace selects the pod
+func selectsPod(namespace string, rn k8sv1alpha1.RoutingNetwork, pod *corev1.Pod, podNamespace *corev1.Namespace) bool {
+	if emptySelector(rn.PodSelector) {
+		return false
+	}
+	podSelector, err := v1.LabelSelectorAsSelector(&rn.PodSelector)
+	if err != nil || !podSelector.Matches(labels.Set(pod.GetLabels())) {
+		return false
+	}
+	if emptySelector(rn.NamespaceSelector) {
+		return podNamespace.GetName() == namespace
+	}
+	nsSelector, err := v1.LabelSelectorAsSelector(&rn.NamespaceSelector)
+	return err == nil && nsSelector.Matches(labels.Set(podNamespace.GetLabels()))
 }
 
 //configurePodSelectorDeployment
-func configurePodSelectorDeployment(ln k8sv1alpha1.RoutingNetwork, sfcEntryPodLabel string, toDelete bool, mode string, networklabel string, sfcposition string, dst []string) ([]RoutingInfo, []PodNetworkInfo, error) {
+func configurePodSelectorDeployment(cr *k8sv1alpha1.NetworkChaining, ln k8sv1alpha1.RoutingNetwork, sfcEntryPodLabel string, toDelete bool, refresh bool, mode string, networklabel string, sfcposition string, dst []string) ([]RoutingInfo, []PodNetworkInfo, error) {
 	var rt []RoutingInfo
 	var pni []PodNetworkInfo
 	var networkname string
@@ -144,7 +303,7 @@ func configurePodSelectorDeployment(ln k8sv1alpha1.RoutingNetwork, sfcEntryPodLa
 
 	if mode != k8sv1alpha1.VirtualMode {
 		if ln.NetworkName != "" {
-			pn, err := k8sv1alpha1Clientset.ProviderNetworks("default").Get(context.TODO(), ln.NetworkName, v1.GetOptions{})
+			pn, err := getProviderNetwork(k8sv1alpha1Clientset, ln.NetworkName)
 			if err != nil {
 				log.Error(err, "Error in getting Provider Networks")
 				return nil, nil, err
@@ -159,46 +318,32 @@ func configurePodSelectorDeployment(ln k8sv1alpha1.RoutingNetwork, sfcEntryPodLa
 	}
 
 	if mode == k8sv1alpha1.VirtualMode {
-		vn, err := k8sv1alpha1Clientset.Networks("default").List(context.TODO(), v1.ListOptions{LabelSelector: networklabel})
+		vn, err := networksFromLabel(k8sv1alpha1Clientset, cr.Namespace, networklabel)
 		if err != nil {
 			log.Error(err, "Error in getting Provider Networks")
 			return nil, nil, err
 		}
 
-		if len(vn.Items) != 1 {
+		if len(vn) != 1 {
 			err := fmt.Errorf("Virtual network is not available for the networklabel - %s", networklabel)
 			log.Error(err, "Error in kube clientset in listing the pods for namespace", "networklabel", networklabel)
 			return nil, nil, err
 		}
 
-		networkname = vn.Items[0].GetName()
+		networkname = vn[0].GetName()
 	}
 
-	pods, err := clientset.CoreV1().Pods("default").List(context.TODO(), v1.ListOptions{LabelSelector: sfcEntryPodLabel})
+	nfns := nfNamespace(cr)
+	entryReady, entryPods, err := CheckPodStatusFromPodLabel(nfns, sfcEntryPodLabel)
 	if err != nil {
-		log.Error(err, "Error in kube clientset in listing the pods for default namespace with label", "sfcEntryPodLabel", sfcEntryPodLabel)
 		return nil, nil, err
 	}
 
-	if len(pods.Items) != 1 {
-		err := fmt.Errorf("Currently load balancing is not supported, expected SFC deployment has only 1 replica")
-		log.Error(err, "Error in kube clientset in listing the pods for namespace", "sfcEntryPodLabel", sfcEntryPodLabel)
-		return nil, nil, err
-	}
-
-	podName := pods.Items[0].GetName()
-
-	for {
-		_, nf, err := checkPodstatus(podName)
-		if err != nil {
-			return nil, nil, err
-		}
-		if nf {
-			break
-		}
+	if !entryReady {
+		return nil, nil, pendingNetworkFunction(nfns, sfcEntryPodLabel)
 	}
 
-	sfcEntryIP, err := ovn.GetIPAdressForPod(networkname, podName)
+	sfcEntryIP, err := replicaGateways(networkname, entryPods)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -213,47 +358,35 @@ func configurePodSelectorDeployment(ln k8sv1alpha1.RoutingNetwork, sfcEntryPodLa
 		defaultRoute = append(defaultRoute, dr)
 	}
 
-	nsLabel := labels.Set(ln.NamespaceSelector.MatchLabels)
-	podLabel := labels.Set(ln.PodSelector.MatchLabels)
-	nslist, err := clientset.CoreV1().Namespaces().List(context.TODO(), v1.ListOptions{LabelSelector: nsLabel.AsSelector().String()})
+	podLabel, err := v1.LabelSelectorAsSelector(&ln.PodSelector)
+	if err != nil {
+		log.Error(err, "Error in the pod selector")
+		return nil, nil, err
+	}
+	namespaces, err := selectedNamespaces(clientset, cr, ln)
 	if err != nil {
 		log.Error(err, "Error in kube clientset in listing the namespaces")
 		return nil, nil, err
 	}
 
-	log.Info("Value of the nslabel", "nslabel", nsLabel)
-	log.Info("Value of the nslabel", "nslist", nslist)
-	for _, ns := range nslist.Items {
-		if ns.GetLabels() == nil {
-			log.Info("The namespace label is empty", "namespace", ns.GetName())
-			continue
-		}
-
-		log.Info("Value of the ns.GetLabels", "ns.GetLabels()", ns.GetLabels())
-		set := labels.Set(ns.GetLabels())
-		log.Info("Value of the nslabel", "set", set)
-		pods, err := clientset.CoreV1().Pods(ns.GetName()).List(context.TODO(), v1.ListOptions{LabelSelector: podLabel.AsSelector().String()})
+	log.Info("Value of the namespaces", "namespaces", namespaces)
+	for _, ns := range namespaces {
+		pods, err := clientset.CoreV1().Pods(ns).List(context.TODO(), v1.ListOptions{LabelSelector: podLabel.String()})
 		if err != nil {
-			log.Error(err, "Error in kube clientset in listing the pods for namespace", "namespace", ns.GetName())
+			log.Error(err, "Error in kube clientset in listing the pods for namespace", "namespace", ns)
 			return nil, nil, err
 		}
 
 		if len(pods.Items) == 0 {
-			log.Info("no pods are avaiable in the namespace", "namespace label", ns.GetName(), "pod label", podLabel.AsSelector().String())
+			log.Info("no pods are avaiable in the namespace", "namespace label", ns, "pod label", podLabel.String())
 			continue
 		}
 
-		var pl *corev1.PodList
-		var ps bool
-		if len(pods.Items) >= 1 {
-			for {
-				pl, ps, err = checkSFCPodSelectorStatus(ns.GetName(), podLabel.AsSelector().String())
-				if err != nil {
-					return nil, nil, err
-				}
-				if ps {
-					break
-				}
+		// The pods not ready yet are configured by the pod controller once they are ready
+		pl := &corev1.PodList{}
+		for _, pod := range pods.Items {
+			if PodReady(&pod) {
+				pl.Items = append(pl.Items, pod)
 			}
 		}
 
@@ -264,7 +397,7 @@ func configurePodSelectorDeployment(ln k8sv1alpha1.RoutingNetwork, sfcEntryPodLa
 
 			log.Info("Value of the pod", "pod", pod.GetName())
 
-			if toDelete != true {
+			if toDelete != true && !refresh {
 				annotation := pod.GetAnnotations()
 				sfcValue, ok := annotation[SFCannotationTag]
 				if ok {
@@ -280,6 +413,9 @@ func configurePodSelectorDeployment(ln k8sv1alpha1.RoutingNetwork, sfcEntryPodLa
 					log.Error(err, "Error getting pod network", "network", networkname)
 					return nil, nil, err
 				}
+				if refresh {
+					continue
+				}
 				netinfo, err = AddPodNetworkAnnotations(pod, networkname, toDelete)
 				if err != nil {
 					log.Error(err, "Error in adding the network pod annotations")
@@ -309,7 +445,7 @@ func configurePodSelectorDeployment(ln k8sv1alpha1.RoutingNetwork, sfcEntryPodLa
 				p.Route = append(p.Route, defaultRoute...)
 				pni = append(pni, p)
 			}
-			if toDelete != true {
+			if toDelete != true && !refresh {
 				kubecli := &kube.Kube{KClient: clientset}
 				key := SFCannotationTag
 				value := SFCcreated
@@ -324,36 +460,24 @@ func configurePodSelectorDeployment(ln k8sv1alpha1.RoutingNetwork, sfcEntryPodLa
 	return rt, pni, nil
 }
 
-// Calcuate route to get to left and right edge networks and other networks (not adjacent) in the chain
-func calculateDeploymentRoutes(namespace, label string, pos int, num int, ln []k8sv1alpha1.RoutingNetwork, rn []k8sv1alpha1.RoutingNetwork, networkList, deploymentList []string) (r RoutingInfo, err error) {
+// Calcuate route to get to left and right edge networks and other networks (not adjacent) in the chain.
+// The routes are the same for the replicas of the network function, the next neighbours with several
+// replicas are reached with ECMP routes
+func calculateDeploymentRoutes(namespace, label string, pos int, num int, ln []k8sv1alpha1.RoutingNetwork, rn []k8sv1alpha1.RoutingNetwork, networkList, deploymentList []string) (rt []RoutingInfo, err error) {
 
 	var nextLeftIP string
 	var nextRightIP string
+	var r RoutingInfo
 
 	r.Namespace = namespace
-	// Get a config to talk to the apiserver
-	k, err := kube.GetKubeConfig()
-	if err != nil {
-		log.Error(err, "Error in kube clientset")
-		return RoutingInfo{}, err
-	}
-
-	lo := v1.ListOptions{LabelSelector: label}
-	// List the Pods matching the Labels
-	pods, err := k.CoreV1().Pods(namespace).List(context.TODO(), lo)
+	pods, err := readyReplicas(namespace, label)
 	if err != nil {
 		log.Error(err, "Deloyment with label not found", "label", label)
-		return RoutingInfo{}, err
+		return nil, err
 	}
-	// LOADBALANCER NOT YET SUPPORTED - Assuming deployment has only one Pod
-	if len(pods.Items) <= 0 {
-		log.Error(err, "Deloyment with label not found", "label", label)
-		return RoutingInfo{}, fmt.Errorf("Pod not found")
+	if len(pods) <= 0 {
+		return nil, pendingNetworkFunction(namespace, label)
 	}
-	// Get the containerID of the first container
-	r.Id = strings.TrimPrefix(pods.Items[0].Status.ContainerStatuses[0].ContainerID, "docker://")
-	r.Name = pods.Items[0].GetName()
-	r.Node = pods.Items[0].Spec.NodeName
 
 	// Calcluate IP addresses for next neighbours on left
 	for _, l := range ln {
@@ -362,19 +486,19 @@ func calculateDeploymentRoutes(namespace, label string, pos int, num int, ln []k
 			nextLeftIP = l.GatewayIP
 		} else {
 			log.Info("Value of deployment pod label", "network function pod label", deploymentList[pos-1])
-			podrunningState, podname, err := CheckPodStatusFromPodLabel(deploymentList[pos-1])
+			podrunningState, podnames, err := CheckPodStatusFromPodLabel(namespace, deploymentList[pos-1])
 			if err != nil {
 				log.Error(err, "Error in pod deployment with pod label", "label", deploymentList[pos-1])
-				return RoutingInfo{}, err
+				return nil, err
 			}
 
 			if podrunningState == true {
-				nextLeftIP, err = ovn.GetIPAdressForPod(networkList[pos-1], podname)
+				nextLeftIP, err = replicaGateways(networkList[pos-1], podnames)
 				if err != nil {
-					return RoutingInfo{}, err
+					return nil, err
 				}
 			} else {
-				return RoutingInfo{}, fmt.Errorf("Error in getting network function podname with pod label -%v", deploymentList[pos-1])
+				return nil, pendingNetworkFunction(namespace, deploymentList[pos-1])
 			}
 		}
 		routeinfo.GW = nextLeftIP
@@ -390,19 +514,19 @@ func calculateDeploymentRoutes(namespace, label string, pos int, num int, ln []k
 			break
 		} else {
 			log.Info("Value of deployment pod label", "network function pod label", deploymentList[pos+1])
-			podrunningState, podname, err := CheckPodStatusFromPodLabel(deploymentList[pos+1])
+			podrunningState, podnames, err := CheckPodStatusFromPodLabel(namespace, deploymentList[pos+1])
 			if err != nil {
 				log.Error(err, "Error in pod deployment with pod label", "label", deploymentList[pos+1])
-				return RoutingInfo{}, err
+				return nil, err
 			}
 
 			if podrunningState == true {
-				nextRightIP, err = ovn.GetIPAdressForPod(networkList[pos], podname)
+				nextRightIP, err = replicaGateways(networkList[pos], podnames)
 				if err != nil {
-					return RoutingInfo{}, err
+					return nil, err
 				}
 			} else {
-				return RoutingInfo{}, fmt.Errorf("Error in getting network function podname with pod label -%v", deploymentList[pos-1])
+				return nil, pendingNetworkFunction(namespace, deploymentList[pos+1])
 			}
 		}
 		routeinfo.Dst = right.Subnet
@@ -429,31 +553,40 @@ func calculateDeploymentRoutes(namespace, label string, pos int, num int, ln []k
 		if i == pos || i == pos-1 {
 			continue
 		} else {
-			var rt k8sv1alpha1.Route
-			rt.Dst, err = ovn.GetNetworkSubnet(networkList[i])
+			var route k8sv1alpha1.Route
+			route.Dst, err = ovn.GetNetworkSubnet(networkList[i])
 			if err != nil {
-				return RoutingInfo{}, err
+				return nil, err
 			}
 			if i > pos {
-				rt.GW = nextRightIP
+				route.GW = nextRightIP
 			} else {
-				rt.GW = nextLeftIP
+				route.GW = nextLeftIP
 			}
-			r.DynamicNetworkRoutes = append(r.DynamicNetworkRoutes, rt)
+			r.DynamicNetworkRoutes = append(r.DynamicNetworkRoutes, route)
 		}
 	}
 
 	//Add Default Route based on Right Network
-	rt := k8sv1alpha1.Route{
+	dr := k8sv1alpha1.Route{
 		GW:  nextRightIP,
 		Dst: "0.0.0.0",
 	}
-	r.DynamicNetworkRoutes = append(r.DynamicNetworkRoutes, rt)
-	return
+	r.DynamicNetworkRoutes = append(r.DynamicNetworkRoutes, dr)
+
+	// Get the containerID of the first container of each replica
+	for _, pod := range pods {
+		replica := r
+		replica.Id = strings.TrimPrefix(pod.Status.ContainerStatuses[0].ContainerID, "docker://")
+		replica.Name = pod.GetName()
+		replica.Node = pod.Spec.NodeName
+		rt = append(rt, replica)
+	}
+	return rt, nil
 }
 
 //CheckNetFromLabel return
-func CheckNetFromLabel(label string) error {
+func CheckNetFromLabel(label, namespace string) error {
 	var err error
 	k8sv1alpha1Clientset, err := kube.GetKubev1alpha1Config()
 	if err != nil {
@@ -461,17 +594,17 @@ func CheckNetFromLabel(label string) error {
 		return err
 	}
 
-	net, err := k8sv1alpha1Clientset.Networks("default").List(context.TODO(), v1.ListOptions{LabelSelector: label})
+	net, err := networksFromLabel(k8sv1alpha1Clientset, namespace, label)
 	if err != nil {
 		log.Error(err, "Error in kube clientset in listing the namespaces")
 		return err
 	}
 
-	if len(net.Items) == 0 {
+	if len(net) == 0 {
 		log.Info("Network for the label-%s doesn't exist, check network pools for virtual net creation", "network label", label)
 		networkname := label[len("net="):]
 		th := "sfc"
-		err := network.CreateNetworkFromPool(networkname, th)
+		err := network.CreateNetworkFromPool(networkname, "", namespace, th)
 		if err != nil {
 			log.Error(err, "Error in creating network from network pools")
 			return err
@@ -489,7 +622,7 @@ func CheckNetForNetPool(cr *k8sv1alpha1.NetworkChaining) error {
 
 	for _, label := range chains {
 		if strings.Compare("net", label[:len("net")]) == 0 {
-			err := CheckNetFromLabel(label)
+			err := CheckNetFromLabel(label, cr.Namespace)
 			if err != nil {
 				log.Error(err, "Error in checking the net labels")
 				return fmt.Errorf("Error in Checking network in Network pool-%v", err)
@@ -586,19 +719,19 @@ func ValidateNetworkChaining(cr *k8sv1alpha1.NetworkChaining) (string, error) {
 		return "", err
 	}
 
-	sfcheadnet, err := k8sv1alpha1Clientset.Networks("default").List(context.TODO(), v1.ListOptions{LabelSelector: chains[0]})
+	sfcheadnet, err := networksFromLabel(k8sv1alpha1Clientset, cr.Namespace, chains[0])
 	if err != nil {
 		log.Error(err, "Error in kube clientset in listing the namespaces")
 		return "", err
 	}
 
-	sfctailnet, err := k8sv1alpha1Clientset.Networks("default").List(context.TODO(), v1.ListOptions{LabelSelector: chains[len(chains)-1]})
+	sfctailnet, err := networksFromLabel(k8sv1alpha1Clientset, cr.Namespace, chains[len(chains)-1])
 	if err != nil {
 		log.Error(err, "Error in kube clientset in listing the namespaces")
 		return "", err
 	}
 
-	if (len(sfcheadnet.Items) != 0) && (len(sfctailnet.Items) != 0) {
+	if (len(sfcheadnet) != 0) && (len(sfctailnet) != 0) {
 		mode = k8sv1alpha1.VirtualMode
 	}
 
@@ -606,7 +739,7 @@ func ValidateNetworkChaining(cr *k8sv1alpha1.NetworkChaining) (string, error) {
 }
 
 //ConfigureNetworkFromLabel return ...
-func configureNetworkFromLabel(label string) (r k8sv1alpha1.RoutingNetwork, err error) {
+func configureNetworkFromLabel(namespace, label string) (r k8sv1alpha1.RoutingNetwork, err error) {
 	var route k8sv1alpha1.RoutingNetwork
 
 	k8sv1alpha1Clientset, err := kube.GetKubev1alpha1Config()
@@ -615,20 +748,20 @@ func configureNetworkFromLabel(label string) (r k8sv1alpha1.RoutingNetwork, err
 		return k8sv1alpha1.RoutingNetwork{}, err
 	}
 
-	net, err := k8sv1alpha1Clientset.Networks("default").List(context.TODO(), v1.ListOptions{LabelSelector: label})
+	net, err := networksFromLabel(k8sv1alpha1Clientset, namespace, label)
 	if err != nil {
 		log.Error(err, "Error in kube clientset in listing the namespaces")
 		return k8sv1alpha1.RoutingNetwork{}, err
 	}
 
-	if len(net.Items) != 1 {
+	if len(net) != 1 {
 		err := fmt.Errorf("Virtual network is not available for the networklabel - %s", label)
 		log.Error(err, "Error in kube clientset in listing the pods for namespace", "networklabel")
 		return k8sv1alpha1.RoutingNetwork{}, err
 	}
 
-	route.NetworkName = net.Items[0].GetName()
-	ipv4Subnets := net.Items[0].Spec.Ipv4Subnets
+	route.NetworkName = net[0].GetName()
+	ipv4Subnets := net[0].Spec.Ipv4Subnets
 	route.GatewayIP = ipv4Subnets[0].ExcludeIps
 	route.Subnet = ipv4Subnets[0].Subnet
 
@@ -653,27 +786,9 @@ func noSFCrequired(clientset *kubernetes.Clientset, podname string, podnamespace
 	return nil
 }
 
-func compareEachLabel(a map[string]string, b map[string]string) bool {
-	var isEqual bool
-
-	for akey, aValue := range a {
-		for bkey, bValue := range b {
-			if akey == bkey && aValue == bValue {
-				isEqual = true
-				break
-			}
-			if isEqual == true {
-				break
-			}
-		}
-	}
-	return isEqual
-}
-
 //ConfigureforSFC returns
 func ConfigureforSFC(podname string, podnamespace string) (bool, []PodNetworkInfo, []RoutingInfo, error) {
-	var sfcname string
-	var nl, pl, nr, pr map[string]string
+	var sfcname, sfcnamespace string
 
 	// Get a config to talk to the apiserver
 	clientset, err := kube.GetKubeConfig()
@@ -688,7 +803,7 @@ func ConfigureforSFC(podname string, podnamespace string) (bool, []PodNetworkInf
 		return false, nil, nil, fmt.Errorf("ConfigureforSFC - Error in k8sv1alpha clientset - %v", err)
 	}
 
-	sfc, err := k8sv1alpha1Clientset.NetworkChainings("default").List(context.TODO(), v1.ListOptions{})
+	sfc, err := k8sv1alpha1Clientset.NetworkChainings(v1.NamespaceAll).List(context.TODO(), v1.ListOptions{})
 	if err != nil {
 		return false, nil, nil, fmt.Errorf("ConfigureforSFC - Error in listing the k8sv1alpha network chainings - %v", err)
 	}
@@ -707,21 +822,11 @@ func ConfigureforSFC(podname string, podnamespace string) (bool, []PodNetworkInf
 		return false, nil, nil, nil
 	}
 
-	pdlabel := pod.GetLabels()
 	namespace, err := clientset.CoreV1().Namespaces().Get(context.TODO(), podnamespace, v1.GetOptions{})
 	if err != nil {
 		return false, nil, nil, fmt.Errorf("ConfigureforSFC - Error in getting the pod namespace - %s clientset get options - %v", podnamespace, err)
 	}
 
-	if namespace.GetLabels() == nil {
-		err = noSFCrequired(clientset, podname, podnamespace)
-		if err != nil {
-			log.Error(err, "ConfigureforSFC - error in seting SFC not required")
-		}
-		log.Info("value of namespace label is nil", "namespace name", namespace.GetName())
-		return false, nil, nil, nil
-	}
-
 	if len(sfc.Items) == 0 {
 		log.Info("ConfigureforSFC - No SFC created", "podname", podname)
 		return false, nil, nil, nil
@@ -731,15 +836,13 @@ func ConfigureforSFC(podname string, podnamespace string) (bool, []PodNetworkInf
 
 	}
 
-	pdnslabel := namespace.GetLabels()
 	var isSFCExist bool
 	for _, nc := range sfc.Items {
 		sfcname = nc.GetName()
+		sfcnamespace = nc.GetNamespace()
 		left := nc.Spec.RoutingSpec.LeftNetwork
 		for _, l := range left {
-			pl = l.PodSelector.MatchLabels
-			nl = l.NamespaceSelector.MatchLabels
-			if compareEachLabel(pl, pdlabel) && compareEachLabel(nl, pdnslabel) {
+			if selectsPod(sfcnamespace, l, pod, namespace) {
 				isSFCExist = true
 				break
 			}
@@ -754,11 +857,10 @@ func ConfigureforSFC(podname string, podnamespace string) (bool, []PodNetworkInf
 	if isSFCExist == false {
 		for _, nc := range sfc.Items {
 			sfcname = nc.GetName()
+			sfcnamespace = nc.GetNamespace()
 			right := nc.Spec.RoutingSpec.RightNetwork
 			for _, r := range right {
-				pr = r.PodSelector.MatchLabels
-				nr = r.NamespaceSelector.MatchLabels
-				if compareEachLabel(pr, pdlabel) && compareEachLabel(nr, pdnslabel) {
+				if selectsPod(sfcnamespace, r, pod, namespace) {
 					isSFCExist = true
 					break
 				}
@@ -774,7 +876,7 @@ func ConfigureforSFC(podname string, podnamespace string) (bool, []PodNetworkInf
 		return false, nil, nil, nil
 	}
 
-	cr, err := k8sv1alpha1Clientset.NetworkChainings("default").Get(context.TODO(), sfcname, v1.GetOptions{})
+	cr, err := k8sv1alpha1Clientset.NetworkChainings(sfcnamespace).Get(context.TODO(), sfcname, v1.GetOptions{})
 	if err != nil {
 		return false, nil, nil, fmt.Errorf("ConfigureforSFC - Error in getting the network chaining - %s k8sv1alpha1 clientset get options - %v", sfcname, err)
 	}
@@ -789,7 +891,7 @@ func ConfigureforSFC(podname string, podnamespace string) (bool, []PodNetworkInf
 }
 
 //CalculateDstforTail return ...
-func CalculateDstforTail(networklist []string) ([]string, error) {
+func CalculateDstforTail(namespace string, networklist []string) ([]string, error) {
 	var dst []string
 	var err error
 
@@ -800,7 +902,7 @@ func CalculateDstforTail(networklist []string) ([]string, error) {
 	}
 
 	for _, n := range networklist {
-		net, err := k8sv1alpha1Clientset.Networks("default").Get(context.TODO(), n, v1.GetOptions{})
+		net, err := getNetwork(k8sv1alpha1Clientset, namespace, n)
 		if err != nil {
 			log.Error(err, "Error in kube clientset in listing the namespaces")
 			return nil, err
@@ -813,7 +915,7 @@ func CalculateDstforTail(networklist []string) ([]string, error) {
 }
 
 // DerivedNetworkFromNetworklist returns the network list
-func DerivedNetworkFromNetworklist(networklabellist []string) ([]string, error) {
+func DerivedNetworkFromNetworklist(namespace string, networklabellist []string) ([]string, error) {
 	var networklist []string
 
 	// Get a config to talk to the apiserver
@@ -825,134 +927,35 @@ func DerivedNetworkFromNetworklist(networklabellist []string) ([]string, error)
 
 	for _, networklabel := range networklabellist {
 
-		vn, err := k8sv1alpha1Clientset.Networks("default").List(context.TODO(), v1.ListOptions{LabelSelector: networklabel})
+		vn, err := networksFromLabel(k8sv1alpha1Clientset, namespace, networklabel)
 		if err != nil {
 			log.Error(err, "Error in getting Provider Networks")
 			return nil, err
 		}
 
-		if len(vn.Items) != 1 {
+		if len(vn) != 1 {
 			err := fmt.Errorf("Virtual network is not available for the networklabel - %s", networklabel)
 			log.Error(err, "Error in kube clientset in listing the pods for namespace", "networklabel", networklabel)
 			return nil, err
 		}
 
-		networklist = append(networklist, vn.Items[0].GetName())
+		networklist = append(networklist, vn[0].GetName())
 	}
 
 	return networklist, nil
 }
 
-func checkPodstatus(podname string) (*corev1.Pod, bool, error) {
-	var isPodRunning bool
-
-	clientset, err := kube.GetKubeConfig()
-	if err != nil {
-		log.Error(err, "Error in kube clientset")
-		return nil, false, err
-	}
-
-	pdState, err := clientset.CoreV1().Pods("default").Get(context.TODO(), podname, v1.GetOptions{})
-	if err != nil {
-		log.Error(err, "Error in kube clientset in getting the pod status", "podname", podname)
-		return nil, false, err
-	}
-
-	log.Info("State of pods", "podName", pdState.GetName(), "Status", pdState.Status.Phase)
-
-	if pdState.Status.Phase == corev1.PodRunning {
-		if len(pdState.Status.ContainerStatuses) != 0 {
-			if pdState.Status.ContainerStatuses[0].Ready == true {
-				log.Info("State of pods", "podName", pdState.GetName(), "Status", pdState.Status.Phase, "Container ID", pdState.Status.ContainerStatuses[0].ContainerID, "container Ready", pdState.Status.ContainerStatuses[0].Ready)
-				isPodRunning = true
-			} else {
-				isPodRunning = false
-				log.Info("State of pods", "podName", pdState.GetName(), "Status", pdState.Status.Phase, "Container ID", pdState.Status.ContainerStatuses[0].ContainerID, "container Ready", pdState.Status.ContainerStatuses[0].Ready)
-
-			}
-		} else {
-			isPodRunning = false
-			log.Info("State of pods", "podName", pdState.GetName(), "Status", pdState.Status.Phase, "container status", pdState.Status.ContainerStatuses)
-		}
-	} else {
-		isPodRunning = false
-		log.Info("State of pods", "podName", pdState.GetName(), "Status", pdState.Status.Phase)
-	}
-
-	log.Info("State of pods", "Running State", isPodRunning)
-
-	if isPodRunning != true {
-		return nil, isPodRunning, nil
-	}
-
-	return pdState, isPodRunning, nil
-}
-
-func checkSFCPodSelectorStatus(nslabel, podlabel string) (*corev1.PodList, bool, error) {
-	var isPodRunning bool
-
-	clientset, err := kube.GetKubeConfig()
-	if err != nil {
-		log.Error(err, "Error in kube clientset")
-		return nil, false, err
-	}
-
-	pods, err := clientset.CoreV1().Pods(nslabel).List(context.TODO(), v1.ListOptions{LabelSelector: podlabel})
-	if err != nil {
-		log.Error(err, "Error in kube clientset in listing the pods for default namespace with label", "podlabel", podlabel)
-		return nil, false, err
-	}
-
-	for i, pod := range pods.Items {
-		pdState, err := clientset.CoreV1().Pods(pod.GetNamespace()).Get(context.TODO(), pod.GetName(), v1.GetOptions{})
-		if err != nil {
-			log.Error(err, "Error in kube clientset in getting the pod state for default namespace with label", "podlabel", podlabel, "pdState", pdState)
-			return nil, false, err
-		}
-
-		log.Info("State of PodSelector pods", "index", i, "podName", pod.GetName(), "Status", pdState.Status.Phase)
-
-		if pdState.Status.Phase == corev1.PodRunning {
-			if len(pod.Status.ContainerStatuses) != 0 {
-				if pod.Status.ContainerStatuses[0].Ready == true {
-					log.Info("State of PodSelector pods", "index", i, "podName", pod.GetName(), "Status", pdState.Status.Phase, "", pod.Status.ContainerStatuses[0].ContainerID, "container Ready", pod.Status.ContainerStatuses[0].Ready)
-					isPodRunning = true
-				} else {
-					isPodRunning = false
-					log.Info("Exit the loop - PodSelector pods", "index", i, "podName", pod.GetName(), "Status", pdState.Status.Phase, "", pod.Status.ContainerStatuses[0].ContainerID, "container Ready", pod.Status.ContainerStatuses[0].Ready)
-					break
-				}
-			} else {
-				isPodRunning = false
-				log.Info("Exit the loop, PodSelector pods", "index", i, "podName", pod.GetName(), "Status", pdState.Status.Phase, "container status", pod.Status.ContainerStatuses)
-				break
-			}
-		} else {
-			isPodRunning = false
-			log.Info("Exit the loop, PodSelector pods are not in running state", "index", i, "podName", pod.GetName(), "Status", pdState.Status.Phase)
-			break
-		}
-	}
-
-	log.Info("PodSelector Pods status", "Running State", isPodRunning)
-
-	if isPodRunning != true {
-		return nil, isPodRunning, nil
-	}
-
-	return pods, isPodRunning, nil
-}
-
-// CheckSFCPodLabelStatus returns true, if all the pods in the SFC are up and running
-func CheckSFCPodLabelStatus(cr *k8sv1alpha1.NetworkChaining) (bool, error) {
+// CheckSFCPodLabelStatus returns true, if a replica of each network function in the SFC is ready.
+// Otherwise it returns the reason the chain is pending, naming the network functions not ready
+func CheckSFCPodLabelStatus(cr *k8sv1alpha1.NetworkChaining) (bool, string, error) {
 	var deploymentList []string
-	var isPodRunning bool
+	var pending []string
 
 	chains := strings.Split(cr.Spec.RoutingSpec.NetworkChain, ",")
 
 	mode, err := ValidateNetworkChaining(cr)
 	if err != nil {
-		return false, err
+		return false, "", err
 	}
 
 	if mode == k8sv1alpha1.VirtualMode {
@@ -967,57 +970,42 @@ func CheckSFCPodLabelStatus(cr *k8sv1alpha1.NetworkChaining) (bool, error) {
 		i++
 	}
 
+	nfns := nfNamespace(cr)
 	for j, sfcpodlabel := range deploymentList {
-		// Get a config to talk to the apiserver
-		clientset, err := kube.GetKubeConfig()
+		pods, err := readyReplicas(nfns, sfcpodlabel)
 		if err != nil {
-			log.Error(err, "Error in kube clientset")
-			return false, err
-		}
-
-		pods, err := clientset.CoreV1().Pods("default").List(context.TODO(), v1.ListOptions{LabelSelector: sfcpodlabel})
-		if err != nil {
-			log.Error(err, "Error in kube clientset in listing the pods for default namespace with label", "sfcpodlabel", sfcpodlabel)
-			return false, err
-		}
-
-		if len(pods.Items) == 0 {
-			log.Info("No pod is created for the sfc podlabel", "sfcpodlabel", sfcpodlabel)
-			return false, err
-		}
-
-		if len(pods.Items) != 1 {
-			err := fmt.Errorf("Currently load balancing is not supported, expected SFC deployment has only 1 replica")
-			log.Error(err, "currently load balancing is not supported, expected SFC deployment has only 1 replica", "sfcpodlabel", sfcpodlabel)
-			return false, err
-		}
-
-		podName := pods.Items[0].GetName()
-
-		pdState, err := clientset.CoreV1().Pods("default").Get(context.TODO(), podName, v1.GetOptions{})
-		if err != nil {
-			log.Error(err, "Error in kube clientset in getting the pod state for default namespace with label", "sfcpodlabel", sfcpodlabel, "pdState", pdState)
-			return false, err
+			return false, "", err
 		}
 
-		log.Info("State of the Pod", "index", j, "podName", podName, "Status", pdState.Status.Phase)
+		log.V(1).Info("Ready replicas of the network function", "index", j, "sfcpodlabel", sfcpodlabel, "replicas", len(pods))
 
-		if pdState.Status.Phase == corev1.PodRunning {
-			isPodRunning = true
-		} else {
-			isPodRunning = false
-			log.Info("Exit the loop, sfc pods are not in running state", "index", j, "podName", podName, "Status", pdState.Status.Phase)
-			break
+		if len(pods) == 0 {
+			pending = append(pending, sfcpodlabel)
 		}
 	}
 
-	log.Info("SFC Pods status", "Running State", isPodRunning)
+	if len(pending) != 0 {
+		reason := fmt.Sprintf("no ready replica of the network functions %s in namespace %s", strings.Join(pending, ", "), nfns)
+		log.Info("SFC Pods status", "reason", reason)
+		return false, reason, nil
+	}
 
-	return isPodRunning, nil
+	return true, "", nil
 }
 
 // CalculateRoutes returns the routing info
 func CalculateRoutes(cr *k8sv1alpha1.NetworkChaining, cs bool, onlyPodSelector bool) ([]PodNetworkInfo, []RoutingInfo, error) {
+	return calculateRoutes(cr, cs, onlyPodSelector, false)
+}
+
+// RecalculateRoutes returns the routing info of the ready pods of a created chain, without
+// configuring the pods not attached to the chain yet
+func RecalculateRoutes(cr *k8sv1alpha1.NetworkChaining) ([]RoutingInfo, error) {
+	_, rt, err := calculateRoutes(cr, false, false, true)
+	return rt, err
+}
+
+func calculateRoutes(cr *k8sv1alpha1.NetworkChaining, cs bool, onlyPodSelector bool, refresh bool) ([]PodNetworkInfo, []RoutingInfo, error) {
 	var deploymentList []string
 	var networklabelList []string
 	var sfctaillabel, sfcheadlabel string
@@ -1065,7 +1053,7 @@ func CalculateRoutes(cr *k8sv1alpha1.NetworkChaining, cs bool, onlyPodSelector b
 	}
 	num := len(deploymentList)
 
-	networkList, err := DerivedNetworkFromNetworklist(networklabelList)
+	networkList, err := DerivedNetworkFromNetworklist(cr.Namespace, networklabelList)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1084,7 +1072,7 @@ func CalculateRoutes(cr *k8sv1alpha1.NetworkChaining, cs bool, onlyPodSelector b
 		//For the sfc head dst will be default
 		ldst = append(ldst, "0.0.0.0")
 
-		r, pni, err := configurePodSelectorDeployment(leftNetworks, deploymentList[0], cs, mode, sfcheadlabel, SFCHead, ldst)
+		r, pni, err := configurePodSelectorDeployment(cr, leftNetworks, deploymentList[0], cs, refresh, mode, sfcheadlabel, SFCHead, ldst)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -1096,13 +1084,13 @@ func CalculateRoutes(cr *k8sv1alpha1.NetworkChaining, cs bool, onlyPodSelector b
 	chainRoutingInfo = append(chainRoutingInfo, lnRoutingInfo...)
 
 	if mode == k8sv1alpha1.VirtualMode {
-		l, err := configureNetworkFromLabel(sfcheadlabel)
+		l, err := configureNetworkFromLabel(cr.Namespace, sfcheadlabel)
 		if err != nil {
 			return nil, nil, err
 		}
 		ln = append(ln, l)
 
-		r, err := configureNetworkFromLabel(sfctaillabel)
+		r, err := configureNetworkFromLabel(cr.Namespace, sfctaillabel)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -1111,7 +1099,7 @@ func CalculateRoutes(cr *k8sv1alpha1.NetworkChaining, cs bool, onlyPodSelector b
 		log.Info("List of rn", "rn", rn)
 	}
 
-	taildst, err := CalculateDstforTail(networkList)
+	taildst, err := CalculateDstforTail(cr.Namespace, networkList)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1145,7 +1133,7 @@ func CalculateRoutes(cr *k8sv1alpha1.NetworkChaining, cs bool, onlyPodSelector b
 		rdst = append(rdst, taildst...)
 		log.Info("list of the after rdst", "rdst", rdst)
 
-		r, pni, err := configurePodSelectorDeployment(rightNetworks, deploymentList[num-1], cs, mode, sfctaillabel, SFCTail, rdst)
+		r, pni, err := configurePodSelectorDeployment(cr, rightNetworks, deploymentList[num-1], cs, refresh, mode, sfctaillabel, SFCTail, rdst)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -1154,7 +1142,7 @@ func CalculateRoutes(cr *k8sv1alpha1.NetworkChaining, cs bool, onlyPodSelector b
 		podsNetworkInfo = append(podsNetworkInfo, pni...)
 	}
 
-	chainRoutingInfo = append(chainRoutingInfo, lnRoutingInfo...)
+	chainRoutingInfo = append(chainRoutingInfo, rnRoutingInfo...)
 
 	var lnconf []k8sv1alpha1.RoutingNetwork
 	var rnconf []k8sv1alpha1.RoutingNetwork
@@ -1184,11 +1172,11 @@ func CalculateRoutes(cr *k8sv1alpha1.NetworkChaining, cs bool, onlyPodSelector b
 
 	if onlyPodSelector != true {
 		for i, deployment := range deploymentList {
-			r, err := calculateDeploymentRoutes(cr.Namespace, deployment, i, num, lnconf, rnconf, networkList, deploymentList)
+			r, err := calculateDeploymentRoutes(nfNamespace(cr), deployment, i, num, lnconf, rnconf, networkList, deploymentList)
 			if err != nil {
 				return nil, nil, err
 			}
-			chainRoutingInfo = append(chainRoutingInfo, r)
+			chainRoutingInfo = append(chainRoutingInfo, r...)
 		}
 	}
 
@@ -1197,8 +1185,8 @@ func CalculateRoutes(cr *k8sv1alpha1.NetworkChaining, cs bool, onlyPodSelector b
 }
 
 //ContainerAddInteface return
-func ContainerAddInteface(containerPid int, payload *pb.PodAddNetwork) error {
-	klog.Infof("Value of ContainerAddInteface containerPid - %v", containerPid)
+func ContainerAddInteface(netns string, payload *pb.PodAddNetwork) error {
+	klog.Infof("Value of ContainerAddInteface netns - %v", netns)
 	klog.Infof("Value of ContainerAddInteface payload.GetNet() - %v", payload.GetNet())
 	klog.Infof("Value of ContainerAddInteface payload.GetPod() - %v", payload.GetPod())
 	klog.Infof("Value of ContainerAddInteface payload.GetRoute()- %v", payload.GetRoute())
@@ -1214,31 +1202,40 @@ func ContainerAddInteface(containerPid int, payload *pb.PodAddNetwork) error {
 		return fmt.Errorf("Error in unmarshal podnet conf=%v", err)
 	}
 
-	if len(nets) != 1 {
-		return fmt.Errorf("Only support one interface addition")
-	}
+	for _, net := range nets {
+		// The interfaces are replayed when the agent resyncs with the operator
+		if containerHasInterface(netns, net.Interface) {
+			klog.Infof("Interface %s already present in the pod %s/%s", net.Interface, podinfo.Namespace, podinfo.Name)
+			continue
+		}
 
-	cnishimreq := &cniserver.CNIServerRequest{
-		Command:      cniserver.CNIAdd,
-		PodNamespace: podinfo.Namespace,
-		PodName:      podinfo.Name,
-		SandboxID:    config.GeneratePodNameID(podinfo.Name),
-		Netns:        fmt.Sprintf("/proc/%d/ns/net", containerPid),
-		IfName:       nets[len(nets)-1].Interface,
-		CNIConf:      nil,
-	}
+		data, err := json.Marshal([]cniserver.OvnNetwork{net})
+		if err != nil {
+			return fmt.Errorf("Error in marshal podnet conf=%v", err)
+		}
+
+		cnishimreq := &cniserver.CNIServerRequest{
+			Command:      cniserver.CNIAdd,
+			PodNamespace: podinfo.Namespace,
+			PodName:      podinfo.Name,
+			SandboxID:    hotplugSandboxID(podinfo.Name, net.Interface),
+			Netns:        netns,
+			IfName:       net.Interface,
+			CNIConf:      nil,
+		}
 
-	result := cnishimreq.AddMultipleInterfaces("", podnetconf.Data, podinfo.Namespace, podinfo.Name)
-	if result == nil {
-		return fmt.Errorf("result is nil from cni server for adding interface in the existing pod")
+		result := cnishimreq.AddMultipleInterfaces("", string(data), podinfo.Namespace, podinfo.Name)
+		if result == nil {
+			return fmt.Errorf("result is nil from cni server for adding interface %s in the existing pod", net.Interface)
+		}
 	}
 
 	return nil
 }
 
 //ContainerDelInteface return
-func ContainerDelInteface(containerPid int, payload *pb.PodDelNetwork) error {
-	klog.Infof("Value of ContainerDelInteface containerPid - %v", containerPid)
+func ContainerDelInteface(netns string, payload *pb.PodDelNetwork) error {
+	klog.Infof("Value of ContainerDelInteface netns - %v", netns)
 	klog.Infof("Value of ContainerDelInteface payload.GetNet() - %v", payload.GetNet())
 	klog.Infof("Value of ContainerDelInteface payload.GetPod() - %v", payload.GetPod())
 	klog.Infof("Value of ContainerDelInteface payload.GetRoute()- %v", payload.GetRoute())
@@ -1252,31 +1249,48 @@ func ContainerDelInteface(containerPid int, payload *pb.PodDelNetwork) error {
 		return fmt.Errorf("Error in unmarshal podnet conf=%v", err)
 	}
 
-	if len(nets) != 1 {
-		return fmt.Errorf("Only support one interface addition")
-	}
+	for _, net := range nets {
+		data, err := json.Marshal([]cniserver.OvnNetwork{net})
+		if err != nil {
+			return fmt.Errorf("Error in marshal podnet conf=%v", err)
+		}
 
-	cnishimreq := &cniserver.CNIServerRequest{
-		Command:      cniserver.CNIDel,
-		PodNamespace: podinfo.Namespace,
-		PodName:      podinfo.Name,
-		SandboxID:    config.GeneratePodNameID(podinfo.Name),
-		Netns:        fmt.Sprintf("/proc/%d/ns/net", containerPid),
-		IfName:       nets[len(nets)-1].Interface,
-		CNIConf:      nil,
-	}
+		cnishimreq := &cniserver.CNIServerRequest{
+			Command:      cniserver.CNIDel,
+			PodNamespace: podinfo.Namespace,
+			PodName:      podinfo.Name,
+			SandboxID:    hotplugSandboxID(podinfo.Name, net.Interface),
+			Netns:        netns,
+			IfName:       net.Interface,
+			CNIConf:      nil,
+		}
 
-	err = cnishimreq.DeleteMultipleInterfaces(podnetconf.Data, podinfo.Namespace, podinfo.Name)
-	if err != nil {
-		return fmt.Errorf("cni server for deleting interface in the existing pod=%v", err)
+		err = cnishimreq.DeleteMultipleInterfaces(string(data), podinfo.Namespace, podinfo.Name)
+		if err != nil {
+			return fmt.Errorf("cni server for deleting interface %s in the existing pod=%v", net.Interface, err)
+		}
 	}
 
 	return nil
 }
 
-// ContainerDelRoute return containerPid and route
-func ContainerDelRoute(containerPid int, route []*pb.RouteData) error {
-	str := fmt.Sprintf("/proc/%d/ns/net", containerPid)
+// containerHasInterface returns true if the interface exists in the network namespace
+func containerHasInterface(netns, ifName string) bool {
+	err := ns.WithNetNSPath(netns, func(_ ns.NetNS) error {
+		_, err := netlink.LinkByName(ifName)
+		return err
+	})
+	return err == nil
+}
+
+// hotplugSandboxID returns the ID used to name the host end of an interface added to a running pod,
+// the interface name keeps the host veth names unique when several interfaces are added
+func hotplugSandboxID(podName, ifName string) string {
+	return config.GeneratePodNameID(podName + "_" + ifName)
+}
+
+// ContainerDelRoute deletes the routes in the network namespace of the container
+func ContainerDelRoute(netns string, route []*pb.RouteData) error {
 
 	hostNet, err := network.GetHostNetwork()
 	if err != nil {
@@ -1297,9 +1311,9 @@ func ContainerDelRoute(containerPid int, route []*pb.RouteData) error {
 		return err
 	}
 
-	nms, err := ns.GetNS(str)
+	nms, err := ns.GetNS(netns)
 	if err != nil {
-		log.Error(err, "Failed namesapce", "containerID", containerPid)
+		log.Error(err, "Failed namesapce", "netns", netns)
 		return err
 	}
 	defer nms.Close()
@@ -1338,6 +1352,19 @@ func ContainerDelRoute(containerPid int, route []*pb.RouteData) error {
 					log.Error(err, "Failed to ip route replace", "stdout", stdout, "stderr", stderr)
 					return err
 				}
+			} else if len(routeGateways(gw)) > 1 {
+				routes, err := netlink.RouteList(nil, netlink.FAMILY_ALL)
+				if err != nil {
+					log.Error(err, "Failed to list the routes")
+					return err
+				}
+				if hasRoute(routes, dst, gw) {
+					stdout, stderr, err := ovn.RunIP("route", "del", dst)
+					if err != nil {
+						log.Error(err, "Failed to ip route del", "stdout", stdout, "stderr", stderr)
+						return err
+					}
+				}
 			} else {
 				isExist, err := network.IsRouteExist(dst, gw)
 				if err != nil {
@@ -1356,15 +1383,14 @@ func ContainerDelRoute(containerPid int, route []*pb.RouteData) error {
 		return nil
 	})
 	if err != nil {
-		log.Error(err, "Failed Netns Do", "containerID", containerPid)
+		log.Error(err, "Failed Netns Do", "netns", netns)
 		return err
 	}
 	return nil
 }
 
-// ContainerAddRoute return containerPid and route
-func ContainerAddRoute(containerPid int, route []*pb.RouteData) error {
-	str := fmt.Sprintf("/proc/%d/ns/net", containerPid)
+// ContainerAddRoute adds the routes in the network namespace of the container
+func ContainerAddRoute(netns string, route []*pb.RouteData) error {
 
 	hostNet, err := network.GetHostNetwork()
 	if err != nil {
@@ -1385,9 +1411,9 @@ func ContainerAddRoute(containerPid int, route []*pb.RouteData) error {
 		return err
 	}
 
-	nms, err := ns.GetNS(str)
+	nms, err := ns.GetNS(netns)
 	if err != nil {
-		log.Error(err, "Failed namesapce", "containerID", containerPid)
+		log.Error(err, "Failed namesapce", "netns", netns)
 		return err
 	}
 	defer nms.Close()
@@ -1416,9 +1442,26 @@ func ContainerAddRoute(containerPid int, route []*pb.RouteData) error {
 			return err
 		}
 
+		// the multipath hash policies of the IP families of the ECMP routes
+		hashPolicies := make(map[string]bool)
 		for _, r := range route {
 			dst := r.GetDst()
 			gw := r.GetGw()
+			gws := routeGateways(gw)
+			if len(gws) > 1 {
+				// ECMP route over the replicas of a network function, replacing the previous one
+				hashPolicies[multipathHashPolicy(gws[0])] = true
+				if dst == "0.0.0.0" {
+					dst = "default"
+				}
+				args := append([]string{"route", "replace", dst}, nexthopArgs(gws)...)
+				stdout, stderr, err := ovn.RunIP(args...)
+				if err != nil {
+					log.Error(err, "Failed to ip route replace", "stdout", stdout, "stderr", stderr)
+					return err
+				}
+				continue
+			}
 			// Replace default route
 			if dst == "0.0.0.0" {
 				stdout, stderr, err := ovn.RunIP("route", "replace", "default", "via", gw)
@@ -1428,21 +1471,162 @@ func ContainerAddRoute(containerPid int, route []*pb.RouteData) error {
 				}
 			} else {
 				stdout, stderr, err := ovn.RunIP("route", "add", dst, "via", gw)
+				if err != nil && strings.Contains(stderr, "RTNETLINK answers: File exists") && hasGatewayRoute(dst) {
+					// The gateways of the route changed, the connected routes are left alone
+					stdout, stderr, err = ovn.RunIP("route", "replace", dst, "via", gw)
+				}
 				if err != nil && !strings.Contains(stderr, "RTNETLINK answers: File exists") {
 					log.Error(err, "Failed to ip route add", "stdout", stdout, "stderr", stderr)
 					return err
 				}
 			}
 		}
+		for policy := range hashPolicies {
+			// Hash the flows on their L4 fields, the packets of a flow go through the same replica
+			stdout, stderr, err := ovn.RunSysctl("-w", policy+"=1")
+			if err != nil {
+				log.Error(err, "Failed to set the multipath hash policy", "stdout", stdout.String(), "stderr", stderr.String())
+				return err
+			}
+		}
 		return nil
 	})
 	if err != nil {
-		log.Error(err, "Failed Netns Do", "containerID", containerPid)
+		log.Error(err, "Failed Netns Do", "netns", netns)
 		return err
 	}
 	return nil
 }
 
+// ContainerMissingRoutes returns the routes not present in the network namespace of the container
+func ContainerMissingRoutes(netns string, route []*pb.RouteData) ([]*pb.RouteData, error) {
+	var missing []*pb.RouteData
+	nms, err := ns.GetNS(netns)
+	if err != nil {
+		return nil, err
+	}
+	defer nms.Close()
+	err = nms.Do(func(_ ns.NetNS) error {
+		routes, err := netlink.RouteList(nil, netlink.FAMILY_ALL)
+		if err != nil {
+			return err
+		}
+		for _, r := range route {
+			if !hasRoute(routes, r.GetDst(), r.GetGw()) {
+				missing = append(missing, r)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// routeGateways returns the gateways of a route, several for an ECMP route
+func routeGateways(gw string) []string {
+	var gws []string
+	for _, g := range strings.Split(gw, GatewaySeparator) {
+		if g = strings.TrimSpace(g); g != "" {
+			gws = append(gws, g)
+		}
+	}
+	return gws
+}
+
+// multipathHashPolicy returns the sysctl of the multipath hash policy of the IP family of the gateway
+func multipathHashPolicy(gw string) string {
+	if strings.Contains(gw, ":") {
+		return "net.ipv6.fib_multipath_hash_policy"
+	}
+	return "net.ipv4.fib_multipath_hash_policy"
+}
+
+// nexthopArgs returns the ip route arguments of the next hops of an ECMP route
+func nexthopArgs(gws []string) []string {
+	var args []string
+	for _, gw := range gws {
+		args = append(args, "nexthop", "via", gw)
+	}
+	return args
+}
+
+// routeDst returns the subnet of the route destination, nil for the default route 0.0.0.0
+func routeDst(dst string) (*net.IPNet, bool) {
+	if dst == "0.0.0.0" {
+		return nil, true
+	}
+	_, ipNet, err := net.ParseCIDR(dst)
+	if err != nil {
+		ip := net.ParseIP(dst)
+		if ip == nil {
+			return nil, false
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		ipNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}
+	}
+	return ipNet, true
+}
+
+// sameDst checks if the route goes to the destination subnet, a nil subnet is the default route
+func sameDst(r netlink.Route, dstNet *net.IPNet) bool {
+	if dstNet == nil {
+		return r.Dst == nil || r.Dst.String() == "0.0.0.0/0"
+	}
+	return r.Dst != nil && r.Dst.String() == dstNet.String()
+}
+
+// hasGatewayRoute checks if there is a route to dst through gateways, as opposed to a connected route
+func hasGatewayRoute(dst string) bool {
+	dstNet, ok := routeDst(dst)
+	if !ok {
+		return false
+	}
+	routes, err := netlink.RouteList(nil, netlink.FAMILY_ALL)
+	if err != nil {
+		return false
+	}
+	for _, r := range routes {
+		if sameDst(r, dstNet) && (r.Gw != nil || len(r.MultiPath) != 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRoute checks if the route to dst via gw is in the routes, dst 0.0.0.0 is the default route.
+// The gateways of an ECMP route are the next hops of a multipath route
+func hasRoute(routes []netlink.Route, dst, gw string) bool {
+	dstNet, ok := routeDst(dst)
+	if !ok {
+		return false
+	}
+	gws := routeGateways(gw)
+	for _, r := range routes {
+		if !sameDst(r, dstNet) {
+			continue
+		}
+		if len(gws) == 1 && r.Gw.Equal(net.ParseIP(gws[0])) {
+			return true
+		}
+		if len(gws) > 1 && len(r.MultiPath) == len(gws) {
+			matched := true
+			for _, nh := range r.MultiPath {
+				if !utils.Contains(gws, nh.Gw.String()) {
+					matched = false
+				}
+			}
+			if matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func GetPidForContainer(id string) (int, error) {
 	cli, err := client.NewEnvClient()
 	if err != nil {
@@ -0,0 +1,90 @@
+package nfn
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+)
+
+func TestChaining(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Chaining Test Suite")
+}
+
+// route returns the netlink route to dst through the gateways, a multipath route if there are
+// several gateways
+func route(dst string, gws ...string) netlink.Route {
+	r := netlink.Route{}
+	if dst != "" {
+		_, r.Dst, _ = net.ParseCIDR(dst)
+	}
+	if len(gws) == 1 {
+		r.Gw = net.ParseIP(gws[0])
+		return r
+	}
+	for _, gw := range gws {
+		r.MultiPath = append(r.MultiPath, &netlink.NexthopInfo{Gw: net.ParseIP(gw)})
+	}
+	return r
+}
+
+var _ = Describe("Test chain routes", func() {
+	table.DescribeTable("splits the gateways of the routes",
+		func(gw string, gws []string) {
+			Expect(routeGateways(gw)).To(Equal(gws))
+		},
+		table.Entry("single gateway", "172.30.10.3", []string{"172.30.10.3"}),
+		table.Entry("gateways of the replicas", "172.30.10.3,172.30.10.4", []string{"172.30.10.3", "172.30.10.4"}),
+		table.Entry("spaces and empty gateways", " 172.30.10.3 ,,172.30.10.4,", []string{"172.30.10.3", "172.30.10.4"}),
+		table.Entry("no gateway", "", nil),
+	)
+
+	table.DescribeTable("builds the next hops of the ECMP routes",
+		func(gws []string, args []string) {
+			Expect(nexthopArgs(gws)).To(Equal(args))
+		},
+		table.Entry("two replicas", []string{"172.30.10.3", "172.30.10.4"},
+			[]string{"nexthop", "via", "172.30.10.3", "nexthop", "via", "172.30.10.4"}),
+		table.Entry("IPv6 replicas", []string{"fd00:10::3", "fd00:10::4"},
+			[]string{"nexthop", "via", "fd00:10::3", "nexthop", "via", "fd00:10::4"}),
+		table.Entry("no replica", nil, nil),
+	)
+
+	table.DescribeTable("selects the multipath hash policy of the IP family",
+		func(gw, policy string) {
+			Expect(multipathHashPolicy(gw)).To(Equal(policy))
+		},
+		table.Entry("IPv4 gateway", "172.30.10.3", "net.ipv4.fib_multipath_hash_policy"),
+		table.Entry("IPv6 gateway", "fd00:10::3", "net.ipv6.fib_multipath_hash_policy"),
+	)
+
+	table.DescribeTable("finds the installed routes",
+		func(routes []netlink.Route, dst, gw string, found bool) {
+			Expect(hasRoute(routes, dst, gw)).To(Equal(found))
+		},
+		table.Entry("route through the gateway",
+			[]netlink.Route{route("172.30.20.0/24", "172.30.10.3")}, "172.30.20.0/24", "172.30.10.3", true),
+		table.Entry("route through another gateway",
+			[]netlink.Route{route("172.30.20.0/24", "172.30.10.4")}, "172.30.20.0/24", "172.30.10.3", false),
+		table.Entry("default route",
+			[]netlink.Route{route("", "172.30.10.3")}, "0.0.0.0", "172.30.10.3", true),
+		table.Entry("host route",
+			[]netlink.Route{route("172.30.20.5/32", "172.30.10.3")}, "172.30.20.5", "172.30.10.3", true),
+		table.Entry("multipath route with the next hops in another order",
+			[]netlink.Route{route("172.30.20.0/24", "172.30.10.4", "172.30.10.3")}, "172.30.20.0/24", "172.30.10.3,172.30.10.4", true),
+		table.Entry("multipath route missing a replica",
+			[]netlink.Route{route("172.30.20.0/24", "172.30.10.3", "172.30.10.4")}, "172.30.20.0/24", "172.30.10.3,172.30.10.4,172.30.10.5", false),
+		table.Entry("multipath route with a replica removed",
+			[]netlink.Route{route("172.30.20.0/24", "172.30.10.3", "172.30.10.4")}, "172.30.20.0/24", "172.30.10.3", false),
+		table.Entry("multipath route with a replica replaced",
+			[]netlink.Route{route("172.30.20.0/24", "172.30.10.3", "172.30.10.5")}, "172.30.20.0/24", "172.30.10.3,172.30.10.4", false),
+		table.Entry("IPv6 multipath route",
+			[]netlink.Route{route("fd00:20::/64", "fd00:10::3", "fd00:10::4")}, "fd00:20::/64", "fd00:10::3,fd00:10::4", true),
+		table.Entry("invalid destination",
+			[]netlink.Route{route("172.30.20.0/24", "172.30.10.3")}, "invalid", "172.30.10.3", false),
+	)
+})
@@ -0,0 +1,68 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IPReservationSpec defines the addresses of a network reserved to the pods of the namespace
+// +k8s:openapi-gen=true
+type IPReservationSpec struct {
+	// Network is the name of the Network or provider network of the addresses
+	Network string `json:"network"`
+	// Ips are the reserved addresses or ranges of addresses, e.g. 10.0.0.10 or 10.0.0.10..10.0.0.20
+	Ips []string `json:"ips"`
+	// PodName reserves the first address to the pod of this name
+	PodName string `json:"podName,omitempty"`
+	// StatefulSet reserves the addresses to the replicas of the StatefulSet of this name, the replica
+	// with ordinal i gets the address with index i
+	StatefulSet string `json:"statefulSet,omitempty"`
+	// Selector reserves the addresses to the pods matching the selector, a pod gets the first free
+	// address and keeps it while it exists
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// IPAllocation is a reserved address given to a pod
+type IPAllocation struct {
+	Pod string `json:"pod"`
+	IP  string `json:"ip"`
+}
+
+// IPReservationStatus defines the observed state of IPReservation
+// +k8s:openapi-gen=true
+type IPReservationStatus struct {
+	State string `json:"state"` // Indicates if IPReservation is in "created" state
+	// Message explains a CreateInternalError state
+	Message string `json:"message,omitempty"`
+	// Allocations are the reserved addresses given to pods
+	Allocations []IPAllocation `json:"allocations,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IPReservation is the Schema for the ipreservations API, the reserved addresses are not given to
+// other pods of the network
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Network",type=string,JSONPath=`.spec.network`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +genclient
+type IPReservation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IPReservationSpec   `json:"spec,omitempty"`
+	Status IPReservationStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IPReservationList contains a list of IPReservation
+type IPReservationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IPReservation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IPReservation{}, &IPReservationList{})
+}
@@ -20,6 +20,37 @@ type NetworkSpec struct {
 	Ipv6Subnets []IpSubnet `json:"ipv6Subnets,omitempty"`
 	DNS         DnsSpec    `json:"dns,omitempty"`
 	Routes      []Route    `json:"routes,omitempty"`
+	Ipam        *IpamSpec  `json:"ipam,omitempty"`
+	// Mtu of the pod interfaces, defaults to the global MTU when not set
+	Mtu int `json:"mtu,omitempty"`
+	// TxQueueLen of the pod interfaces
+	TxQueueLen int          `json:"txqueuelen,omitempty"`
+	Offload    *OffloadSpec `json:"offload,omitempty"`
+}
+
+// OffloadSpec toggles the offload features of the pod interfaces. Features not set are left untouched
+type OffloadSpec struct {
+	Tx  *bool `json:"tx,omitempty"`
+	Rx  *bool `json:"rx,omitempty"`
+	Tso *bool `json:"tso,omitempty"`
+	Gso *bool `json:"gso,omitempty"`
+	Gro *bool `json:"gro,omitempty"`
+}
+
+// IpamSpec delegates the IP address assignment of the network to a CNI IPAM plugin
+type IpamSpec struct {
+	// Type is the name of the IPAM plugin binary, e.g. host-local, whereabouts or static
+	Type string `json:"type"`
+	// Config is the JSON encoded "ipam" section passed to the plugin, without the type
+	Config string `json:"config,omitempty"`
+}
+
+// IpamAllocation is an address allocation of the delegated IPAM plugin, the allocations are kept
+// in the status to restore the local state of the plugin when the nfn-operator is rescheduled
+type IpamAllocation struct {
+	ContainerID string   `json:"containerID"`
+	IfName      string   `json:"ifName"`
+	IPs         []string `json:"ips"`
 }
 
 type IpSubnet struct {
@@ -29,10 +60,24 @@ type IpSubnet struct {
 	ExcludeIps string `json:"excludeIps,omitempty"`
 }
 
-type NetworkPool struct {
-	PoolNr    int      `json:"poolnumber"`
-	Network   IpSubnet `json:"network"`
-	Available bool     `json:"available"`
+// SubnetUsage is the IP address usage of a subnet. The counts of a large IPv6 subnet are capped to
+// the largest int64
+type SubnetUsage struct {
+	Name   string `json:"name"`
+	Subnet string `json:"subnet"`
+	// Total is the number of addresses of the subnet, without the network and broadcast addresses
+	Total int64 `json:"total"`
+	// Allocated is the number of addresses of the ports of the network
+	Allocated int64 `json:"allocated"`
+	// Excluded is the number of addresses of the gateway and the excludeIps
+	Excluded int64 `json:"excluded"`
+	Free     int64 `json:"free"`
+}
+
+// NamespaceUsage is the number of addresses of the network allocated to the pods of a namespace
+type NamespaceUsage struct {
+	Namespace string `json:"namespace"`
+	Allocated int64  `json:"allocated"`
 }
 
 type Route struct {
@@ -75,6 +120,14 @@ type NetworkStatus struct {
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
 	State string `json:"state"` // Indicates if Network is in "created" state
+	// Subnets is the IP address usage of the subnets of the network
+	Subnets []SubnetUsage `json:"subnets,omitempty"`
+	// Namespaces is the IP address usage of the network by namespace
+	Namespaces []NamespaceUsage `json:"namespaces,omitempty"`
+	// Conflicts are the other address sources of the cluster overlapping the subnets of the network
+	Conflicts []string `json:"conflicts,omitempty"`
+	// IpamAllocations are the addresses allocated by the delegated IPAM plugin of the network
+	IpamAllocations []IpamAllocation `json:"ipamAllocations,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
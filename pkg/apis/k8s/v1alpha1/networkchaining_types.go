@@ -34,6 +34,8 @@ type RoutingNetwork struct {
 // +k8s:openapi-gen=true
 type NetworkChainingStatus struct {
 	State string `json:"state"` // Indicates if Network Chain is in "created" state
+	// Reason is what a Pending chain waits for, the network functions without a ready replica
+	Reason string `json:"reason,omitempty"`
 }
 
 // NetworkChaining is the Schema for the networkchainings API
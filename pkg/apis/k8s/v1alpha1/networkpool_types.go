@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NetworkPoolSpec defines the supernet split into the subnets of the networks created from the pool
+// +k8s:openapi-gen=true
+type NetworkPoolSpec struct {
+	// Network is the IPv4 or IPv6 supernet of the pool, e.g. 10.10.0.0/16
+	Network string `json:"network"`
+	// SubnetLen is the prefix length of the subnet of each network, computed from the supernet when not set
+	SubnetLen uint `json:"subnetLen,omitempty"`
+	// Ipv6Network is the IPv6 supernet paired with the IPv4 one for dual-stack networks
+	Ipv6Network string `json:"ipv6Network,omitempty"`
+	// Ipv6SubnetLen is the prefix length of the IPv6 subnet of each network, computed from the
+	// IPv6 supernet when not set
+	Ipv6SubnetLen uint `json:"ipv6SubnetLen,omitempty"`
+	// SubnetMin is the first subnet of the pool
+	SubnetMin string `json:"subnetMin,omitempty"`
+	// SubnetMax is the last subnet of the pool
+	SubnetMax string `json:"subnetMax,omitempty"`
+	// IdleTimeout deletes the networks created from the pool once no pod is attached to them for
+	// this duration, the networks are kept when not set
+	IdleTimeout *metav1.Duration `json:"idleTimeout,omitempty"`
+}
+
+// NetworkPoolStatus defines the observed state of NetworkPool
+// +k8s:openapi-gen=true
+type NetworkPoolStatus struct {
+	// Total is the number of subnets of the pool
+	Total int `json:"total"`
+	// Allocated is the number of subnets used by networks
+	Allocated int `json:"allocated"`
+	// Free is the number of subnets left
+	Free int `json:"free"`
+	// Allocations are the subnets used by networks, the name of a subnet is the name of its network
+	Allocations []IpSubnet `json:"allocations,omitempty"`
+	// Ipv6Allocations are the IPv6 subnets used by networks
+	Ipv6Allocations []IpSubnet `json:"ipv6Allocations,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkPool is the Schema for the networkpools API, the networks created on demand for pods and
+// network chainings get their subnet from it
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Network",type=string,JSONPath=`.spec.network`
+// +kubebuilder:printcolumn:name="SubnetLen",type=integer,JSONPath=`.spec.subnetLen`
+// +kubebuilder:printcolumn:name="Allocated",type=integer,JSONPath=`.status.allocated`
+// +kubebuilder:printcolumn:name="Free",type=integer,JSONPath=`.status.free`
+// +genclient
+// +genclient:nonNamespaced
+type NetworkPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NetworkPoolSpec   `json:"spec,omitempty"`
+	Status NetworkPoolStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkPoolList contains a list of NetworkPool
+type NetworkPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NetworkPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NetworkPool{}, &NetworkPoolList{})
+}
@@ -21,6 +21,38 @@ type ProviderNetworkSpec struct {
 	ProviderNetType string     `json:"providerNetType"`
 	Vlan            VlanSpec   `json:"vlan,omitempty"` // For now VLAN & Direct only supported type
 	Direct          DirectSpec `json:"direct,omitempty"`
+	// Mtu of the pod interfaces, must not exceed the provider interface MTU
+	Mtu int `json:"mtu,omitempty"`
+	// TxQueueLen of the pod interfaces
+	TxQueueLen int             `json:"txqueuelen,omitempty"`
+	Offload    *OffloadSpec    `json:"offload,omitempty"`
+	Attachment *AttachmentSpec `json:"attachment,omitempty"`
+}
+
+// AttachmentSpec selects how the pod interfaces are attached to the provider network
+type AttachmentSpec struct {
+	// Type is "ovs" (default), "macvlan" or "ipvlan". macvlan and ipvlan move a sub-interface
+	// of the provider (or VLAN) interface into the pod instead of using an OVS bridge
+	Type string `json:"type"`
+	// Mode is the macvlan (bridge, private, vepa, passthru) or ipvlan (l2, l3, l3s) mode
+	Mode string `json:"mode,omitempty"`
+}
+
+const (
+	// OvsAttachment attaches the pods through the provider network OVS bridge
+	OvsAttachment = "ovs"
+	// MacvlanAttachment attaches the pods with a macvlan sub-interface
+	MacvlanAttachment = "macvlan"
+	// IpvlanAttachment attaches the pods with an ipvlan sub-interface
+	IpvlanAttachment = "ipvlan"
+)
+
+// GetAttachmentType returns the pod attachment type of the provider network
+func (spec *ProviderNetworkSpec) GetAttachmentType() string {
+	if spec.Attachment == nil || spec.Attachment.Type == "" {
+		return OvsAttachment
+	}
+	return spec.Attachment.Type
 }
 
 type VlanSpec struct {
@@ -44,6 +76,12 @@ type ProviderNetworkStatus struct {
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
 	State string `json:"state"` // Indicates if ProviderNetwork is in "created" state
+	// Subnets is the IP address usage of the subnets of the provider network
+	Subnets []SubnetUsage `json:"subnets,omitempty"`
+	// Namespaces is the IP address usage of the provider network by namespace
+	Namespaces []NamespaceUsage `json:"namespaces,omitempty"`
+	// Conflicts are the other address sources of the cluster overlapping the subnets of the provider network
+	Conflicts []string `json:"conflicts,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -69,6 +107,53 @@ type ProviderNetworkList struct {
 	Items           []ProviderNetwork `json:"items"`
 }
 
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterProviderNetwork is the cluster-scoped variant of ProviderNetwork, owned by the
+// platform admins
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +genclient
+// +genclient:nonNamespaced
+type ClusterProviderNetwork struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProviderNetworkSpec   `json:"spec,omitempty"`
+	Status ProviderNetworkStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterProviderNetworkList contains a list of ClusterProviderNetwork
+type ClusterProviderNetworkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterProviderNetwork `json:"items"`
+}
+
+// ProviderNetwork returns the ClusterProviderNetwork as a ProviderNetwork without namespace,
+// the provider network code handles both kinds through it
+func (in *ClusterProviderNetwork) ProviderNetwork() *ProviderNetwork {
+	return &ProviderNetwork{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Spec:       *in.Spec.DeepCopy(),
+		Status:     in.Status,
+	}
+}
+
+// OwnsNameBefore checks if the provider network was created before other, the oldest provider
+// network or ClusterProviderNetwork of a name owns the OVN logical switch of the name
+func (in *ProviderNetwork) OwnsNameBefore(other *ProviderNetwork) bool {
+	if !in.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return in.CreationTimestamp.Before(&other.CreationTimestamp)
+	}
+	return in.Namespace < other.Namespace
+}
+
 func init() {
 	SchemeBuilder.Register(&ProviderNetwork{}, &ProviderNetworkList{})
+	SchemeBuilder.Register(&ClusterProviderNetwork{}, &ClusterProviderNetworkList{})
 }
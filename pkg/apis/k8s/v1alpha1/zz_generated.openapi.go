@@ -11,15 +11,210 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"./pkg/apis/k8s/v1alpha1.Network":               schema_pkg_apis_k8s_v1alpha1_Network(ref),
-		"./pkg/apis/k8s/v1alpha1.NetworkChaining":       schema_pkg_apis_k8s_v1alpha1_NetworkChaining(ref),
-		"./pkg/apis/k8s/v1alpha1.NetworkChainingSpec":   schema_pkg_apis_k8s_v1alpha1_NetworkChainingSpec(ref),
-		"./pkg/apis/k8s/v1alpha1.NetworkChainingStatus": schema_pkg_apis_k8s_v1alpha1_NetworkChainingStatus(ref),
-		"./pkg/apis/k8s/v1alpha1.NetworkSpec":           schema_pkg_apis_k8s_v1alpha1_NetworkSpec(ref),
-		"./pkg/apis/k8s/v1alpha1.NetworkStatus":         schema_pkg_apis_k8s_v1alpha1_NetworkStatus(ref),
-		"./pkg/apis/k8s/v1alpha1.ProviderNetwork":       schema_pkg_apis_k8s_v1alpha1_ProviderNetwork(ref),
-		"./pkg/apis/k8s/v1alpha1.ProviderNetworkSpec":   schema_pkg_apis_k8s_v1alpha1_ProviderNetworkSpec(ref),
-		"./pkg/apis/k8s/v1alpha1.ProviderNetworkStatus": schema_pkg_apis_k8s_v1alpha1_ProviderNetworkStatus(ref),
+		"./pkg/apis/k8s/v1alpha1.ClusterProviderNetwork": schema_pkg_apis_k8s_v1alpha1_ClusterProviderNetwork(ref),
+		"./pkg/apis/k8s/v1alpha1.IPReservation":          schema_pkg_apis_k8s_v1alpha1_IPReservation(ref),
+		"./pkg/apis/k8s/v1alpha1.IPReservationSpec":      schema_pkg_apis_k8s_v1alpha1_IPReservationSpec(ref),
+		"./pkg/apis/k8s/v1alpha1.IPReservationStatus":    schema_pkg_apis_k8s_v1alpha1_IPReservationStatus(ref),
+		"./pkg/apis/k8s/v1alpha1.Network":                schema_pkg_apis_k8s_v1alpha1_Network(ref),
+		"./pkg/apis/k8s/v1alpha1.NetworkChaining":        schema_pkg_apis_k8s_v1alpha1_NetworkChaining(ref),
+		"./pkg/apis/k8s/v1alpha1.NetworkChainingSpec":    schema_pkg_apis_k8s_v1alpha1_NetworkChainingSpec(ref),
+		"./pkg/apis/k8s/v1alpha1.NetworkChainingStatus":  schema_pkg_apis_k8s_v1alpha1_NetworkChainingStatus(ref),
+		"./pkg/apis/k8s/v1alpha1.NetworkPool":            schema_pkg_apis_k8s_v1alpha1_NetworkPool(ref),
+		"./pkg/apis/k8s/v1alpha1.NetworkPoolSpec":        schema_pkg_apis_k8s_v1alpha1_NetworkPoolSpec(ref),
+		"./pkg/apis/k8s/v1alpha1.NetworkPoolStatus":      schema_pkg_apis_k8s_v1alpha1_NetworkPoolStatus(ref),
+		"./pkg/apis/k8s/v1alpha1.NetworkSpec":            schema_pkg_apis_k8s_v1alpha1_NetworkSpec(ref),
+		"./pkg/apis/k8s/v1alpha1.NetworkStatus":          schema_pkg_apis_k8s_v1alpha1_NetworkStatus(ref),
+		"./pkg/apis/k8s/v1alpha1.ProviderNetwork":        schema_pkg_apis_k8s_v1alpha1_ProviderNetwork(ref),
+		"./pkg/apis/k8s/v1alpha1.ProviderNetworkSpec":    schema_pkg_apis_k8s_v1alpha1_ProviderNetworkSpec(ref),
+		"./pkg/apis/k8s/v1alpha1.ProviderNetworkStatus":  schema_pkg_apis_k8s_v1alpha1_ProviderNetworkStatus(ref),
+	}
+}
+
+func schema_pkg_apis_k8s_v1alpha1_ClusterProviderNetwork(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterProviderNetwork is the cluster-scoped variant of ProviderNetwork, owned by the platform admins",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/k8s/v1alpha1.ProviderNetworkSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/k8s/v1alpha1.ProviderNetworkStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/k8s/v1alpha1.ProviderNetworkSpec", "./pkg/apis/k8s/v1alpha1.ProviderNetworkStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_k8s_v1alpha1_IPReservation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IPReservation is the Schema for the ipreservations API, the reserved addresses are not given to other pods of the network",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/k8s/v1alpha1.IPReservationSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/k8s/v1alpha1.IPReservationStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/k8s/v1alpha1.IPReservationSpec", "./pkg/apis/k8s/v1alpha1.IPReservationStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_k8s_v1alpha1_IPReservationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IPReservationSpec defines the addresses of a network reserved to the pods of the namespace",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"network": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Network is the name of the Network or provider network of the addresses",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ips": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ips are the reserved addresses or ranges of addresses, e.g. 10.0.0.10 or 10.0.0.10..10.0.0.20",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"podName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodName reserves the first address to the pod of this name",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"statefulSet": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StatefulSet reserves the addresses to the replicas of the StatefulSet of this name, the replica with ordinal i gets the address with index i",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"selector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Selector reserves the addresses to the pods matching the selector, a pod gets the first free address and keeps it while it exists",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+				},
+				Required: []string{"network", "ips"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema_pkg_apis_k8s_v1alpha1_IPReservationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IPReservationStatus defines the observed state of IPReservation",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message explains a CreateInternalError state",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"allocations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Allocations are the reserved addresses given to pods",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/k8s/v1alpha1.IPAllocation"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"state"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/k8s/v1alpha1.IPAllocation"},
 	}
 }
 
@@ -152,6 +347,13 @@ func schema_pkg_apis_k8s_v1alpha1_NetworkChainingStatus(ref common.ReferenceCall
 							Format: "",
 						},
 					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reason is what a Pending chain waits for, the network functions without a ready replica",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"state"},
 			},
@@ -159,6 +361,177 @@ func schema_pkg_apis_k8s_v1alpha1_NetworkChainingStatus(ref common.ReferenceCall
 	}
 }
 
+func schema_pkg_apis_k8s_v1alpha1_NetworkPool(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NetworkPool is the Schema for the networkpools API, the networks created on demand for pods and network chainings get their subnet from it",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/k8s/v1alpha1.NetworkPoolSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/k8s/v1alpha1.NetworkPoolStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/k8s/v1alpha1.NetworkPoolSpec", "./pkg/apis/k8s/v1alpha1.NetworkPoolStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_k8s_v1alpha1_NetworkPoolSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NetworkPoolSpec defines the supernet split into the subnets of the networks created from the pool",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"network": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Network is the IPv4 or IPv6 supernet of the pool, e.g. 10.10.0.0/16",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"subnetLen": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SubnetLen is the prefix length of the subnet of each network, computed from the supernet when not set",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"ipv6Network": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ipv6Network is the IPv6 supernet paired with the IPv4 one for dual-stack networks",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ipv6SubnetLen": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ipv6SubnetLen is the prefix length of the IPv6 subnet of each network, computed from the IPv6 supernet when not set",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"subnetMin": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SubnetMin is the first subnet of the pool",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"subnetMax": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SubnetMax is the last subnet of the pool",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"idleTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IdleTimeout deletes the networks created from the pool once no pod is attached to them for this duration, the networks are kept when not set",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"network"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_k8s_v1alpha1_NetworkPoolStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NetworkPoolStatus defines the observed state of NetworkPool",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"total": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Total is the number of subnets of the pool",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"allocated": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Allocated is the number of subnets used by networks",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"free": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Free is the number of subnets left",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"allocations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Allocations are the subnets used by networks, the name of a subnet is the name of its network",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/k8s/v1alpha1.IpSubnet"),
+									},
+								},
+							},
+						},
+					},
+					"ipv6Allocations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ipv6Allocations are the IPv6 subnets used by networks",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/k8s/v1alpha1.IpSubnet"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"total", "allocated", "free"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/k8s/v1alpha1.IpSubnet"},
+	}
+}
+
 func schema_pkg_apis_k8s_v1alpha1_NetworkSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -214,12 +587,36 @@ func schema_pkg_apis_k8s_v1alpha1_NetworkSpec(ref common.ReferenceCallback) comm
 							},
 						},
 					},
+					"ipam": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/k8s/v1alpha1.IpamSpec"),
+						},
+					},
+					"mtu": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mtu of the pod interfaces, defaults to the global MTU when not set",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"txqueuelen": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TxQueueLen of the pod interfaces",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"offload": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/k8s/v1alpha1.OffloadSpec"),
+						},
+					},
 				},
 				Required: []string{"cniType", "ipv4Subnets"},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/k8s/v1alpha1.DnsSpec", "./pkg/apis/k8s/v1alpha1.IpSubnet", "./pkg/apis/k8s/v1alpha1.Route"},
+			"./pkg/apis/k8s/v1alpha1.DnsSpec", "./pkg/apis/k8s/v1alpha1.IpSubnet", "./pkg/apis/k8s/v1alpha1.IpamSpec", "./pkg/apis/k8s/v1alpha1.OffloadSpec", "./pkg/apis/k8s/v1alpha1.Route"},
 	}
 }
 
@@ -237,10 +634,66 @@ func schema_pkg_apis_k8s_v1alpha1_NetworkStatus(ref common.ReferenceCallback) co
 							Format:      "",
 						},
 					},
+					"subnets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Subnets is the IP address usage of the subnets of the network",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/k8s/v1alpha1.SubnetUsage"),
+									},
+								},
+							},
+						},
+					},
+					"namespaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespaces is the IP address usage of the network by namespace",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/k8s/v1alpha1.NamespaceUsage"),
+									},
+								},
+							},
+						},
+					},
+					"conflicts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conflicts are the other address sources of the cluster overlapping the subnets of the network",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"ipamAllocations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IpamAllocations are the addresses allocated by the delegated IPAM plugin of the network",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/k8s/v1alpha1.IpamAllocation"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"state"},
 			},
 		},
+		Dependencies: []string{
+			"./pkg/apis/k8s/v1alpha1.IpamAllocation", "./pkg/apis/k8s/v1alpha1.NamespaceUsage", "./pkg/apis/k8s/v1alpha1.SubnetUsage"},
 	}
 }
 
@@ -360,12 +813,36 @@ func schema_pkg_apis_k8s_v1alpha1_ProviderNetworkSpec(ref common.ReferenceCallba
 							Ref:         ref("./pkg/apis/k8s/v1alpha1.DirectSpec"),
 						},
 					},
+					"mtu": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mtu of the pod interfaces, must not exceed the provider interface MTU",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"txqueuelen": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TxQueueLen of the pod interfaces",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"offload": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/k8s/v1alpha1.OffloadSpec"),
+						},
+					},
+					"attachment": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/k8s/v1alpha1.AttachmentSpec"),
+						},
+					},
 				},
 				Required: []string{"cniType", "ipv4Subnets", "providerNetType"},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/k8s/v1alpha1.DirectSpec", "./pkg/apis/k8s/v1alpha1.DnsSpec", "./pkg/apis/k8s/v1alpha1.IpSubnet", "./pkg/apis/k8s/v1alpha1.Route", "./pkg/apis/k8s/v1alpha1.VlanSpec"},
+			"./pkg/apis/k8s/v1alpha1.AttachmentSpec", "./pkg/apis/k8s/v1alpha1.DirectSpec", "./pkg/apis/k8s/v1alpha1.DnsSpec", "./pkg/apis/k8s/v1alpha1.IpSubnet", "./pkg/apis/k8s/v1alpha1.OffloadSpec", "./pkg/apis/k8s/v1alpha1.Route", "./pkg/apis/k8s/v1alpha1.VlanSpec"},
 	}
 }
 
@@ -383,9 +860,52 @@ func schema_pkg_apis_k8s_v1alpha1_ProviderNetworkStatus(ref common.ReferenceCall
 							Format:      "",
 						},
 					},
+					"subnets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Subnets is the IP address usage of the subnets of the provider network",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/k8s/v1alpha1.SubnetUsage"),
+									},
+								},
+							},
+						},
+					},
+					"namespaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespaces is the IP address usage of the provider network by namespace",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/k8s/v1alpha1.NamespaceUsage"),
+									},
+								},
+							},
+						},
+					},
+					"conflicts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conflicts are the other address sources of the cluster overlapping the subnets of the provider network",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"state"},
 			},
 		},
+		Dependencies: []string{
+			"./pkg/apis/k8s/v1alpha1.NamespaceUsage", "./pkg/apis/k8s/v1alpha1.SubnetUsage"},
 	}
 }
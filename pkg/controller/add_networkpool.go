@@ -0,0 +1,10 @@
+package controller
+
+import (
+	"github.com/akraino-edge-stack/icn-nodus/pkg/controller/networkpool"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, networkpool.Add)
+}
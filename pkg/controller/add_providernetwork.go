@@ -6,5 +6,5 @@ import (
 
 func init() {
 	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
-	AddToManagerFuncs = append(AddToManagerFuncs, providernetwork.Add)
+	AddToManagerFuncs = append(AddToManagerFuncs, providernetwork.Add, providernetwork.AddCluster)
 }
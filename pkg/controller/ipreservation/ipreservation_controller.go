@@ -0,0 +1,341 @@
+package ipreservation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+	"github.com/akraino-edge-stack/icn-nodus/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("ipreservation_controller")
+
+const (
+	nfnIPReservationFinalizer = "nfnCleanUpIPReservation"
+)
+
+// Add creates a new IPReservation Controller and adds it to the Manager. The pods get their reserved
+// addresses when their ports are added, the controller validates the reservations, excludes the
+// reserved addresses from the dynamic addresses of the networks and frees the addresses of deleted pods.
+func Add(mgr manager.Manager) error {
+	r := &ReconcileIPReservation{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	c, err := controller.New("ipreservation-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource IPReservation
+	err = c.Watch(&source.Kind{Type: &k8sv1alpha1.IPReservation{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+	// A reservation is validated against the other reservations of its network
+	err = c.Watch(&source.Kind{Type: &k8sv1alpha1.IPReservation{}}, handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		return r.networkReservations(obj.(*k8sv1alpha1.IPReservation).Spec.Network)
+	}))
+	if err != nil {
+		return err
+	}
+	// The excluded addresses are reset when the spec of a network changes or its logical switch is
+	// created, the other status updates are ignored
+	networkChanged := predicate.Or(predicate.GenerationChangedPredicate{}, predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return networkState(e.ObjectNew) == k8sv1alpha1.Created && networkState(e.ObjectOld) != k8sv1alpha1.Created
+		},
+	})
+	for _, obj := range []client.Object{&k8sv1alpha1.Network{}, &k8sv1alpha1.ProviderNetwork{}, &k8sv1alpha1.ClusterProviderNetwork{}} {
+		err = c.Watch(&source.Kind{Type: obj}, handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+			return r.networkReservations(obj.GetName())
+		}), networkChanged)
+		if err != nil {
+			return err
+		}
+	}
+	// The addresses of deleted pods are freed
+	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(r.podReservations))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// blank assignment to verify that ReconcileIPReservation implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileIPReservation{}
+
+// ReconcileIPReservation reconciles a IPReservation object
+type ReconcileIPReservation struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// networkState returns the state of a Network, ProviderNetwork or ClusterProviderNetwork
+func networkState(obj client.Object) string {
+	switch n := obj.(type) {
+	case *k8sv1alpha1.Network:
+		return n.Status.State
+	case *k8sv1alpha1.ProviderNetwork:
+		return n.Status.State
+	case *k8sv1alpha1.ClusterProviderNetwork:
+		return n.Status.State
+	}
+	return ""
+}
+
+// networkReservations returns the requests of the reservations of the network
+func (r *ReconcileIPReservation) networkReservations(network string) []reconcile.Request {
+	reservations := &k8sv1alpha1.IPReservationList{}
+	if err := r.client.List(context.TODO(), reservations, client.MatchingFields{ovn.ReservationNetworkField: network}); err != nil {
+		log.Error(err, "Failed to list IPReservations")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, item := range reservations.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: item.Namespace, Name: item.Name}})
+	}
+	return requests
+}
+
+// podReservations returns the requests of the reservations with an address allocated to the pod
+func (r *ReconcileIPReservation) podReservations(obj client.Object) []reconcile.Request {
+	reservations := &k8sv1alpha1.IPReservationList{}
+	if err := r.client.List(context.TODO(), reservations, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "Failed to list IPReservations")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, item := range reservations.Items {
+		for _, a := range item.Status.Allocations {
+			if a.Pod == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: item.Namespace, Name: item.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// Reconcile reads that state of the cluster for a IPReservation object and makes changes based on the state read
+// and what is in the IPReservation.Spec
+func (r *ReconcileIPReservation) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.V(1).Info("Reconciling IPReservation")
+
+	instance := &k8sv1alpha1.IPReservation{}
+	err := r.client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if !instance.DeletionTimestamp.IsZero() {
+		if !utils.Contains(instance.ObjectMeta.Finalizers, nfnIPReservationFinalizer) {
+			return reconcile.Result{}, nil
+		}
+		// Give the addresses back to the dynamic addresses of the network
+		if err = r.setReservedAddresses(ctx, instance.Spec.Network); err != nil {
+			reqLogger.Error(err, "Releasing the reserved addresses")
+			return reconcile.Result{}, err
+		}
+		instance.ObjectMeta.Finalizers = utils.Remove(instance.ObjectMeta.Finalizers, nfnIPReservationFinalizer)
+		if err = r.client.Update(ctx, instance); err != nil {
+			reqLogger.Error(err, "Removing Finalize")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if !utils.Contains(instance.GetFinalizers(), nfnIPReservationFinalizer) {
+		instance.SetFinalizers(append(instance.GetFinalizers(), nfnIPReservationFinalizer))
+		if err = r.client.Update(ctx, instance); err != nil {
+			reqLogger.Error(err, "Adding Finalize")
+			return reconcile.Result{}, err
+		}
+		reqLogger.V(1).Info("Finalizer added")
+	}
+
+	status := instance.Status.DeepCopy()
+	if err = r.validate(ctx, instance); err != nil {
+		reqLogger.Error(err, "Invalid IPReservation")
+		instance.Status.State = k8sv1alpha1.CreateInternalError
+		instance.Status.Message = err.Error()
+	} else {
+		instance.Status.State = k8sv1alpha1.Created
+		instance.Status.Message = ""
+	}
+	if err = r.pruneAllocations(ctx, instance); err != nil {
+		return reconcile.Result{}, err
+	}
+	if !reflect.DeepEqual(status, &instance.Status) {
+		// A conflict with an allocation requeues the request
+		if err = r.client.Status().Update(ctx, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if err = r.setReservedAddresses(ctx, instance.Spec.Network); err != nil {
+		reqLogger.Error(err, "Excluding the reserved addresses")
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// validate checks the spec of the reservation. Its addresses must not be reserved by an older
+// reservation of the network
+func (r *ReconcileIPReservation) validate(ctx context.Context, cr *k8sv1alpha1.IPReservation) error {
+	if cr.Spec.Network == "" {
+		return fmt.Errorf("network is not set")
+	}
+	if len(cr.Spec.Ips) == 0 {
+		return fmt.Errorf("no reserved address")
+	}
+	var n int
+	for _, set := range []bool{cr.Spec.PodName != "", cr.Spec.StatefulSet != "", cr.Spec.Selector != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("exactly one of podName, statefulSet and selector must be set")
+	}
+	if cr.Spec.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(cr.Spec.Selector); err != nil {
+			return err
+		}
+	}
+	if _, err := ovn.ReservedIpsOverlap(cr.Spec.Ips, nil); err != nil {
+		return err
+	}
+
+	reservations := &k8sv1alpha1.IPReservationList{}
+	if err := r.client.List(ctx, reservations, client.MatchingFields{ovn.ReservationNetworkField: cr.Spec.Network}); err != nil {
+		return err
+	}
+	for _, item := range reservations.Items {
+		if item.UID == cr.UID || !olderThan(&item, cr) {
+			continue
+		}
+		overlap, err := ovn.ReservedIpsOverlap(cr.Spec.Ips, item.Spec.Ips)
+		if err != nil {
+			// the older reservation is invalid itself
+			continue
+		}
+		if overlap {
+			return fmt.Errorf("addresses are reserved by IPReservation %s/%s", item.Namespace, item.Name)
+		}
+	}
+	return nil
+}
+
+// olderThan orders the reservations by creation time, then by namespace and name
+func olderThan(a, b *k8sv1alpha1.IPReservation) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// pruneAllocations frees the addresses allocated to deleted pods
+func (r *ReconcileIPReservation) pruneAllocations(ctx context.Context, cr *k8sv1alpha1.IPReservation) error {
+	var allocations []k8sv1alpha1.IPAllocation
+	for _, a := range cr.Status.Allocations {
+		pod := &corev1.Pod{}
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: a.Pod}, pod)
+		if errors.IsNotFound(err) {
+			log.Info("Freeing the reserved address of the deleted pod", "IPReservation", cr.Name, "namespace", cr.Namespace, "pod", a.Pod, "ip", a.IP)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		allocations = append(allocations, a)
+	}
+	cr.Status.Allocations = allocations
+	return nil
+}
+
+// setReservedAddresses excludes the addresses of the valid reservations of the network from its
+// dynamic addresses
+func (r *ReconcileIPReservation) setReservedAddresses(ctx context.Context, network string) error {
+	excludeIps, ok, err := r.networkExcludeIps(ctx, network)
+	if err != nil || !ok {
+		return err
+	}
+
+	reservations := &k8sv1alpha1.IPReservationList{}
+	if err := r.client.List(ctx, reservations, client.MatchingFields{ovn.ReservationNetworkField: network}); err != nil {
+		return err
+	}
+	var reserved []string
+	for _, item := range reservations.Items {
+		if item.Status.State != k8sv1alpha1.Created || !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		reserved = append(reserved, item.Spec.Ips...)
+	}
+	return ovn.SetReservedAddresses(network, excludeIps, reserved)
+}
+
+// networkExcludeIps returns the excludeIps of the IPv4 subnet of the Network, ProviderNetwork or
+// ClusterProviderNetwork of the name, ok is false when there is no such network
+func (r *ReconcileIPReservation) networkExcludeIps(ctx context.Context, name string) (excludeIps string, ok bool, err error) {
+	ipv4Subnets := func(subnets []k8sv1alpha1.IpSubnet) string {
+		if len(subnets) == 0 {
+			return ""
+		}
+		return subnets[0].ExcludeIps
+	}
+
+	networks := &k8sv1alpha1.NetworkList{}
+	if err = r.client.List(ctx, networks); err != nil {
+		return "", false, err
+	}
+	for _, item := range networks.Items {
+		if item.Name == name && item.DeletionTimestamp.IsZero() {
+			return ipv4Subnets(item.Spec.Ipv4Subnets), true, nil
+		}
+	}
+
+	providerNetworks := &k8sv1alpha1.ProviderNetworkList{}
+	if err = r.client.List(ctx, providerNetworks); err != nil {
+		return "", false, err
+	}
+	for _, item := range providerNetworks.Items {
+		if item.Name == name && item.DeletionTimestamp.IsZero() {
+			return ipv4Subnets(item.Spec.Ipv4Subnets), true, nil
+		}
+	}
+
+	cpn := &k8sv1alpha1.ClusterProviderNetwork{}
+	err = r.client.Get(ctx, types.NamespacedName{Name: name}, cpn)
+	if errors.IsNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return ipv4Subnets(cpn.Spec.Ipv4Subnets), cpn.DeletionTimestamp.IsZero(), nil
+}
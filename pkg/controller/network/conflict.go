@@ -0,0 +1,67 @@
+package network
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/network"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+	"github.com/akraino-edge-stack/icn-nodus/pkg/utils"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileConflicts checks the subnets of the network against the address space of the cluster.
+// The overlapping sources are recorded in the status of the network and a Warning event names the
+// new ones. A network with conflicts is not created, a created network is only reported
+func (r *ReconcileNetwork) reconcileConflicts(cr *k8sv1alpha1.Network, reqLogger logr.Logger) error {
+	if !cr.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	registry, err := ovn.NewAddressRegistry(context.TODO(), r.client)
+	if err != nil {
+		reqLogger.Error(err, "Error reading the address space of the cluster")
+		return err
+	}
+	conflicts, warnings := registry.NetworkConflicts(cr)
+	if cr.Status.State != k8sv1alpha1.Created {
+		for _, w := range warnings {
+			r.recorder.Eventf(cr, corev1.EventTypeWarning, ovn.AddressConflictReason, "Subnets overlap %s", w)
+		}
+	}
+	for _, c := range conflicts {
+		if !utils.Contains(cr.Status.Conflicts, c) {
+			r.recorder.Eventf(cr, corev1.EventTypeWarning, ovn.AddressConflictReason, "Subnets overlap %s", c)
+		}
+	}
+	if reflect.DeepEqual(cr.Status.Conflicts, conflicts) {
+		return nil
+	}
+	if len(conflicts) > 0 {
+		reqLogger.Info("Subnets of the network overlap", "conflicts", strings.Join(conflicts, ", "))
+	}
+	cr.Status.Conflicts = conflicts
+	return r.client.Status().Update(context.TODO(), cr)
+}
+
+// rejectedForConflicts checks if the network must not be created for its conflicts
+func rejectedForConflicts(cr *k8sv1alpha1.Network) bool {
+	return len(cr.Status.Conflicts) > 0 && cr.Status.State != k8sv1alpha1.Created
+}
+
+// withConflictRequeue requeues a network rejected for its conflicts, it is created once they are
+// resolved
+func withConflictRequeue(cr *k8sv1alpha1.Network, result reconcile.Result) reconcile.Result {
+	if !cr.DeletionTimestamp.IsZero() || !rejectedForConflicts(cr) {
+		return result
+	}
+	if result.RequeueAfter == 0 || result.RequeueAfter > network.UsageInterval {
+		result.RequeueAfter = network.UsageInterval
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package network
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/network"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// idleSinceAnnotation records when the last pod left a network created from a pool
+	idleSinceAnnotation = "k8s.plugin.opnfv.org/idle-since"
+	// drainingGracePeriod is how long a network is marked as draining before it is deleted, the
+	// nfn-operator checks the mark from its cache when a pod is attached
+	drainingGracePeriod = 10 * time.Second
+)
+
+// reconcileIdle deletes the networks created from a pool with an idle timeout once no pod and no
+// network chaining uses them for the timeout. The network is requeued to check it again
+func (r *ReconcileNetwork) reconcileIdle(cr *k8sv1alpha1.Network, reqLogger logr.Logger) (reconcile.Result, error) {
+	poolName, ok := cr.Labels[network.NetworkPoolLabel]
+	if !ok || !cr.DeletionTimestamp.IsZero() || cr.Status.State != k8sv1alpha1.Created {
+		return reconcile.Result{}, nil
+	}
+
+	pool := &k8sv1alpha1.NetworkPool{}
+	if err := r.client.Get(context.TODO(), client.ObjectKey{Name: poolName}, pool); err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if pool.Spec.IdleTimeout == nil || pool.Spec.IdleTimeout.Duration <= 0 {
+		return reconcile.Result{}, nil
+	}
+	timeout := pool.Spec.IdleTimeout.Duration
+
+	inUse, err := r.networkInUse(cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if inUse {
+		return r.keepNetwork(cr, timeout)
+	}
+
+	since, err := time.Parse(time.RFC3339, cr.Annotations[idleSinceAnnotation])
+	if err != nil {
+		if cr.Annotations == nil {
+			cr.Annotations = make(map[string]string)
+		}
+		cr.Annotations[idleSinceAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		if err := r.client.Update(context.TODO(), cr); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: timeout}, nil
+	}
+
+	if idle := time.Since(since); idle < timeout {
+		return reconcile.Result{RequeueAfter: timeout - idle}, nil
+	}
+
+	// The network is marked before its usage is checked again, a pod attached after the check sees
+	// the mark and detaches from the network. The mark records when it was set, the usage is
+	// checked again once the mark reached the cache of the pod controller
+	markedAt, err := time.Parse(time.RFC3339, cr.Annotations[network.NetworkDrainingAnnotation])
+	if err != nil {
+		cr.Annotations[network.NetworkDrainingAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		if err := r.client.Update(context.TODO(), cr); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: drainingGracePeriod}, nil
+	}
+	if wait := drainingGracePeriod - time.Since(markedAt); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+	inUse, err = r.networkInUse(cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if inUse {
+		return r.keepNetwork(cr, timeout)
+	}
+
+	// A change of the network after the mark makes the delete conflict and the network is checked again
+	reqLogger.Info("Deleting the idle network created from the pool", "pool", poolName, "idleSince", since)
+	err = r.client.Delete(context.TODO(), cr, client.Preconditions{ResourceVersion: &cr.ResourceVersion})
+	if err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// keepNetwork removes the idle and draining annotations of a network in use
+func (r *ReconcileNetwork) keepNetwork(cr *k8sv1alpha1.Network, timeout time.Duration) (reconcile.Result, error) {
+	_, idle := cr.Annotations[idleSinceAnnotation]
+	_, draining := cr.Annotations[network.NetworkDrainingAnnotation]
+	if idle || draining {
+		delete(cr.Annotations, idleSinceAnnotation)
+		delete(cr.Annotations, network.NetworkDrainingAnnotation)
+		if err := r.client.Update(context.TODO(), cr); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{RequeueAfter: timeout}, nil
+}
+
+// networkInUse checks if a pod is attached to the network or a network chaining refers to it
+func (r *ReconcileNetwork) networkInUse(cr *k8sv1alpha1.Network) (bool, error) {
+	count, err := ovn.PodPortCount(cr.Name)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	chains := &k8sv1alpha1.NetworkChainingList{}
+	if err := r.client.List(context.TODO(), chains); err != nil {
+		return false, err
+	}
+	for i := range chains.Items {
+		if chainUsesNetwork(&chains.Items[i], cr.Name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// chainUsesNetwork checks if the network is a left, right or middle network of the chain
+func chainUsesNetwork(chain *k8sv1alpha1.NetworkChaining, name string) bool {
+	routing := chain.Spec.RoutingSpec
+	for _, n := range routing.LeftNetwork {
+		if n.NetworkName == name {
+			return true
+		}
+	}
+	for _, n := range routing.RightNetwork {
+		if n.NetworkName == name {
+			return true
+		}
+	}
+	for _, e := range strings.Split(routing.NetworkChain, ",") {
+		if strings.TrimSpace(e) == "net="+name {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/akraino-edge-stack/icn-nodus/pkg/utils"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -34,7 +36,7 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileNetwork{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	return &ReconcileNetwork{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor("network-controller")}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -50,6 +52,8 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 	_ = network.CheckandCreateNetworkPools()
+	// the cache of the manager is not started yet
+	_ = ovn.CheckAddressConflicts(mgr.GetAPIReader())
 
 	return nil
 }
@@ -61,8 +65,9 @@ var _ reconcile.Reconciler = &ReconcileNetwork{}
 type ReconcileNetwork struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client client.Client
-	scheme *runtime.Scheme
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 }
 type reconcileFun func(instance *k8sv1alpha1.Network, reqLogger logr.Logger) error
 
@@ -90,13 +95,19 @@ func (r *ReconcileNetwork) Reconcile(ctx context.Context, request reconcile.Requ
 	}
 	for _, fun := range []reconcileFun{
 		r.reconcileFinalizers,
+		r.reconcileConflicts,
 		r.createNetwork,
+		r.reconcileUsage,
 	} {
 		if err = fun(instance, reqLogger); err != nil {
 			return reconcile.Result{}, err
 		}
 	}
-	return reconcile.Result{}, nil
+	result, err := r.reconcileIdle(instance, reqLogger)
+	if err != nil {
+		return result, err
+	}
+	return withConflictRequeue(instance, withUsageRequeue(instance, result)), nil
 }
 
 const (
@@ -111,6 +122,14 @@ func (r *ReconcileNetwork) createNetwork(cr *k8sv1alpha1.Network, reqLogger logr
 	}
 	switch {
 	case cr.Spec.CniType == "ovn4nfv":
+		if rejectedForConflicts(cr) {
+			reqLogger.Error(fmt.Errorf("subnets overlap %s", strings.Join(cr.Status.Conflicts, ", ")), "Error Creating Network")
+			if cr.Status.State != k8sv1alpha1.CreateInternalError {
+				cr.Status.State = k8sv1alpha1.CreateInternalError
+				return r.client.Status().Update(context.TODO(), cr)
+			}
+			return nil
+		}
 		ovnCtl, err := ovn.GetOvnController()
 		if err != nil {
 			return err
@@ -171,6 +190,12 @@ func (r *ReconcileNetwork) reconcileFinalizers(instance *k8sv1alpha1.Network, re
 			if err = r.deleteNetwork(instance, reqLogger); err != nil {
 				reqLogger.Error(err, "Delete network")
 			}
+			// Return the subnet of a network created from a pool, the pool is updated with the
+			// resource version so retry until it succeeds
+			if err = network.ReleaseNetworkSubnet(instance); err != nil {
+				reqLogger.Error(err, "Releasing the network subnet to the pool")
+				return err
+			}
 			// Remove the finalizer even if Delete Network fails. Fatal error retry will not resolve
 			instance.ObjectMeta.Finalizers = utils.Remove(instance.ObjectMeta.Finalizers, nfnNetworkFinalizer)
 			if err = r.client.Update(context.TODO(), instance); err != nil {
@@ -0,0 +1,58 @@
+package network
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/network"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileUsage updates the IP address usage of the network in its status and metrics, a Warning
+// event is raised when the usage of a subnet goes above the usage threshold
+func (r *ReconcileNetwork) reconcileUsage(cr *k8sv1alpha1.Network, reqLogger logr.Logger) error {
+	if !cr.DeletionTimestamp.IsZero() {
+		network.DeleteUsageMetrics("Network", cr.Namespace, cr.Name, cr.Status.Subnets)
+		return nil
+	}
+	if cr.Spec.CniType != "ovn4nfv" || cr.Status.State != k8sv1alpha1.Created {
+		return nil
+	}
+
+	subnets, namespaces, err := ovn.NetworkUsage(cr.Name, cr.Spec.Ipv4Subnets, cr.Spec.Ipv6Subnets)
+	if err != nil {
+		// The usage is updated on the next period
+		reqLogger.Error(err, "Error getting the IP address usage of the network")
+		return nil
+	}
+
+	threshold := network.UsageThreshold()
+	for _, u := range network.CrossedUsageThreshold(cr.Status.Subnets, subnets) {
+		r.recorder.Eventf(cr, corev1.EventTypeWarning, network.UsageHighReason,
+			"Subnet %s uses %d of %d addresses, above the %d%% threshold", u.Subnet, u.Allocated, u.Allocated+u.Free, threshold)
+	}
+	network.RecordUsageMetrics("Network", cr.Namespace, cr.Name, subnets)
+
+	if reflect.DeepEqual(cr.Status.Subnets, subnets) && reflect.DeepEqual(cr.Status.Namespaces, namespaces) {
+		return nil
+	}
+	cr.Status.Subnets = subnets
+	cr.Status.Namespaces = namespaces
+	return r.client.Status().Update(context.TODO(), cr)
+}
+
+// withUsageRequeue requeues a created network for the next usage accounting
+func withUsageRequeue(cr *k8sv1alpha1.Network, result reconcile.Result) reconcile.Result {
+	if !cr.DeletionTimestamp.IsZero() || cr.Status.State != k8sv1alpha1.Created {
+		return result
+	}
+	if result.RequeueAfter == 0 || result.RequeueAfter > network.UsageInterval {
+		result.RequeueAfter = network.UsageInterval
+	}
+	return result
+}
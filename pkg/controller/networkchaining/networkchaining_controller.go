@@ -18,8 +18,12 @@ package networkchaining
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 
 	"github.com/akraino-edge-stack/icn-nodus/pkg/utils"
 
@@ -30,8 +34,12 @@ import (
 	notif "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify"
 
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -52,12 +60,12 @@ func Add(mgr manager.Manager) error {
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileNetworkChaining{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager) *ReconcileNetworkChaining {
+	return &ReconcileNetworkChaining{client: mgr.GetClient(), scheme: mgr.GetScheme(), routes: make(map[types.NamespacedName]string)}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r *ReconcileNetworkChaining) error {
 	// Create a new controller
 	c, err := controller.New("networkchaining-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -110,9 +118,100 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	if err != nil {
 		return err
 	}
+
+	// A pending chain is created when its network functions have a ready replica, the routes of a
+	// created chain are recalculated when a replica becomes ready, unready or is deleted
+	replicas := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, ok := e.ObjectOld.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			pod, ok := e.ObjectNew.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			return chaining.PodReady(oldPod) != chaining.PodReady(pod)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		return r.networkFunctionChains(obj.GetNamespace(), obj.GetLabels())
+	}), replicas)
+	if err != nil {
+		return err
+	}
+
+	// The deployments of the network functions are watched for their ready replicas
+	deployments := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldDeployment, ok := e.ObjectOld.(*appsv1.Deployment)
+			if !ok {
+				return false
+			}
+			deployment, ok := e.ObjectNew.(*appsv1.Deployment)
+			if !ok {
+				return false
+			}
+			return oldDeployment.Status.ReadyReplicas != deployment.Status.ReadyReplicas
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		deployment, ok := obj.(*appsv1.Deployment)
+		if !ok {
+			return nil
+		}
+		return r.networkFunctionChains(deployment.GetNamespace(), deployment.Spec.Template.GetLabels())
+	}), deployments)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
+// networkFunctionChains returns the chains with a network function selecting the pods with the
+// labels in the namespace
+func (r *ReconcileNetworkChaining) networkFunctionChains(namespace string, podLabels map[string]string) []reconcile.Request {
+	chains := &k8sv1alpha1.NetworkChainingList{}
+	if err := r.client.List(context.TODO(), chains); err != nil {
+		log.Error(err, "Failed to list the network chainings")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, cr := range chains.Items {
+		if !cr.DeletionTimestamp.IsZero() || cr.Spec.ChainType != "Routing" {
+			continue
+		}
+		nfNamespace := cr.Spec.RoutingSpec.Namespace
+		if nfNamespace == "" {
+			nfNamespace = cr.Namespace
+		}
+		if namespace != nfNamespace {
+			continue
+		}
+		for _, nf := range strings.Split(cr.Spec.RoutingSpec.NetworkChain, ",") {
+			selector, err := labels.Parse(nf)
+			if err != nil || selector.Empty() || !selector.Matches(labels.Set(podLabels)) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}})
+			break
+		}
+	}
+	return requests
+}
+
 // blank assignment to verify that ReconcileNetworkChaining implements reconcile.Reconciler
 var _ reconcile.Reconciler = &ReconcileNetworkChaining{}
 
@@ -122,6 +221,10 @@ type ReconcileNetworkChaining struct {
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
 	scheme *runtime.Scheme
+	// routes are the digests of the routes last sent for the created chains, the digests are
+	// read from the routesDigestAnnotation of the chains after a restart
+	routes map[types.NamespacedName]string
+	mutex  sync.Mutex
 }
 type reconcileFun func(instance *k8sv1alpha1.NetworkChaining, reqLogger logr.Logger) error
 
@@ -160,55 +263,72 @@ func (r *ReconcileNetworkChaining) Reconcile(ctx context.Context, request reconc
 			return reconcile.Result{}, err
 		}
 	}
+	if instance.Status.State == k8sv1alpha1.Pending || (instance.Status.State == k8sv1alpha1.Created && instance.Status.Reason != "") {
+		// The watches on the pods and deployments of the network functions requeue the chain once
+		// they are ready, in the meantime it is retried with backoff
+		return reconcile.Result{Requeue: true}, nil
+	}
 	log.V(1).Info("return nothing")
 	return reconcile.Result{}, nil
 }
 
 const (
 	nfnNetworkChainFinalizer = "nfnCleanUpNetworkChain"
+	// routesDigestAnnotation records the digest of the routes last sent for a created chain
+	routesDigestAnnotation = "k8s.plugin.opnfv.org/routes-digest"
 )
 
-func (r *ReconcileNetworkChaining) checkChain(cr *k8sv1alpha1.NetworkChaining) error {
+// checkChain checks that each network function of the chain has a ready replica. Until then the
+// chain is Pending, with the network functions it waits for in the status reason
+func (r *ReconcileNetworkChaining) checkChain(cr *k8sv1alpha1.NetworkChaining) (bool, error) {
 
 	err := chaining.CheckNetForNetPool(cr)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	podStatus, err := chaining.CheckSFCPodLabelStatus(cr)
+	podStatus, reason, err := chaining.CheckSFCPodLabelStatus(cr)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if podStatus != true {
-		cr.Status.State = k8sv1alpha1.Pending
-	} else {
-		cr.Status.State = k8sv1alpha1.Creating
+		return false, r.setPending(cr, reason)
 	}
 
-	err = r.client.Status().Update(context.TODO(), cr)
-	if err != nil {
-		return err
+	return true, r.setState(cr, k8sv1alpha1.Creating)
+}
+
+// setPending updates the chain to the Pending state with the reason
+func (r *ReconcileNetworkChaining) setPending(cr *k8sv1alpha1.NetworkChaining, reason string) error {
+	if cr.Status.State == k8sv1alpha1.Pending && cr.Status.Reason == reason {
+		return nil
 	}
+	log.Info("Network chain pending", "name", cr.Name, "reason", reason)
+	cr.Status.State = k8sv1alpha1.Pending
+	cr.Status.Reason = reason
+	return r.client.Status().Update(context.TODO(), cr)
+}
 
-	if cr.Status.State == k8sv1alpha1.Pending {
-		for {
-			ps, err := chaining.CheckSFCPodLabelStatus(cr)
-			if err != nil {
-				return err
-			}
-			if ps {
-				cr.Status.State = k8sv1alpha1.Creating
-				err = r.client.Status().Update(context.TODO(), cr)
-				if err != nil {
-					return err
-				}
-				break
-			}
-		}
+// setNotReady records in the reason of a created chain that a network function has no ready replica,
+// the chain keeps its state and its pods stay configured
+func (r *ReconcileNetworkChaining) setNotReady(cr *k8sv1alpha1.NetworkChaining, reason string) error {
+	if cr.Status.Reason == reason {
+		return nil
 	}
+	log.Info("Network chain not ready", "name", cr.Name, "reason", reason)
+	cr.Status.Reason = reason
+	return r.client.Status().Update(context.TODO(), cr)
+}
 
-	return nil
+// setState updates the state of the chain and clears the pending reason
+func (r *ReconcileNetworkChaining) setState(cr *k8sv1alpha1.NetworkChaining, state string) error {
+	if cr.Status.State == state && cr.Status.Reason == "" {
+		return nil
+	}
+	cr.Status.State = state
+	cr.Status.Reason = ""
+	return r.client.Status().Update(context.TODO(), cr)
 }
 
 func (r *ReconcileNetworkChaining) createChain(cr *k8sv1alpha1.NetworkChaining, reqLogger logr.Logger) error {
@@ -221,15 +341,15 @@ func (r *ReconcileNetworkChaining) createChain(cr *k8sv1alpha1.NetworkChaining,
 	}
 
 	if cr.Status.State == k8sv1alpha1.Created {
-		// Already created CR
+		// Already created CR, the routes follow the replicas of the network functions
 		log.V(1).Info("Already created chain")
-		return nil
+		return r.refreshChain(cr, reqLogger)
 	}
 
 	switch {
 	case cr.Spec.ChainType == "Routing":
-		err := r.checkChain(cr)
-		if err != nil {
+		ready, err := r.checkChain(cr)
+		if err != nil || !ready {
 			return err
 		}
 
@@ -245,6 +365,10 @@ func (r *ReconcileNetworkChaining) createChain(cr *k8sv1alpha1.NetworkChaining,
 		log.Info("Value of networkchain in chain creation", "cr.Spec.RoutingSpec.NetworkChain", cr.Spec.RoutingSpec.NetworkChain)
 
 		podnetworkList, routeList, err := chaining.CalculateRoutes(cr, false, false)
+		if pending, ok := err.(*chaining.PendingError); ok {
+			// A replica became unready since the chain was checked
+			return r.setPending(cr, pending.Reason)
+		}
 		if err != nil {
 			return err
 		}
@@ -261,6 +385,7 @@ func (r *ReconcileNetworkChaining) createChain(cr *k8sv1alpha1.NetworkChaining,
 		log.Info("value of the podnetworkList", "podnetworkList", podnetworkList)
 		log.Info("value of the cr.Status.State", "cr.Status.State", cr.Status.State)
 
+		cr.Status.Reason = ""
 		if cr.Status.State != k8sv1alpha1.CreateInternalError {
 			err = notif.SendPodNetworkNotif(podnetworkList, "create")
 			if err != nil {
@@ -275,6 +400,9 @@ func (r *ReconcileNetworkChaining) createChain(cr *k8sv1alpha1.NetworkChaining,
 		if err != nil {
 			return err
 		}
+		if cr.Status.State == k8sv1alpha1.Created {
+			return r.setRoutes(cr, routeList)
+		}
 		return nil
 		// Add other Chaining types here
 	}
@@ -282,6 +410,96 @@ func (r *ReconcileNetworkChaining) createChain(cr *k8sv1alpha1.NetworkChaining,
 	return fmt.Errorf("Chaining type not supported")
 }
 
+// routesDigest returns the digest of the routes of a chain
+func routesDigest(routeList []chaining.RoutingInfo) (string, error) {
+	b, err := json.Marshal(routeList)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
+}
+
+// sentRoutes returns the digest of the routes last sent for the chain, from the cache or from the
+// annotation of the chain after a restart
+func (r *ReconcileNetworkChaining) sentRoutes(cr *k8sv1alpha1.NetworkChaining) string {
+	key := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	digest, ok := r.routes[key]
+	if !ok {
+		digest = cr.Annotations[routesDigestAnnotation]
+		r.routes[key] = digest
+	}
+	return digest
+}
+
+// setRoutes records the digest of the routes sent for the chain in the cache and in the
+// annotation of the chain
+func (r *ReconcileNetworkChaining) setRoutes(cr *k8sv1alpha1.NetworkChaining, routeList []chaining.RoutingInfo) error {
+	digest, err := routesDigest(routeList)
+	if err != nil {
+		return err
+	}
+	key := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	if cr.Annotations[routesDigestAnnotation] != digest {
+		patch := client.MergeFrom(cr.DeepCopy())
+		if cr.Annotations == nil {
+			cr.Annotations = make(map[string]string)
+		}
+		cr.Annotations[routesDigestAnnotation] = digest
+		if err := r.client.Patch(context.TODO(), cr, patch); err != nil {
+			return err
+		}
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.routes[key] = digest
+	return nil
+}
+
+// forgetRoutes removes the chain from the cache of the routes
+func (r *ReconcileNetworkChaining) forgetRoutes(cr *k8sv1alpha1.NetworkChaining) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.routes, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name})
+}
+
+// refreshChain recalculates the routes of a created chain over the ready replicas of its network
+// functions. The routes are sent when they differ from the routes last sent for the chain, the
+// next hops of the ECMP routes are replaced
+func (r *ReconcileNetworkChaining) refreshChain(cr *k8sv1alpha1.NetworkChaining, reqLogger logr.Logger) error {
+	if cr.Spec.ChainType != "Routing" {
+		return nil
+	}
+	routeList, err := chaining.RecalculateRoutes(cr)
+	if pending, ok := err.(*chaining.PendingError); ok {
+		// The chain is marked not ready until the network function has a ready replica again,
+		// the routes are recalculated then
+		reqLogger.Info("Routes of the chain not recalculated", "reason", pending.Reason)
+		return r.setNotReady(cr, pending.Reason)
+	}
+	if err != nil {
+		reqLogger.Error(err, "Error recalculating the routes")
+		return err
+	}
+
+	digest, err := routesDigest(routeList)
+	if err != nil {
+		return err
+	}
+	if digest != r.sentRoutes(cr) {
+		reqLogger.Info("Routes of the chain changed", "routes", len(routeList))
+		if err = notif.SendRouteNotif(routeList, "create"); err != nil {
+			reqLogger.Error(err, "Error Sending route Message")
+			return err
+		}
+		if err = r.setRoutes(cr, routeList); err != nil {
+			return err
+		}
+	}
+	return r.setState(cr, k8sv1alpha1.Created)
+}
+
 func (r *ReconcileNetworkChaining) deleteChain(cr *k8sv1alpha1.NetworkChaining, reqLogger logr.Logger) error {
 	log.V(1).Info("Entering the deletechain")
 
@@ -290,6 +508,7 @@ func (r *ReconcileNetworkChaining) deleteChain(cr *k8sv1alpha1.NetworkChaining,
 		log.V(1).Info("Already deleted chain")
 		return nil
 	}
+	r.forgetRoutes(cr)
 
 	switch {
 	case cr.Spec.ChainType == "Routing":
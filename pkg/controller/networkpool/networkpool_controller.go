@@ -0,0 +1,103 @@
+package networkpool
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/network"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("networkpool_controller")
+
+// Add creates a new NetworkPool Controller and adds it to the Manager. The subnets of the pools are
+// computed from the NetworkPool objects each time a network is created, the controller keeps the
+// counters of the pool status in line with changes of the pool spec.
+func Add(mgr manager.Manager) error {
+	r := &ReconcileNetworkPool{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor("networkpool-controller")}
+	c, err := controller.New("networkpool-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource NetworkPool
+	err = c.Watch(&source.Kind{Type: &k8sv1alpha1.NetworkPool{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// blank assignment to verify that ReconcileNetworkPool implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileNetworkPool{}
+
+// ReconcileNetworkPool reconciles a NetworkPool object
+type ReconcileNetworkPool struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+// Reconcile reads that state of the cluster for a NetworkPool object and updates the NetworkPool.Status
+// to what is in the NetworkPool.Spec
+func (r *ReconcileNetworkPool) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Name", request.Name)
+	reqLogger.V(1).Info("Reconciling NetworkPool")
+
+	instance := &k8sv1alpha1.NetworkPool{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: request.Name}, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	r.reportConflicts(instance, reqLogger)
+
+	status := instance.Status.DeepCopy()
+	if err := network.RefreshPoolStatus(instance); err != nil {
+		// Invalid spec, nothing to do until the pool is updated
+		reqLogger.Error(err, "Invalid NetworkPool")
+		return reconcile.Result{}, nil
+	}
+	if reflect.DeepEqual(status, &instance.Status) {
+		return reconcile.Result{}, nil
+	}
+
+	reqLogger.Info("Updating NetworkPool status", "total", instance.Status.Total, "free", instance.Status.Free)
+	// A conflict with an allocation requeues the request
+	return reconcile.Result{}, r.client.Status().Update(ctx, instance)
+}
+
+// reportConflicts raises a Warning event for each address source overlapping the supernets of the
+// pool. The networks created from the pool do not conflict with it
+func (r *ReconcileNetworkPool) reportConflicts(pool *k8sv1alpha1.NetworkPool, reqLogger logr.Logger) {
+	registry, err := ovn.NewAddressRegistry(context.TODO(), r.client)
+	if err != nil {
+		reqLogger.Error(err, "Error reading the address space of the cluster")
+		return
+	}
+	conflicts, warnings := registry.PoolConflicts(pool)
+	for _, c := range append(conflicts, warnings...) {
+		reqLogger.Info("Supernet of the pool overlaps", "conflict", c)
+		r.recorder.Eventf(pool, corev1.EventTypeWarning, ovn.AddressConflictReason, "Supernet overlaps %s", c)
+	}
+}
@@ -0,0 +1,197 @@
+package pod
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
+
+	chaining "github.com/akraino-edge-stack/icn-nodus/internal/pkg/utils"
+
+	notif "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify"
+	pb "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify/proto"
+
+	"github.com/mitchellh/mapstructure"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// isHotplugInterface returns true for the pod interfaces managed through the nfn-network
+// annotation of a running pod. The default interface and the interfaces added by the
+// network chaining are left untouched.
+func isHotplugInterface(ifName string) bool {
+	return ifName != "" && ifName != "*" && !strings.HasPrefix(ifName, ovn.SFCnetworkIntefacePrefixes)
+}
+
+func interfaceField(iface map[string]interface{}, field string) string {
+	value, _ := iface[field].(string)
+	return value
+}
+
+// desiredInterfaces returns the interfaces requested in the nfn-network annotation, keyed by
+// interface name. It returns nil if the annotation is not handled by ovn4nfv.
+func (r *ReconcilePod) desiredInterfaces(pod *corev1.Pod) (map[string]map[string]interface{}, error) {
+	desired := map[string]map[string]interface{}{}
+	if _, ok := pod.Annotations[nfnNetworkAnnotation]; !ok {
+		return desired, nil
+	}
+
+	nfn, err := r.readPodAnnotation(pod)
+	if err != nil {
+		return nil, err
+	}
+	if nfn.Type != "ovn4nfv" {
+		return nil, nil
+	}
+
+	for _, iface := range nfn.Interface {
+		var net ovn.NetInterface
+		if err := mapstructure.Decode(iface, &net); err != nil {
+			log.Error(err, "mapstruct error", "network", iface)
+			return nil, err
+		}
+		if isHotplugInterface(net.Interface) {
+			desired[net.Interface] = iface
+		}
+	}
+	return desired, nil
+}
+
+// diffInterfaces compares the nfn-network annotation with the ovnInterfaces annotation of the pod
+// and returns the interfaces to add, to delete and to keep
+func (r *ReconcilePod) diffInterfaces(pod *corev1.Pod) (add []map[string]interface{}, del, keep []*ovn.PodInterface, err error) {
+	desired, err := r.desiredInterfaces(pod)
+	if err != nil || desired == nil {
+		return nil, nil, nil, err
+	}
+
+	current, err := ovn.DecodePodInterfaces(pod.Annotations[ovn.Ovn4nfvAnnotationTag])
+	if err != nil {
+		log.Error(err, "Invalid ovnInterfaces annotation", "pod", pod.Name)
+		return nil, nil, nil, err
+	}
+
+	attached := map[string]bool{}
+	for _, iface := range current {
+		if !isHotplugInterface(iface.Interface) {
+			keep = append(keep, iface)
+			continue
+		}
+		want, ok := desired[iface.Interface]
+		// interfaces attached before the network was recorded in the annotation are matched by name
+		if ok && (iface.Network == "" || iface.Network == interfaceField(want, "name")) {
+			keep = append(keep, iface)
+			attached[iface.Interface] = true
+			continue
+		}
+		del = append(del, iface)
+	}
+
+	for ifName, iface := range desired {
+		if !attached[ifName] {
+			add = append(add, iface)
+		}
+	}
+	return add, del, keep, nil
+}
+
+// needsHotplug returns true if the interfaces of the running pod differ from its nfn-network annotation
+func (r *ReconcilePod) needsHotplug(pod *corev1.Pod) bool {
+	add, del, _, err := r.diffInterfaces(pod)
+	if err != nil {
+		return false
+	}
+	return len(add) != 0 || len(del) != 0
+}
+
+// containerID returns the ID of the first container of the pod without the runtime prefix
+func containerID(pod *corev1.Pod) string {
+	id := pod.Status.ContainerStatuses[0].ContainerID
+	if i := strings.Index(id, "://"); i >= 0 {
+		return id[i+3:]
+	}
+	return id
+}
+
+func podNetworkInfo(pod *corev1.Pod, ifaces []*ovn.PodInterface) ([]chaining.PodNetworkInfo, error) {
+	netinfo, err := json.Marshal(ifaces)
+	if err != nil {
+		return nil, err
+	}
+	return []chaining.PodNetworkInfo{{
+		Name:        pod.GetName(),
+		Namespace:   pod.GetNamespace(),
+		Id:          containerID(pod),
+		Node:        pod.Spec.NodeName,
+		NetworkInfo: string(netinfo),
+	}}, nil
+}
+
+// updateLogicalPorts hot-plugs and unplugs the interfaces of a running pod to match its
+// nfn-network annotation and updates the ovnInterfaces annotation with the result
+func (r *ReconcilePod) updateLogicalPorts(pod *corev1.Pod) error {
+	add, del, keep, err := r.diffInterfaces(pod)
+	if err != nil {
+		return err
+	}
+	if len(add) == 0 && len(del) == 0 {
+		return nil
+	}
+	if len(pod.Status.ContainerStatuses) == 0 || pod.Status.ContainerStatuses[0].ContainerID == "" {
+		return fmt.Errorf("No container found for the pod %s", pod.GetName())
+	}
+	// The missing capability is reported in the nfn-agent condition of the node
+	if !notif.NodeSupports(pod.Spec.NodeName, pb.CapabilityHotplug) {
+		log.Info("The nfn-agent of the node doesn't support hot-plug", "pod", pod.GetName(), "node", pod.Spec.NodeName)
+		return nil
+	}
+
+	ovnCtl, err := ovn.GetOvnController()
+	if err != nil {
+		return err
+	}
+
+	if len(del) != 0 {
+		pni, err := podNetworkInfo(pod, del)
+		if err != nil {
+			return err
+		}
+		if err := notif.SendDeletePodNetworkNotif(pni, "delete"); err != nil {
+			log.Error(err, "Error Sending pod network Message")
+			return err
+		}
+		for _, iface := range del {
+			if err := ovnCtl.DeleteLogicalPort(pod.GetName(), pod.GetNamespace(), iface.Interface); err != nil {
+				keep = append(keep, iface)
+				continue
+			}
+			log.Info("Interface removed from the pod", "pod", pod.GetName(), "interface", iface.Interface)
+		}
+	}
+
+	if len(add) != 0 {
+		_, value := ovnCtl.AddLogicalPorts(pod, add, true)
+		if len(value) == 0 {
+			return fmt.Errorf("Failed to add ports")
+		}
+		added, err := ovn.DecodePodInterfaces(value)
+		if err != nil {
+			return err
+		}
+		pni, err := podNetworkInfo(pod, added)
+		if err != nil {
+			return err
+		}
+		if err := notif.SendPodNetworkNotif(pni, "create"); err != nil {
+			log.Error(err, "Error Sending pod network Message")
+			for _, iface := range added {
+				ovnCtl.DeleteLogicalPort(pod.GetName(), pod.GetNamespace(), iface.Interface)
+			}
+			return err
+		}
+		keep = append(keep, added...)
+		log.Info("Interfaces added to the pod", "pod", pod.GetName(), "interfaces", added)
+	}
+
+	return r.setPodInterfaces(pod, keep)
+}
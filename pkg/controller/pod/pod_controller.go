@@ -10,6 +10,7 @@ import (
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
 
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/kube"
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
 
 	chaining "github.com/akraino-edge-stack/icn-nodus/internal/pkg/utils"
 
@@ -83,6 +84,11 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 				if obj.Status.Phase == corev1.PodRunning {
 					log.V(1).Info("Pod Status Phase", "Pod name", obj.GetName(), "obj.Status.Phase", obj.Status.Phase)
 
+					// The nfn-network annotation was edited or the interfaces changed, reconcile the pod
+					if r.(*ReconcilePod).needsHotplug(obj) || r.(*ReconcilePod).networkStatusOutdated(obj) {
+						return true
+					}
+
 					// If pod was not previously processed the network policies should be refreshed to reflect the changes
 					if _, exists := annotation[networkpolicy.NodusNetworkPolicyAnnotationTag]; !exists {
 						if err := networkpolicy.RefreshNetworkPolicies(&mgr); err != nil {
@@ -220,6 +226,18 @@ func (r *ReconcilePod) Reconcile(ctx context.Context, request reconcile.Request)
 		return reconcile.Result{}, err
 	}
 
+	if _, ok := instance.Annotations[ovn.Ovn4nfvAnnotationTag]; ok && instance.Status.Phase == corev1.PodRunning {
+		err = r.updateLogicalPorts(instance)
+		if err != nil {
+			// Requeue the object
+			return reconcile.Result{}, err
+		}
+		err = r.syncNetworkStatus(instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	err = r.checkforsfc(instance)
 	if err != nil {
 		// Requeue the object
@@ -231,16 +249,101 @@ func (r *ReconcilePod) Reconcile(ctx context.Context, request reconcile.Request)
 
 // annotatePod annotates pod with the given annotations
 func (r *ReconcilePod) setPodAnnotation(pod *corev1.Pod, key, value string) error {
+	return r.setPodAnnotations(pod, map[string]string{key: value})
+}
 
-	patchData := fmt.Sprintf(`{"metadata":{"annotations":{"%s":"%s"}}}`, key, value)
-	err := r.client.Patch(context.TODO(), pod, client.RawPatch(types.MergePatchType, []byte(patchData)))
+func (r *ReconcilePod) setPodAnnotations(pod *corev1.Pod, annotations map[string]string) error {
+
+	patchData, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
 	if err != nil {
-		log.Error(err, "Updating pod failed", "pod", pod, "key", key, "value", value)
+		return err
+	}
+	err = r.client.Patch(context.TODO(), pod, client.RawPatch(types.MergePatchType, patchData))
+	if err != nil {
+		log.Error(err, "Updating pod failed", "pod", pod, "annotations", annotations)
 		return err
 	}
 	return nil
 }
 
+// setPodInterfaces sets the ovnInterfaces and the network-status annotations of the pod
+func (r *ReconcilePod) setPodInterfaces(pod *corev1.Pod, podInterfaces []*ovn.PodInterface) error {
+	value, err := json.Marshal(podInterfaces)
+	if err != nil {
+		return err
+	}
+	status, err := ovn.GetNetworkStatus(podInterfaces, r.networkDNS())
+	if err != nil {
+		return err
+	}
+	return r.setPodAnnotations(pod, map[string]string{
+		ovn.Ovn4nfvAnnotationTag:       string(value),
+		ovn.NetworkStatusAnnotationTag: status,
+	})
+}
+
+// networkDNS returns the DNS of the networks the pod interfaces can be attached to, by name
+func (r *ReconcilePod) networkDNS() map[string]ovn.DNS {
+	dns := make(map[string]ovn.DNS)
+	networks := &k8sv1alpha1.NetworkList{}
+	if err := r.client.List(context.TODO(), networks); err != nil {
+		log.Error(err, "Failed to list the networks")
+	}
+	for _, item := range networks.Items {
+		dns[item.Name] = ovn.NetworkDNS(item.Spec.DNS)
+	}
+	providerNetworks := &k8sv1alpha1.ProviderNetworkList{}
+	if err := r.client.List(context.TODO(), providerNetworks); err != nil {
+		log.Error(err, "Failed to list the provider networks")
+	}
+	for _, item := range providerNetworks.Items {
+		dns[item.Name] = ovn.NetworkDNS(item.Spec.DNS)
+	}
+	clusterProviderNetworks := &k8sv1alpha1.ClusterProviderNetworkList{}
+	if err := r.client.List(context.TODO(), clusterProviderNetworks); err != nil {
+		log.Error(err, "Failed to list the cluster provider networks")
+	}
+	for _, item := range clusterProviderNetworks.Items {
+		dns[item.Name] = ovn.NetworkDNS(item.Spec.DNS)
+	}
+	return dns
+}
+
+// networkStatus returns the network-status annotation matching the ovnInterfaces annotation of the pod
+func (r *ReconcilePod) networkStatus(pod *corev1.Pod) (string, error) {
+	podInterfaces, err := ovn.DecodePodInterfaces(pod.Annotations[ovn.Ovn4nfvAnnotationTag])
+	if err != nil {
+		return "", err
+	}
+	return ovn.GetNetworkStatus(podInterfaces, r.networkDNS())
+}
+
+// networkStatusOutdated returns true if the network-status annotation doesn't reflect the pod
+// interfaces, e.g. after the network chaining added an interface
+func (r *ReconcilePod) networkStatusOutdated(pod *corev1.Pod) bool {
+	status, err := r.networkStatus(pod)
+	if err != nil {
+		return false
+	}
+	return pod.Annotations[ovn.NetworkStatusAnnotationTag] != status
+}
+
+// syncNetworkStatus updates the network-status annotation of the pod if it is outdated
+func (r *ReconcilePod) syncNetworkStatus(pod *corev1.Pod) error {
+	status, err := r.networkStatus(pod)
+	if err != nil {
+		return err
+	}
+	if pod.Annotations[ovn.NetworkStatusAnnotationTag] == status {
+		return nil
+	}
+	return r.setPodAnnotation(pod, ovn.NetworkStatusAnnotationTag, status)
+}
+
 func (r *ReconcilePod) checkforsfc(pod *corev1.Pod) error {
 
 	// Get a config to talk to the apiserver
@@ -283,7 +386,11 @@ func (r *ReconcilePod) addLogicalPorts(pod *corev1.Pod) error {
 		}
 		key, value := ovnCtl.AddLogicalPorts(pod, nfn.Interface, false)
 		if len(key) > 0 {
-			return r.setPodAnnotation(pod, key, value)
+			podInterfaces, err := ovn.DecodePodInterfaces(value)
+			if err != nil {
+				return err
+			}
+			return r.setPodInterfaces(pod, podInterfaces)
 		}
 		return fmt.Errorf("Failed to add ports")
 	default:
@@ -0,0 +1,147 @@
+package providernetwork
+
+import (
+	"context"
+
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// AddCluster creates a new ClusterProviderNetwork Controller and adds it to the Manager. The
+// ClusterProviderNetworks are handled as ProviderNetworks without namespace.
+func AddCluster(mgr manager.Manager) error {
+	r := &ReconcileClusterProviderNetwork{
+		ReconcileProviderNetwork: ReconcileProviderNetwork{
+			client:   mgr.GetClient(),
+			scheme:   mgr.GetScheme(),
+			recorder: mgr.GetEventRecorderFor("clusterprovidernetwork-controller"),
+		},
+	}
+	c, err := controller.New("clusterprovidernetwork-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource ClusterProviderNetwork
+	err = c.Watch(&source.Kind{Type: &k8sv1alpha1.ClusterProviderNetwork{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// blank assignment to verify that ReconcileClusterProviderNetwork implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileClusterProviderNetwork{}
+
+// ReconcileClusterProviderNetwork reconciles a ClusterProviderNetwork object
+type ReconcileClusterProviderNetwork struct {
+	ReconcileProviderNetwork
+}
+
+// Reconcile reads that state of the cluster for a ClusterProviderNetwork object and makes changes
+// based on the state read and what is in the ClusterProviderNetwork.Spec
+func (r *ReconcileClusterProviderNetwork) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Name", request.Name)
+	reqLogger.V(1).Info("Reconciling ClusterProviderNetwork")
+
+	// Fetch the ClusterProviderNetwork instance
+	instance := &k8sv1alpha1.ClusterProviderNetwork{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: request.Name}, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+	pn := instance.ProviderNetwork()
+	for _, fun := range []reconcileFun{
+		r.reconcileFinalizers,
+		r.reconcileConflicts,
+		r.createNetwork,
+		r.reconcileUsage,
+	} {
+		if err = fun(pn, reqLogger); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return conflictResult(pn, usageResult(pn)), nil
+}
+
+// clusterObject returns the ClusterProviderNetwork of a ProviderNetwork without namespace
+func clusterObject(pn *k8sv1alpha1.ProviderNetwork) *k8sv1alpha1.ClusterProviderNetwork {
+	return &k8sv1alpha1.ClusterProviderNetwork{
+		ObjectMeta: pn.ObjectMeta,
+		Spec:       pn.Spec,
+		Status:     pn.Status,
+	}
+}
+
+// update updates the provider network, or its ClusterProviderNetwork if it has no namespace
+func (r *ReconcileProviderNetwork) update(pn *k8sv1alpha1.ProviderNetwork) error {
+	if pn.Namespace != "" {
+		return r.client.Update(context.TODO(), pn)
+	}
+	cpn := clusterObject(pn)
+	if err := r.client.Update(context.TODO(), cpn); err != nil {
+		return err
+	}
+	pn.ObjectMeta = cpn.ObjectMeta
+	return nil
+}
+
+// updateStatus updates the status of the provider network, or of its ClusterProviderNetwork if
+// it has no namespace
+func (r *ReconcileProviderNetwork) updateStatus(pn *k8sv1alpha1.ProviderNetwork) error {
+	if pn.Namespace != "" {
+		return r.client.Status().Update(context.TODO(), pn)
+	}
+	cpn := clusterObject(pn)
+	if err := r.client.Status().Update(context.TODO(), cpn); err != nil {
+		return err
+	}
+	pn.ObjectMeta = cpn.ObjectMeta
+	return nil
+}
+
+// nameOwner returns the provider network or ClusterProviderNetwork created before pn with the
+// same name, if any. The name of a provider network is the name of its OVN logical switch so
+// only the oldest of them creates and deletes the network.
+func (r *ReconcileProviderNetwork) nameOwner(pn *k8sv1alpha1.ProviderNetwork) (string, error) {
+	var owners []*k8sv1alpha1.ProviderNetwork
+	pnList := &k8sv1alpha1.ProviderNetworkList{}
+	if err := r.client.List(context.TODO(), pnList); err != nil {
+		return "", err
+	}
+	for i := range pnList.Items {
+		owners = append(owners, &pnList.Items[i])
+	}
+	cpn := &k8sv1alpha1.ClusterProviderNetwork{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: pn.Name}, cpn)
+	if err == nil {
+		owners = append(owners, cpn.ProviderNetwork())
+	} else if !errors.IsNotFound(err) {
+		return "", err
+	}
+
+	for _, owner := range owners {
+		if owner.Name != pn.Name || owner.Namespace == pn.Namespace {
+			continue
+		}
+		if owner.OwnsNameBefore(pn) {
+			if owner.Namespace == "" {
+				return "ClusterProviderNetwork " + owner.Name, nil
+			}
+			return "ProviderNetwork " + owner.Namespace + "/" + owner.Name, nil
+		}
+	}
+	return "", nil
+}
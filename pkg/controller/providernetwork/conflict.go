@@ -0,0 +1,65 @@
+package providernetwork
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/network"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+	"github.com/akraino-edge-stack/icn-nodus/pkg/utils"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileConflicts checks the subnets of the provider network against the address space of the
+// cluster. The overlapping sources are recorded in the status of the provider network and a Warning
+// event names the new ones. A provider network with conflicts is not created, a created provider
+// network is only reported
+func (r *ReconcileProviderNetwork) reconcileConflicts(cr *k8sv1alpha1.ProviderNetwork, reqLogger logr.Logger) error {
+	if !cr.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	registry, err := ovn.NewAddressRegistry(context.TODO(), r.client)
+	if err != nil {
+		reqLogger.Error(err, "Error reading the address space of the cluster")
+		return err
+	}
+	conflicts, warnings := registry.ProviderNetworkConflicts(usageKind(cr), cr)
+	if cr.Status.State != k8sv1alpha1.Created {
+		for _, w := range warnings {
+			r.recorder.Eventf(eventObject(cr), corev1.EventTypeWarning, ovn.AddressConflictReason, "Subnets overlap %s", w)
+		}
+	}
+	for _, c := range conflicts {
+		if !utils.Contains(cr.Status.Conflicts, c) {
+			r.recorder.Eventf(eventObject(cr), corev1.EventTypeWarning, ovn.AddressConflictReason, "Subnets overlap %s", c)
+		}
+	}
+	if reflect.DeepEqual(cr.Status.Conflicts, conflicts) {
+		return nil
+	}
+	if len(conflicts) > 0 {
+		reqLogger.Info("Subnets of the provider network overlap", "conflicts", strings.Join(conflicts, ", "))
+	}
+	cr.Status.Conflicts = conflicts
+	return r.updateStatus(cr)
+}
+
+// rejectedForConflicts checks if the provider network must not be created for its conflicts
+func rejectedForConflicts(pn *k8sv1alpha1.ProviderNetwork) bool {
+	return len(pn.Status.Conflicts) > 0 && pn.Status.State != k8sv1alpha1.Created
+}
+
+// conflictResult requeues a provider network rejected for its conflicts, it is created once they
+// are resolved
+func conflictResult(pn *k8sv1alpha1.ProviderNetwork, result reconcile.Result) reconcile.Result {
+	if !pn.DeletionTimestamp.IsZero() || !rejectedForConflicts(pn) {
+		return result
+	}
+	return reconcile.Result{RequeueAfter: network.UsageInterval}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	notif "github.com/akraino-edge-stack/icn-nodus/internal/pkg/nfnNotify"
 	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
@@ -13,6 +14,7 @@ import (
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -32,7 +34,7 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileProviderNetwork{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	return &ReconcileProviderNetwork{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor("providernetwork-controller")}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -58,8 +60,9 @@ var _ reconcile.Reconciler = &ReconcileProviderNetwork{}
 type ReconcileProviderNetwork struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client client.Client
-	scheme *runtime.Scheme
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 }
 type reconcileFun func(instance *k8sv1alpha1.ProviderNetwork, reqLogger logr.Logger) error
 
@@ -69,7 +72,7 @@ type reconcileFun func(instance *k8sv1alpha1.ProviderNetwork, reqLogger logr.Log
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileProviderNetwork) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
-	reqLogger.Info("Reconciling ProviderNetwork")
+	reqLogger.V(1).Info("Reconciling ProviderNetwork")
 
 	// Fetch the ProviderNetwork instance
 	instance := &k8sv1alpha1.ProviderNetwork{}
@@ -86,13 +89,15 @@ func (r *ReconcileProviderNetwork) Reconcile(ctx context.Context, request reconc
 	}
 	for _, fun := range []reconcileFun{
 		r.reconcileFinalizers,
+		r.reconcileConflicts,
 		r.createNetwork,
+		r.reconcileUsage,
 	} {
 		if err = fun(instance, reqLogger); err != nil {
 			return reconcile.Result{}, err
 		}
 	}
-	return reconcile.Result{}, nil
+	return conflictResult(instance, usageResult(instance)), nil
 }
 
 const (
@@ -107,6 +112,24 @@ func (r *ReconcileProviderNetwork) createNetwork(cr *k8sv1alpha1.ProviderNetwork
 	}
 	switch {
 	case cr.Spec.CniType == "ovn4nfv":
+		if owner, err := r.nameOwner(cr); err != nil {
+			return err
+		} else if owner != "" {
+			reqLogger.Error(fmt.Errorf("provider network name used by %s", owner), "Error Creating Network")
+			if cr.Status.State != k8sv1alpha1.CreateInternalError {
+				cr.Status.State = k8sv1alpha1.CreateInternalError
+				return r.updateStatus(cr)
+			}
+			return nil
+		}
+		if rejectedForConflicts(cr) {
+			reqLogger.Error(fmt.Errorf("subnets overlap %s", strings.Join(cr.Status.Conflicts, ", ")), "Error Creating Network")
+			if cr.Status.State != k8sv1alpha1.CreateInternalError {
+				cr.Status.State = k8sv1alpha1.CreateInternalError
+				return r.updateStatus(cr)
+			}
+			return nil
+		}
 		ovnCtl, err := ovn.GetOvnController()
 		if err != nil {
 			return err
@@ -124,7 +147,7 @@ func (r *ReconcileProviderNetwork) createNetwork(cr *k8sv1alpha1.ProviderNetwork
 			} else {
 				cr.Status.State = k8sv1alpha1.Created
 			}
-			err = r.client.Status().Update(context.TODO(), cr)
+			err = r.updateStatus(cr)
 			if err != nil {
 				return err
 			}
@@ -153,7 +176,7 @@ func (r *ReconcileProviderNetwork) deleteNetwork(cr *k8sv1alpha1.ProviderNetwork
 			// Log the error
 			reqLogger.Error(err, "Error Delete Network")
 			cr.Status.State = k8sv1alpha1.DeleteInternalError
-			err = r.client.Status().Update(context.TODO(), cr)
+			err = r.updateStatus(cr)
 			if err != nil {
 				return err
 			}
@@ -172,12 +195,19 @@ func (r *ReconcileProviderNetwork) reconcileFinalizers(instance *k8sv1alpha1.Pro
 		// Instance marked for deletion
 		if utils.Contains(instance.ObjectMeta.Finalizers, nfnProviderNetworkFinalizer) {
 			reqLogger.V(1).Info("Finalizer found - delete network")
-			if err = r.deleteNetwork(instance, reqLogger); err != nil {
+			// The network of the same name created by another provider network is kept
+			owner, err := r.nameOwner(instance)
+			if err != nil {
+				return err
+			}
+			if owner != "" {
+				reqLogger.Info("Provider network name used by another provider network, skip delete", "owner", owner)
+			} else if err = r.deleteNetwork(instance, reqLogger); err != nil {
 				reqLogger.Error(err, "Delete network")
 			}
 			// Remove the finalizer even if Delete Network fails. Fatal error retry will not resolve
 			instance.ObjectMeta.Finalizers = utils.Remove(instance.ObjectMeta.Finalizers, nfnProviderNetworkFinalizer)
-			if err = r.client.Update(context.TODO(), instance); err != nil {
+			if err = r.update(instance); err != nil {
 				reqLogger.Error(err, "Removing Finalize")
 				return err
 			}
@@ -187,7 +217,7 @@ func (r *ReconcileProviderNetwork) reconcileFinalizers(instance *k8sv1alpha1.Pro
 		// If finalizer doesn't exist add it
 		if !utils.Contains(instance.GetFinalizers(), nfnProviderNetworkFinalizer) {
 			instance.SetFinalizers(append(instance.GetFinalizers(), nfnProviderNetworkFinalizer))
-			if err = r.client.Update(context.TODO(), instance); err != nil {
+			if err = r.update(instance); err != nil {
 				reqLogger.Error(err, "Adding Finalize")
 				return err
 			}
@@ -0,0 +1,73 @@
+package providernetwork
+
+import (
+	"reflect"
+
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/network"
+	"github.com/akraino-edge-stack/icn-nodus/internal/pkg/ovn"
+	k8sv1alpha1 "github.com/akraino-edge-stack/icn-nodus/pkg/apis/k8s/v1alpha1"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileUsage updates the IP address usage of the provider network in its status and metrics, a
+// Warning event is raised when the usage of a subnet goes above the usage threshold
+func (r *ReconcileProviderNetwork) reconcileUsage(cr *k8sv1alpha1.ProviderNetwork, reqLogger logr.Logger) error {
+	kind := usageKind(cr)
+	if !cr.DeletionTimestamp.IsZero() {
+		network.DeleteUsageMetrics(kind, cr.Namespace, cr.Name, cr.Status.Subnets)
+		return nil
+	}
+	if cr.Spec.CniType != "ovn4nfv" || cr.Status.State != k8sv1alpha1.Created {
+		return nil
+	}
+
+	subnets, namespaces, err := ovn.NetworkUsage(cr.Name, cr.Spec.Ipv4Subnets, cr.Spec.Ipv6Subnets)
+	if err != nil {
+		// The usage is updated on the next period
+		reqLogger.Error(err, "Error getting the IP address usage of the provider network")
+		return nil
+	}
+
+	threshold := network.UsageThreshold()
+	for _, u := range network.CrossedUsageThreshold(cr.Status.Subnets, subnets) {
+		r.recorder.Eventf(eventObject(cr), corev1.EventTypeWarning, network.UsageHighReason,
+			"Subnet %s uses %d of %d addresses, above the %d%% threshold", u.Subnet, u.Allocated, u.Allocated+u.Free, threshold)
+	}
+	network.RecordUsageMetrics(kind, cr.Namespace, cr.Name, subnets)
+
+	if reflect.DeepEqual(cr.Status.Subnets, subnets) && reflect.DeepEqual(cr.Status.Namespaces, namespaces) {
+		return nil
+	}
+	cr.Status.Subnets = subnets
+	cr.Status.Namespaces = namespaces
+	return r.updateStatus(cr)
+}
+
+// usageKind returns the kind of the provider network in the usage metrics
+func usageKind(pn *k8sv1alpha1.ProviderNetwork) string {
+	if pn.Namespace == "" {
+		return "ClusterProviderNetwork"
+	}
+	return "ProviderNetwork"
+}
+
+// eventObject returns the object of the events of the provider network, the ClusterProviderNetwork
+// if it has no namespace
+func eventObject(pn *k8sv1alpha1.ProviderNetwork) runtime.Object {
+	if pn.Namespace == "" {
+		return clusterObject(pn)
+	}
+	return pn
+}
+
+// usageResult requeues a created provider network for the next usage accounting
+func usageResult(pn *k8sv1alpha1.ProviderNetwork) reconcile.Result {
+	if !pn.DeletionTimestamp.IsZero() || pn.Status.State != k8sv1alpha1.Created {
+		return reconcile.Result{}
+	}
+	return reconcile.Result{RequeueAfter: network.UsageInterval}
+}
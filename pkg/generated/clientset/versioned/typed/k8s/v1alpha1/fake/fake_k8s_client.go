@@ -28,6 +28,14 @@ type FakeK8sV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeK8sV1alpha1) ClusterProviderNetworks() v1alpha1.ClusterProviderNetworkInterface {
+	return &FakeClusterProviderNetworks{c}
+}
+
+func (c *FakeK8sV1alpha1) IPReservations(namespace string) v1alpha1.IPReservationInterface {
+	return &FakeIPReservations{c, namespace}
+}
+
 func (c *FakeK8sV1alpha1) Networks(namespace string) v1alpha1.NetworkInterface {
 	return &FakeNetworks{c, namespace}
 }
@@ -36,6 +44,10 @@ func (c *FakeK8sV1alpha1) NetworkChainings(namespace string) v1alpha1.NetworkCha
 	return &FakeNetworkChainings{c, namespace}
 }
 
+func (c *FakeK8sV1alpha1) NetworkPools() v1alpha1.NetworkPoolInterface {
+	return &FakeNetworkPools{c}
+}
+
 func (c *FakeK8sV1alpha1) ProviderNetworks(namespace string) v1alpha1.ProviderNetworkInterface {
 	return &FakeProviderNetworks{c, namespace}
 }
@@ -18,8 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+type ClusterProviderNetworkExpansion interface{}
+
+type IPReservationExpansion interface{}
+
 type NetworkExpansion interface{}
 
 type NetworkChainingExpansion interface{}
 
+type NetworkPoolExpansion interface{}
+
 type ProviderNetworkExpansion interface{}
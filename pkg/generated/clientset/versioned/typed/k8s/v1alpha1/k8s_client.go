@@ -26,8 +26,11 @@ import (
 
 type K8sV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	ClusterProviderNetworksGetter
+	IPReservationsGetter
 	NetworksGetter
 	NetworkChainingsGetter
+	NetworkPoolsGetter
 	ProviderNetworksGetter
 }
 
@@ -36,6 +39,14 @@ type K8sV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *K8sV1alpha1Client) ClusterProviderNetworks() ClusterProviderNetworkInterface {
+	return newClusterProviderNetworks(c)
+}
+
+func (c *K8sV1alpha1Client) IPReservations(namespace string) IPReservationInterface {
+	return newIPReservations(c, namespace)
+}
+
 func (c *K8sV1alpha1Client) Networks(namespace string) NetworkInterface {
 	return newNetworks(c, namespace)
 }
@@ -44,6 +55,10 @@ func (c *K8sV1alpha1Client) NetworkChainings(namespace string) NetworkChainingIn
 	return newNetworkChainings(c, namespace)
 }
 
+func (c *K8sV1alpha1Client) NetworkPools() NetworkPoolInterface {
+	return newNetworkPools(c)
+}
+
 func (c *K8sV1alpha1Client) ProviderNetworks(namespace string) ProviderNetworkInterface {
 	return newProviderNetworks(c, namespace)
 }
@@ -53,10 +53,16 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=k8s.plugin.opnfv.org, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("clusterprovidernetworks"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.K8s().V1alpha1().ClusterProviderNetworks().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("ipreservations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.K8s().V1alpha1().IPReservations().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("networks"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.K8s().V1alpha1().Networks().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("networkchainings"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.K8s().V1alpha1().NetworkChainings().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("networkpools"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.K8s().V1alpha1().NetworkPools().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("providernetworks"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.K8s().V1alpha1().ProviderNetworks().Informer()}, nil
 
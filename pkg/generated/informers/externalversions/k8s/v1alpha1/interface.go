@@ -24,10 +24,16 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ClusterProviderNetworks returns a ClusterProviderNetworkInformer.
+	ClusterProviderNetworks() ClusterProviderNetworkInformer
+	// IPReservations returns a IPReservationInformer.
+	IPReservations() IPReservationInformer
 	// Networks returns a NetworkInformer.
 	Networks() NetworkInformer
 	// NetworkChainings returns a NetworkChainingInformer.
 	NetworkChainings() NetworkChainingInformer
+	// NetworkPools returns a NetworkPoolInformer.
+	NetworkPools() NetworkPoolInformer
 	// ProviderNetworks returns a ProviderNetworkInformer.
 	ProviderNetworks() ProviderNetworkInformer
 }
@@ -43,6 +49,16 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ClusterProviderNetworks returns a ClusterProviderNetworkInformer.
+func (v *version) ClusterProviderNetworks() ClusterProviderNetworkInformer {
+	return &clusterProviderNetworkInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// IPReservations returns a IPReservationInformer.
+func (v *version) IPReservations() IPReservationInformer {
+	return &iPReservationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Networks returns a NetworkInformer.
 func (v *version) Networks() NetworkInformer {
 	return &networkInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -53,6 +69,11 @@ func (v *version) NetworkChainings() NetworkChainingInformer {
 	return &networkChainingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// NetworkPools returns a NetworkPoolInformer.
+func (v *version) NetworkPools() NetworkPoolInformer {
+	return &networkPoolInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ProviderNetworks returns a ProviderNetworkInformer.
 func (v *version) ProviderNetworks() ProviderNetworkInformer {
 	return &providerNetworkInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
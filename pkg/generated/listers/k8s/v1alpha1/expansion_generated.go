@@ -18,6 +18,18 @@ limitations under the License.
 
 package v1alpha1
 
+// ClusterProviderNetworkListerExpansion allows custom methods to be added to
+// ClusterProviderNetworkLister.
+type ClusterProviderNetworkListerExpansion interface{}
+
+// IPReservationListerExpansion allows custom methods to be added to
+// IPReservationLister.
+type IPReservationListerExpansion interface{}
+
+// IPReservationNamespaceListerExpansion allows custom methods to be added to
+// IPReservationNamespaceLister.
+type IPReservationNamespaceListerExpansion interface{}
+
 // NetworkListerExpansion allows custom methods to be added to
 // NetworkLister.
 type NetworkListerExpansion interface{}
@@ -34,6 +46,10 @@ type NetworkChainingListerExpansion interface{}
 // NetworkChainingNamespaceLister.
 type NetworkChainingNamespaceListerExpansion interface{}
 
+// NetworkPoolListerExpansion allows custom methods to be added to
+// NetworkPoolLister.
+type NetworkPoolListerExpansion interface{}
+
 // ProviderNetworkListerExpansion allows custom methods to be added to
 // ProviderNetworkLister.
 type ProviderNetworkListerExpansion interface{}